@@ -7,11 +7,12 @@ import (
 	"testing"
 
 	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
 )
 
 // if this test fails, CLI flag defaults have drifted from config defaults
 func TestDefaultsAreConsistent(t *testing.T) {
-	configDefaults := config.New().Features
+	configDefaults := config.New(collector.DefaultRegistry).Features
 	flagDefaults := loadConfigFromFlags().Features
 
 	cv := reflect.ValueOf(configDefaults)
@@ -23,8 +24,20 @@ func TestDefaultsAreConsistent(t *testing.T) {
 		expected := cv.Field(i).Interface()
 		actual := fv.Field(i).Interface()
 
-		if expected != actual {
+		if !reflect.DeepEqual(expected, actual) {
 			t.Errorf("feature %s: config default is %v, but flag default is %v", field.Name, expected, actual)
 		}
 	}
 }
+
+func TestTargetsFromFlagsAreTrimmed(t *testing.T) {
+	prev := *sshHosts
+	defer func() { *sshHosts = prev }()
+
+	*sshHosts = "router1, router2"
+	c := loadConfigFromFlags()
+
+	if !reflect.DeepEqual(c.Targets, []string{"router1", "router2"}) {
+		t.Errorf("expected targets [router1 router2], got %q", c.Targets)
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// handleDebugConfigRequest shows the effective config of a target after
+// resolving groups and merging features (secrets are redacted)
+func (a *app) handleDebugConfigRequest(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	d, err := a.exp.DeviceForTarget(target)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if d == nil {
+		http.Error(w, fmt.Sprintf("the target '%s' is not defined in the configuration file", target), http.StatusNotFound)
+		return
+	}
+
+	b, err := a.exp.EffectiveConfigYAML(target)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(b)
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+)
+
+func TestDebugConfig(t *testing.T) {
+	c, err := config.Load(bytes.NewReader([]byte(`
+groups:
+  pe:
+    username: pe
+    password: secret
+    features:
+      bfd: true
+devices:
+  - host: router1
+    groups: [pe]
+`)), false, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := exporter.New(c)
+	assert.NoError(t, err)
+	a := newApp(e, c)
+
+	w := httptest.NewRecorder()
+	a.handleDebugConfigRequest(w, httptest.NewRequest(http.MethodGet, "/debug/config?target=router1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "username: pe")
+	assert.Contains(t, body, "password: <secret>")
+	assert.Contains(t, body, "bfd: true")
+	assert.Contains(t, body, "bgp: true")
+	assert.NotContains(t, body, "secret\n")
+
+	w = httptest.NewRecorder()
+	a.handleDebugConfigRequest(w, httptest.NewRequest(http.MethodGet, "/debug/config?target=router2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	deviceFileLoadSuccessful = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "junos_exporter_device_file_last_load_successful",
+		Help: "Whether the last attempt to load the device file was successful",
+	}, []string{"file"})
+	deviceFileDevices = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "junos_exporter_device_file_devices",
+		Help: "Number of devices loaded from the device file",
+	}, []string{"file"})
+)
+
+// deviceFiles are the device lists referenced by device_files in the config
+type deviceFiles struct {
+	exp   *exporter.Exporter // checks the devices against the registered collectors
+	files map[string]*deviceFile
+}
+
+type deviceFile struct {
+	hash    [sha256.Size]byte
+	devices []*config.DeviceConfig
+	err     error
+
+	// cfg is the config the devices were resolved against (groups, global settings)
+	cfg *config.Config
+}
+
+func newDeviceFiles(e *exporter.Exporter) *deviceFiles {
+	return &deviceFiles{
+		exp:   e,
+		files: make(map[string]*deviceFile),
+	}
+}
+
+// paths returns the files matching the device_files globs of the config
+func (f *deviceFiles) paths(c *config.Config) []string {
+	paths := make([]string, 0)
+	for _, pattern := range c.DeviceFiles {
+		if !filepath.IsAbs(pattern) && *configFile != "" {
+			pattern = filepath.Join(filepath.Dir(*configFile), pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			log.Errorf("Invalid device file pattern %q: %s", pattern, err)
+			continue
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// changed checks whether files were added, removed or modified since the last merge
+func (f *deviceFiles) changed(c *config.Config) bool {
+	paths := f.paths(c)
+	if len(paths) != len(f.files) {
+		return true
+	}
+
+	for _, p := range paths {
+		df, found := f.files[p]
+		if !found {
+			return true
+		}
+
+		h, err := fileHash(p)
+		if err != nil || h != df.hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// devices loads the device files. If a file can not be loaded, the devices of
+// the last successful load of the file are kept.
+func (f *deviceFiles) devices(c *config.Config) []*inventorySource {
+	paths := f.paths(c)
+
+	for p := range f.files {
+		if !slices.Contains(paths, p) {
+			delete(f.files, p)
+			deviceFileLoadSuccessful.DeleteLabelValues(p)
+			deviceFileDevices.DeleteLabelValues(p)
+		}
+	}
+
+	sources := make([]*inventorySource, 0, len(paths))
+	for _, p := range paths {
+		sources = append(sources, &inventorySource{
+			name:    p,
+			devices: f.load(p, c).devices,
+		})
+	}
+
+	return sources
+}
+
+// check loads the device files and returns the errors found
+func (f *deviceFiles) check(c *config.Config) []error {
+	f.devices(c)
+
+	errs := make([]error, 0)
+	for _, p := range slices.Sorted(maps.Keys(f.files)) {
+		if err := f.files[p].err; err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+		}
+	}
+
+	return errs
+}
+
+func (f *deviceFiles) load(path string, c *config.Config) *deviceFile {
+	prev, found := f.files[path]
+	if !found {
+		prev = &deviceFile{}
+		f.files[path] = prev
+	}
+
+	b, err := os.ReadFile(path)
+	if err == nil && prev.err == nil && prev.cfg == c && sha256.Sum256(b) == prev.hash {
+		return prev
+	}
+
+	var devs []*config.DeviceConfig
+	if err == nil {
+		devs, err = f.loadDevices(b, c)
+	}
+
+	if err != nil {
+		log.Errorf("Could not load device file %s: %s", path, err)
+		deviceFileLoadSuccessful.WithLabelValues(path).Set(0)
+		deviceFileDevices.WithLabelValues(path).Set(float64(len(prev.devices)))
+		prev.err = err
+
+		if b != nil {
+			// do not report the same broken file as changed again
+			prev.hash = sha256.Sum256(b)
+		}
+
+		return prev
+	}
+
+	log.Infof("Loaded %d devices from %s", len(devs), path)
+	deviceFileLoadSuccessful.WithLabelValues(path).Set(1)
+	deviceFileDevices.WithLabelValues(path).Set(float64(len(devs)))
+
+	df := &deviceFile{
+		hash:    sha256.Sum256(b),
+		devices: devs,
+		cfg:     c,
+	}
+	f.files[path] = df
+
+	return df
+}
+
+func (f *deviceFiles) loadDevices(b []byte, c *config.Config) ([]*config.DeviceConfig, error) {
+	devs, err := c.LoadDevices(b, *dynamicIfaceLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range devs {
+		err := f.exp.CheckDevice(c, d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return devs, nil
+}
+
+// watchDeviceFiles merges changes of the device files into the running config
+func (a *app) watchDeviceFiles(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.mu.RLock()
+			changed := a.deviceFiles.changed(a.baseCfg)
+			a.mu.RUnlock()
+
+			if !changed {
+				continue
+			}
+
+			a.mu.Lock()
+			err := a.applyInventories()
+			a.mu.Unlock()
+
+			if err != nil {
+				log.Errorf("Could not apply device files: %s", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
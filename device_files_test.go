@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+)
+
+func TestDeviceFiles(t *testing.T) {
+	dir := t.TempDir()
+	fra := filepath.Join(dir, "fra.yml")
+	ber := filepath.Join(dir, "ber.json")
+
+	writeFile(t, fra, "- name: fra1-pe1\n  address: 10.12.0.5\n  labels:\n    site: fra1\n- host: router1\n")
+	writeFile(t, ber, `[{"name": "ber1-pe1", "address": "10.13.0.5"}]`)
+
+	base := &config.Config{
+		Password:    "secret",
+		Devices:     []*config.DeviceConfig{{Host: "router1"}},
+		DeviceFiles: []string{filepath.Join(dir, "*.yml"), filepath.Join(dir, "*.json")},
+	}
+
+	e, err := exporter.New(base)
+	assert.NoError(t, err)
+
+	a := newApp(e, base)
+	f := a.deviceFiles
+	c := a.configWithInventories(base)
+	assert.Equal(t, []string{"router1", "ber1-pe1", "fra1-pe1"}, targetNames(c), "duplicate router1 should be ignored")
+	assert.Equal(t, 1, len(base.Devices), "base config must not be changed")
+	assert.Equal(t, "fra1", c.FindDeviceConfig("fra1-pe1").Labels["site"])
+	assert.False(t, f.changed(base))
+
+	writeFile(t, ber, `[{"name": "ber1-pe1", "usernme": "typo"}]`)
+	assert.True(t, f.changed(base))
+
+	c = a.configWithInventories(base)
+	assert.Equal(t, []string{"router1", "ber1-pe1", "fra1-pe1"}, targetNames(c), "devices of broken file should be kept")
+	assert.Equal(t, 0.0, testutil.ToFloat64(deviceFileLoadSuccessful.WithLabelValues(ber)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(deviceFileLoadSuccessful.WithLabelValues(fra)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(deviceFileDevices.WithLabelValues(fra)))
+	assert.False(t, f.changed(base), "broken file should not be reported as changed again")
+
+	assert.NoError(t, os.Remove(ber))
+	assert.True(t, f.changed(base))
+
+	c = a.configWithInventories(base)
+	assert.Equal(t, []string{"router1", "fra1-pe1"}, targetNames(c))
+	assert.Equal(t, 1, testutil.CollectAndCount(deviceFileLoadSuccessful), "metrics of removed file should be deleted")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func targetNames(c *config.Config) []string {
+	names := make([]string, len(c.Devices))
+	for i, d := range c.Devices {
+		names[i] = d.TargetName()
+	}
+
+	return names
+}
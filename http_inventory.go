@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/internal/inventory"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	httpInventoryCheckInterval = 10 * time.Second
+	httpInventoryTimeout       = time.Minute
+)
+
+var (
+	httpInventoryFetchSuccessful = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "junos_exporter_http_inventory_last_fetch_successful",
+		Help: "Whether the last attempt to fetch the devices from the HTTP inventory was successful",
+	}, []string{"url"})
+	httpInventoryDevices = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "junos_exporter_http_inventory_devices",
+		Help: "Number of devices loaded from the HTTP inventory",
+	}, []string{"url"})
+)
+
+// httpInventories are the HTTP inventories configured in the config (in the order of the config)
+type httpInventories struct {
+	exp         *exporter.Exporter // checks the devices against the registered collectors
+	inventories []*httpInventoryState
+	client      *http.Client
+}
+
+type httpInventoryState struct {
+	cfg       *config.HTTPInventoryConfig
+	source    *inventory.HTTP
+	devices   []inventory.Device
+	lastFetch time.Time
+}
+
+func newHTTPInventories(e *exporter.Exporter) *httpInventories {
+	return &httpInventories{
+		exp:         e,
+		inventories: make([]*httpInventoryState, 0),
+		client: &http.Client{
+			Timeout: httpInventoryTimeout,
+		},
+	}
+}
+
+// sync creates the state for the inventories of the config. Devices fetched before are
+// kept for inventories whose settings are unchanged at the same position in the config.
+func (h *httpInventories) sync(c *config.Config) {
+	inventories := make([]*httpInventoryState, 0, len(c.HTTPInventories))
+	for i, inv := range c.HTTPInventories {
+		if i < len(h.inventories) && reflect.DeepEqual(h.inventories[i].cfg, inv) {
+			inventories = append(inventories, h.inventories[i])
+			continue
+		}
+
+		inventories = append(inventories, &httpInventoryState{
+			cfg:    inv,
+			source: inventory.NewHTTP(inv, h.client),
+		})
+	}
+
+	for _, s := range h.inventories {
+		if !slices.Contains(inventories, s) {
+			httpInventoryFetchSuccessful.DeleteLabelValues(s.cfg.Name())
+			httpInventoryDevices.DeleteLabelValues(s.cfg.Name())
+		}
+	}
+
+	h.inventories = inventories
+}
+
+// devices returns the devices fetched from the inventories
+func (h *httpInventories) devices(c *config.Config) []*inventorySource {
+	sources := make([]*inventorySource, 0, len(h.inventories))
+	for _, s := range h.inventories {
+		if s.devices == nil {
+			continue
+		}
+
+		sources = append(sources, &inventorySource{
+			name:    s.cfg.Name(),
+			devices: h.deviceConfigs(s, c),
+		})
+	}
+
+	return sources
+}
+
+func (h *httpInventories) deviceConfigs(s *httpInventoryState, c *config.Config) []*config.DeviceConfig {
+	devs := s.source.DeviceConfigs(s.devices, c.Groups)
+	err := c.InitDevices(devs, *dynamicIfaceLabels)
+	if err != nil {
+		log.Errorf("Could not use devices of HTTP inventory %s: %s", s.cfg.Name(), err)
+		return nil
+	}
+
+	valid := make([]*config.DeviceConfig, 0, len(devs))
+	for _, d := range devs {
+		err := h.exp.CheckDevice(c, d)
+		if err != nil {
+			log.Errorf("HTTP inventory %s: %s", s.cfg.Name(), err)
+			continue
+		}
+
+		valid = append(valid, d)
+	}
+
+	return valid
+}
+
+// due returns the inventories to refresh
+func (h *httpInventories) due(now time.Time) []*httpInventoryState {
+	due := make([]*httpInventoryState, 0)
+	for _, s := range h.inventories {
+		if now.Sub(s.lastFetch) >= s.cfg.Interval() {
+			due = append(due, s)
+		}
+	}
+
+	return due
+}
+
+// watchHTTPInventories refreshes the HTTP inventories and merges changed device lists into the running config
+func (a *app) watchHTTPInventories(ctx context.Context) {
+	ticker := time.NewTicker(httpInventoryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		a.refreshHTTPInventories(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *app) refreshHTTPInventories(ctx context.Context) {
+	a.mu.RLock()
+	due := a.httpInventory.due(time.Now())
+	a.mu.RUnlock()
+
+	for _, s := range due {
+		devs, err := s.source.Fetch(ctx)
+
+		a.mu.Lock()
+		changed := a.httpInventory.update(s, devs, err)
+		if changed {
+			err = a.applyInventories()
+			if err != nil {
+				log.Errorf("Could not apply HTTP inventory %s: %s", s.cfg.Name(), err)
+			}
+		}
+		a.mu.Unlock()
+	}
+}
+
+// update stores the result of a fetch. It returns whether the device list changed.
+func (h *httpInventories) update(s *httpInventoryState, devs []inventory.Device, err error) bool {
+	s.lastFetch = time.Now()
+
+	if !slices.Contains(h.inventories, s) {
+		// removed from config in the meantime
+		return false
+	}
+
+	if err != nil {
+		log.Errorf("Could not fetch devices from HTTP inventory %s: %s", s.cfg.Name(), err)
+		httpInventoryFetchSuccessful.WithLabelValues(s.cfg.Name()).Set(0)
+		return false
+	}
+
+	httpInventoryFetchSuccessful.WithLabelValues(s.cfg.Name()).Set(1)
+	httpInventoryDevices.WithLabelValues(s.cfg.Name()).Set(float64(len(devs)))
+
+	if reflect.DeepEqual(s.devices, devs) {
+		return false
+	}
+
+	log.Infof("Fetched %d devices from HTTP inventory %s", len(devs), s.cfg.Name())
+	s.devices = devs
+
+	return true
+}
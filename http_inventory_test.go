@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+)
+
+func TestHTTPInventory(t *testing.T) {
+	results := `[{"name": "fra1-pe1", "primary_ip": {"address": "10.12.0.5/32"}, "site": {"slug": "fra1"}, "role": {"slug": "pe"}}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"count": 1, "next": null, "results": %s}`, results)
+	}))
+	defer srv.Close()
+
+	inv := &config.HTTPInventoryConfig{
+		URL:     srv.URL,
+		GroupBy: []string{"role"},
+	}
+	base := &config.Config{
+		Password:        "secret",
+		Devices:         []*config.DeviceConfig{{Host: "router1"}},
+		HTTPInventories: []*config.HTTPInventoryConfig{inv},
+		Groups: map[string]*config.GroupConfig{
+			"pe": {Username: "pe"},
+		},
+	}
+
+	e, err := exporter.New(base)
+	assert.NoError(t, err)
+
+	a := newApp(e, base)
+	assert.Equal(t, []string{"router1"}, targetNames(a.configWithInventories(base)), "devices before first fetch")
+
+	a.refreshHTTPInventories(context.Background())
+	assert.Equal(t, []string{"router1", "fra1-pe1"}, targetNames(e.Config()), "devices after fetch")
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpInventoryFetchSuccessful.WithLabelValues(srv.URL)))
+
+	dc := e.Config().FindDeviceConfig("fra1-pe1")
+	assert.Equal(t, "pe", dc.Username, "username of group pe")
+	assert.Equal(t, map[string]string{"site": "fra1", "role": "pe"}, dc.Labels)
+
+	d, err := e.DeviceForTarget("fra1-pe1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.12.0.5", d.Address)
+
+	s := a.httpInventory.inventories[0]
+	assert.Empty(t, a.httpInventory.due(s.lastFetch), "not due before the refresh interval")
+
+	srv.Close()
+	s.lastFetch = s.lastFetch.Add(-inv.Interval())
+	a.refreshHTTPInventories(context.Background())
+	assert.Equal(t, 0.0, testutil.ToFloat64(httpInventoryFetchSuccessful.WithLabelValues(srv.URL)))
+	assert.Equal(t, []string{"router1", "fra1-pe1"}, targetNames(e.Config()), "devices should be kept if the inventory is unavailable")
+}
+
+func TestHTTPInventorySync(t *testing.T) {
+	h := newHTTPInventories(nil)
+	a := &config.HTTPInventoryConfig{URL: "https://netbox.example.com/api/dcim/devices/?token=secret"}
+	b := &config.HTTPInventoryConfig{URL: "https://inventory.example.com/devices"}
+
+	h.sync(&config.Config{HTTPInventories: []*config.HTTPInventoryConfig{a, b}})
+	assert.Len(t, h.inventories, 2)
+	first := h.inventories[0]
+
+	h.sync(&config.Config{HTTPInventories: []*config.HTTPInventoryConfig{{URL: a.URL}, b}})
+	assert.Same(t, first, h.inventories[0], "state is kept if the settings are unchanged")
+
+	h.sync(&config.Config{HTTPInventories: []*config.HTTPInventoryConfig{{URL: a.URL, Groups: []string{"pe"}}}})
+	assert.Len(t, h.inventories, 1)
+	assert.NotSame(t, first, h.inventories[0], "state is replaced if the settings changed")
+}
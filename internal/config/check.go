@@ -0,0 +1,412 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
+)
+
+// CheckError is a problem found while checking a config file
+type CheckError struct {
+	Line int
+	Path string
+	Err  error
+}
+
+func (e *CheckError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Path, e.Err)
+}
+
+func (e *CheckError) Unwrap() error {
+	return e.Err
+}
+
+// Check decodes a config file strictly and validates it, features are validated against the collectors of the registry.
+// In contrast to Load it does not stop at the first problem, all problems found are returned.
+func Check(b []byte, reg *collector.Registry) []error {
+	c := New(reg)
+	err := unmarshalStrict(b, c)
+	if err != nil {
+		return []error{err}
+	}
+
+	var root yaml.Node
+	err = yaml.Unmarshal(b, &root)
+	if err != nil {
+		return []error{err}
+	}
+
+	ch := &checker{root: &root, reg: reg}
+	ch.checkConfig(c)
+
+	return ch.errs
+}
+
+type checker struct {
+	root *yaml.Node
+	reg  *collector.Registry
+	errs []error
+}
+
+func (ch *checker) fail(err error, path ...any) {
+	ch.errs = append(ch.errs, &CheckError{
+		Line: lineOf(ch.root, path...),
+		Path: pathString(path),
+		Err:  err,
+	})
+}
+
+func (ch *checker) checkConfig(c *Config) {
+	ch.checkRegex(c.IfDescRegStr, "interface_description_regex")
+	ch.checkRegex(c.InterfaceNameRegex, "interface_name_regex")
+	ch.checkRegex(c.FirewallFilterNameRegex, "firewall_filter_name_regex")
+	ch.checkSRGIDs(c.MNHASRGIDs, "mnha_srg_ids")
+	ch.checkFeatures(&c.Features, "features")
+	ch.checkCollectorOptions(c.CollectorOptions, "collector_options")
+	ch.checkRelabelConfigs(c.MetricRelabelConfigs, "metric_relabel_configs")
+	ch.checkSeriesLimits(c.SeriesLimits, "series_limits")
+
+	for i, pattern := range c.DeviceFiles {
+		if _, err := filepath.Glob(pattern); err != nil {
+			ch.fail(err, "device_files", i)
+		}
+	}
+
+	for i, inv := range c.HTTPInventories {
+		ch.checkHTTPInventory(inv, c, i)
+	}
+
+	for _, name := range sortedKeys(c.Credentials) {
+		ch.checkCredentials(c.Credentials[name], name)
+	}
+
+	if err := c.RemoteWrite.check(); err != nil {
+		ch.fail(err, "remote_write")
+	}
+
+	if err := c.OTLPMetrics.check(); err != nil {
+		ch.fail(err, "otlp_metrics")
+	}
+
+	if err := c.DebugRPC.check(); err != nil {
+		ch.fail(err, "debug_rpc")
+	}
+
+	for _, name := range sortedKeys(c.Groups) {
+		ch.checkGroup(c.Groups[name], c, name)
+	}
+
+	hosts := make(map[string]int)
+	for i, d := range c.Devices {
+		ch.checkDevice(d, i)
+
+		for j, name := range d.Groups {
+			if _, found := c.Groups[name]; !found {
+				ch.fail(fmt.Errorf("unknown group %q", name), "devices", i, "groups", j)
+			}
+		}
+
+		ch.checkCredentialsRef(d.Credentials, c, "devices", i, "credentials")
+
+		name, key := d.TargetName(), "host"
+		if name != d.Host {
+			key = "name"
+		}
+
+		if prev, found := hosts[name]; found {
+			ch.fail(fmt.Errorf("duplicate host %q (already defined at line %d)", name, lineOf(ch.root, "devices", prev)), "devices", i, key)
+			continue
+		}
+
+		hosts[name] = i
+	}
+}
+
+func (ch *checker) checkDevice(d *DeviceConfig, i int) {
+	if d.Host == "" && d.Name == "" {
+		ch.fail(fmt.Errorf("host or name must be set"), "devices", i)
+	}
+
+	if d.IsHostPattern {
+		ch.checkRegex(d.Host, "devices", i, "host")
+
+		if d.Name != "" {
+			ch.fail(fmt.Errorf("name can not be used with host_pattern (the target is used as name)"), "devices", i, "name")
+		}
+	}
+
+	if d.Name != "" && d.DiscoverName {
+		ch.fail(fmt.Errorf("name and discover_name are mutually exclusive"), "devices", i, "discover_name")
+	}
+
+	ch.checkRegex(d.IfDescRegStr, "devices", i, "interface_description_regex")
+	ch.checkRegex(d.InterfaceNameRegex, "devices", i, "interface_name_regex")
+	ch.checkRegex(d.FirewallFilterNameRegex, "devices", i, "firewall_filter_name_regex")
+	ch.checkSRGIDs(d.MNHASRGIDs, "devices", i, "mnha_srg_ids")
+	ch.checkLabels(d.Labels, "devices", i, "labels")
+	ch.checkFeatures(d.Features, "devices", i, "features")
+	ch.checkCollectorOptions(d.CollectorOptions, "devices", i, "collector_options")
+	ch.checkRelabelConfigs(d.MetricRelabelConfigs, "devices", i, "metric_relabel_configs")
+	ch.checkSeriesLimits(d.SeriesLimits, "devices", i, "series_limits")
+
+	if err := CheckKeyFile(d.KeyFile); err != nil {
+		ch.fail(err, "devices", i, "key_file")
+	}
+}
+
+// CheckKeyFile checks whether the SSH key file at path is readable (an empty path is valid)
+func CheckKeyFile(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("key file is not readable: %w", err)
+	}
+
+	return f.Close()
+}
+
+func (ch *checker) checkHTTPInventory(inv *HTTPInventoryConfig, c *Config, i int) {
+	if inv == nil || inv.URL == "" {
+		ch.fail(fmt.Errorf("url must be set"), "http_inventories", i)
+		return
+	}
+
+	if _, err := url.Parse(inv.URL); err != nil {
+		ch.fail(err, "http_inventories", i, "url")
+	}
+
+	if inv.Token != "" && inv.TokenFile != "" {
+		ch.fail(fmt.Errorf("token and token_file are mutually exclusive"), "http_inventories", i, "token_file")
+	}
+
+	for j, name := range inv.Groups {
+		if _, found := c.Groups[name]; !found {
+			ch.fail(fmt.Errorf("unknown group %q", name), "http_inventories", i, "groups", j)
+		}
+	}
+
+	for j, field := range inv.GroupBy {
+		if !slices.Contains(HTTPInventoryFields, field) {
+			ch.fail(fmt.Errorf("unknown field %q (supported: %s)", field, strings.Join(HTTPInventoryFields, ", ")), "http_inventories", i, "group_by", j)
+		}
+	}
+
+	for _, name := range sortedKeys(inv.Labels) {
+		if err := checkLabelName(name); err != nil {
+			ch.fail(err, "http_inventories", i, "labels", name)
+		}
+
+		if field := inv.Labels[name]; !slices.Contains(HTTPInventoryFields, field) {
+			ch.fail(fmt.Errorf("unknown field %q (supported: %s)", field, strings.Join(HTTPInventoryFields, ", ")), "http_inventories", i, "labels", name)
+		}
+	}
+}
+
+func (ch *checker) checkGroup(g *GroupConfig, c *Config, name string) {
+	if g == nil {
+		return
+	}
+
+	ch.checkCredentialsRef(g.Credentials, c, "groups", name, "credentials")
+
+	ch.checkRegex(g.IfDescRegStr, "groups", name, "interface_description_regex")
+	ch.checkRegex(g.InterfaceNameRegex, "groups", name, "interface_name_regex")
+	ch.checkRegex(g.FirewallFilterNameRegex, "groups", name, "firewall_filter_name_regex")
+	ch.checkLabels(g.Labels, "groups", name, "labels")
+	ch.checkFeatures(g.Features, "groups", name, "features")
+	ch.checkCollectorOptions(g.CollectorOptions, "groups", name, "collector_options")
+	ch.checkRelabelConfigs(g.MetricRelabelConfigs, "groups", name, "metric_relabel_configs")
+	ch.checkSeriesLimits(g.SeriesLimits, "groups", name, "series_limits")
+
+	if err := CheckKeyFile(g.KeyFile); err != nil {
+		ch.fail(err, "groups", name, "key_file")
+	}
+}
+
+func (ch *checker) checkCredentials(p *CredentialsConfig, name string) {
+	if p == nil {
+		ch.fail(fmt.Errorf("password or key must be set"), "credentials", name)
+		return
+	}
+
+	if err := p.check(); err != nil {
+		ch.fail(err, "credentials", name)
+	}
+
+	sources := map[string]*SecretSource{
+		"password":       p.Password,
+		"key":            p.Key,
+		"key_passphrase": p.KeyPassphrase,
+	}
+	for _, key := range sortedKeys(sources) {
+		if s := sources[key]; s != nil {
+			if err := s.check(); err != nil {
+				ch.fail(err, "credentials", name, key)
+			}
+		}
+	}
+}
+
+func (ch *checker) checkCredentialsRef(name string, c *Config, path ...any) {
+	if name == "" {
+		return
+	}
+
+	if _, found := c.Credentials[name]; !found {
+		ch.fail(fmt.Errorf("unknown credentials %q", name), path...)
+	}
+}
+
+func (ch *checker) checkFeatures(f *FeatureConfig, path ...any) {
+	if f == nil {
+		return
+	}
+
+	for _, name := range sortedKeys(f.Extra) {
+		if _, found := ch.reg.RegistrationForFeature(name); !found {
+			ch.fail(fmt.Errorf("unknown feature %q", name), append(path, name)...)
+		}
+	}
+}
+
+func (ch *checker) checkCollectorOptions(o *CollectorOptions, path ...any) {
+	if o == nil {
+		return
+	}
+
+	if o.Alarm != nil {
+		ch.checkRegex(o.Alarm.Filter, append(path, "alarm", "filter")...)
+	}
+
+	if o.Firewall != nil {
+		ch.checkRegex(o.Firewall.FilterNameRegex, append(path, "firewall", "filter_name_regex")...)
+	}
+
+	if o.Interfaces != nil {
+		ch.checkRegex(o.Interfaces.NameRegex, append(path, "interfaces", "name_regex")...)
+	}
+}
+
+func (ch *checker) checkRelabelConfigs(rules []*relabel.Config, path ...any) {
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			ch.fail(err, append(path, i)...)
+		}
+	}
+}
+
+func (ch *checker) checkSeriesLimits(l *SeriesLimitsConfig, path ...any) {
+	if err := l.check(ch.reg); err != nil {
+		ch.fail(err, path...)
+	}
+}
+
+func (ch *checker) checkLabels(labels map[string]string, path ...any) {
+	for _, name := range sortedKeys(labels) {
+		if err := checkLabelName(name); err != nil {
+			ch.fail(err, append(path, name)...)
+		}
+	}
+}
+
+func (ch *checker) checkRegex(expr string, path ...any) {
+	if expr == "" {
+		return
+	}
+
+	_, err := regexp.Compile(expr)
+	if err != nil {
+		ch.fail(err, path...)
+	}
+}
+
+func (ch *checker) checkSRGIDs(ids string, path ...any) {
+	for _, part := range strings.Split(ids, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(part)
+		if err != nil || id < 0 {
+			ch.fail(fmt.Errorf("invalid services-redundancy-group ID %q", part), path...)
+		}
+	}
+}
+
+// lineOf returns the line of the node addressed by path (map keys and sequence indexes).
+// If the path does not exist the line of the deepest existing node is returned.
+func lineOf(root *yaml.Node, path ...any) int {
+	n := root
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+
+	for _, p := range path {
+		next := childOf(n, p)
+		if next == nil {
+			break
+		}
+
+		n = next
+	}
+
+	return n.Line
+}
+
+func childOf(n *yaml.Node, p any) *yaml.Node {
+	switch k := p.(type) {
+	case string:
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == k {
+				return n.Content[i+1]
+			}
+		}
+	case int:
+		if n.Kind == yaml.SequenceNode && k < len(n.Content) {
+			return n.Content[k]
+		}
+	}
+
+	return nil
+}
+
+func pathString(path []any) string {
+	var b strings.Builder
+	for _, p := range path {
+		switch k := p.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(k)
+		case int:
+			fmt.Fprintf(&b, "[%d]", k)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+func TestCheckShouldAcceptValidConfig(t *testing.T) {
+	b, err := os.ReadFile("tests/config1.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, Check(b, collector.DefaultRegistry))
+}
+
+func TestCheckShouldRejectUnknownFields(t *testing.T) {
+	b, err := os.ReadFile("tests/config8.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := Check(b, collector.DefaultRegistry)
+	assert.Equal(t, 1, len(errs), "error count")
+	assert.Contains(t, errs[0].Error(), "line 3: field featurs not found")
+}
+
+func TestCheckShouldReportAllProblems(t *testing.T) {
+	b, err := os.ReadFile("tests/config7.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := Check(b, collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 6, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], "line 1: interface_name_regex: error parsing regexp")
+	assert.Contains(t, msgs[1], `line 2: mnha_srg_ids: invalid services-redundancy-group ID "x"`)
+	assert.Contains(t, msgs[2], "line 5: devices[0].key_file: key file is not readable")
+	assert.Contains(t, msgs[3], "line 6: devices[1].host: error parsing regexp")
+	assert.Contains(t, msgs[4], "line 9: devices[2].firewall_filter_name_regex: error parsing regexp")
+	assert.Contains(t, msgs[5], `line 8: devices[2].host: duplicate host "router1" (already defined at line 4)`)
+}
+
+func TestCheckShouldRejectUnreadableGroupKeyFile(t *testing.T) {
+	errs := Check([]byte("groups:\n  pe:\n    key_file: /nonexistent/id_rsa\n"), collector.DefaultRegistry)
+
+	if assert.Equal(t, 1, len(errs), "error count") {
+		assert.Contains(t, errs[0].Error(), "line 3: groups.pe.key_file: key file is not readable")
+	}
+}
+
+func TestCheckShouldRejectUnknownGroups(t *testing.T) {
+	errs := Check([]byte("groups:\n  pe:\n    interface_name_regex: '('\ndevices:\n  - host: router1\n    groups: [pe, missing]\n"), collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 2, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], "line 3: groups.pe.interface_name_regex: error parsing regexp")
+	assert.Contains(t, msgs[1], `line 6: devices[0].groups[1]: unknown group "missing"`)
+}
+
+func TestCheckShouldRejectInvalidLabels(t *testing.T) {
+	errs := Check([]byte("groups:\n  pe:\n    labels:\n      __meta: x\ndevices:\n  - host: router1\n    labels:\n      target: x\n      site-name: x\n"), collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 3, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], `line 4: groups.pe.labels.__meta: label name "__meta" is reserved for internal use`)
+	assert.Contains(t, msgs[1], `line 9: devices[0].labels.site-name: invalid label name "site-name"`)
+	assert.Contains(t, msgs[2], `line 8: devices[0].labels.target: label name "target" is used by the exporter`)
+}
+
+func TestCheckDeviceNames(t *testing.T) {
+	errs := Check([]byte("devices:\n  - name: pe1\n    address: 10.0.0.1\n  - host: pe1\n  - host: sw.*\n    host_pattern: true\n    name: sw\n  - name: pe2\n    discover_name: true\n  - address: 10.0.0.2\n"), collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 4, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], `line 4: devices[1].host: duplicate host "pe1" (already defined at line 2)`)
+	assert.Contains(t, msgs[1], "line 7: devices[2].name: name can not be used with host_pattern")
+	assert.Contains(t, msgs[2], "line 9: devices[3].discover_name: name and discover_name are mutually exclusive")
+	assert.Contains(t, msgs[3], "line 10: devices[4]: host or name must be set")
+}
+
+func TestCheckCredentials(t *testing.T) {
+	errs := Check([]byte(`credentials:
+  a:
+    username: a
+  b:
+    password:
+      env: X
+      file: /x
+  c:
+    key:
+      exec: [vault, read]
+      ttl: 1m
+    key_passphrase:
+      env: X
+      ttl: 1m
+devices:
+  - host: router1
+    credentials: missing
+`), collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 4, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], "line 3: credentials.a: password or key must be set")
+	assert.Contains(t, msgs[1], "line 6: credentials.b.password: exactly one of env, file and exec must be set")
+	assert.Contains(t, msgs[2], "line 13: credentials.c.key_passphrase: ttl can only be used with exec")
+	assert.Contains(t, msgs[3], `line 17: devices[0].credentials: unknown credentials "missing"`)
+}
+
+func TestCheckCollectorOptions(t *testing.T) {
+	errs := Check([]byte("collector_options:\n  alarm:\n    filter: '('\ngroups:\n  pe:\n    collector_options:\n      interfaces:\n        name_regex: '['\n"), collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 2, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], "line 3: collector_options.alarm.filter: error parsing regexp")
+	assert.Contains(t, msgs[1], "line 8: groups.pe.collector_options.interfaces.name_regex: error parsing regexp")
+}
+
+func TestCheckRemoteWrite(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{
+			config:   "remote_write:\n  scrape_interval: 30s\n",
+			expected: "line 2: remote_write: url must be set",
+		},
+		{
+			config:   "remote_write:\n  url: http://localhost/api/v1/write\n  bearer_token: x\n  bearer_token_file: /x\n",
+			expected: "line 2: remote_write: bearer_token and bearer_token_file are mutually exclusive",
+		},
+		{
+			config:   "remote_write:\n  url: http://localhost/api/v1/write\n  bearer_token: x\n  basic_auth:\n    username: x\n",
+			expected: "line 2: remote_write: basic_auth and bearer_token are mutually exclusive",
+		},
+		{
+			config:   "remote_write:\n  url: http://localhost/api/v1/write\n  external_labels:\n    site-name: x\n",
+			expected: `line 2: remote_write: external_labels: invalid label name "site-name"`,
+		},
+	}
+
+	for _, test := range tests {
+		errs := Check([]byte(test.config), collector.DefaultRegistry)
+		if assert.Equal(t, 1, len(errs), "error count: %v", errs) {
+			assert.Contains(t, errs[0].Error(), test.expected)
+		}
+	}
+}
+
+func TestCheckOTLPMetrics(t *testing.T) {
+	errs := Check([]byte("otlp_metrics:\n  endpoint: otel-collector:4317\n  protocol: thrift\n"), collector.DefaultRegistry)
+	if assert.Equal(t, 1, len(errs), "error count: %v", errs) {
+		assert.Contains(t, errs[0].Error(), `line 2: otlp_metrics: invalid protocol "thrift" (valid: grpc, http)`)
+	}
+
+	errs = Check([]byte("otlp_metrics:\n  protocol: http\n"), collector.DefaultRegistry)
+	if assert.Equal(t, 1, len(errs), "error count: %v", errs) {
+		assert.Contains(t, errs[0].Error(), "line 2: otlp_metrics: endpoint must be set")
+	}
+}
+
+func TestCheckMetricRelabelConfigs(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{
+			config:   "metric_relabel_configs:\n  - source_labels: [__name__]\n    regex: '('\n    action: drop\n",
+			expected: `line 2: metric_relabel_configs[0]: invalid regex "("`,
+		},
+		{
+			config:   "groups:\n  edge:\n    metric_relabel_configs:\n      - action: drop\n",
+			expected: "line 4: groups.edge.metric_relabel_configs[0]: source_labels must be set for action drop",
+		},
+		{
+			config:   "devices:\n  - host: router1\n    metric_relabel_configs:\n      - source_labels: [__name__]\n        action: hashmod\n",
+			expected: `line 4: devices[0].metric_relabel_configs[0]: unknown action "hashmod"`,
+		},
+	}
+
+	for _, test := range tests {
+		errs := Check([]byte(test.config), collector.DefaultRegistry)
+		if assert.Equal(t, 1, len(errs), "error count: %v", errs) {
+			assert.Contains(t, errs[0].Error(), test.expected)
+		}
+	}
+}
+
+func TestCheckSeriesLimits(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{
+			config:   "series_limits:\n  max_series: -1\n",
+			expected: "line 2: series_limits: max_series must not be negative",
+		},
+		{
+			config:   "series_limits:\n  action: sample\n",
+			expected: `line 2: series_limits: invalid action "sample" (valid: drop, truncate)`,
+		},
+		{
+			config:   "groups:\n  bng:\n    series_limits:\n      collectors:\n        subscribers: 1000\n",
+			expected: `line 4: groups.bng.series_limits: collectors: unknown feature "subscribers"`,
+		},
+		{
+			config:   "devices:\n  - host: bng1\n    series_limits:\n      collectors:\n        subscriber: -5\n",
+			expected: "line 4: devices[0].series_limits: collectors: subscriber: limit must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		errs := Check([]byte(test.config), collector.DefaultRegistry)
+		if assert.Equal(t, 1, len(errs), "error count: %v", errs) {
+			assert.Contains(t, errs[0].Error(), test.expected)
+		}
+	}
+}
+
+func TestCheckDebugRPC(t *testing.T) {
+	errs := Check([]byte("debug_rpc:\n  allowed_commands:\n    - 'show ('\n"), collector.DefaultRegistry)
+	if assert.Equal(t, 1, len(errs), "error count: %v", errs) {
+		assert.Contains(t, errs[0].Error(), `line 2: debug_rpc: allowed_commands: invalid regex "show ("`)
+	}
+}
+
+func TestCheckShouldRejectPassLabels(t *testing.T) {
+	errs := Check([]byte("devices:\n  - host: router1\n    logical_systems: [ls1]\n    labels:\n      logical_system: x\n      routing_instance: x\n"), collector.DefaultRegistry)
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	assert.Equal(t, 2, len(msgs), "error count: %v", msgs)
+	assert.Contains(t, msgs[0], `line 5: devices[0].labels.logical_system: label name "logical_system" is used by the exporter`)
+	assert.Contains(t, msgs[1], `line 6: devices[0].labels.routing_instance: label name "routing_instance" is used by the exporter`)
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/czerwonk/junos_exporter/pkg/features/alarm"
+	"github.com/czerwonk/junos_exporter/pkg/features/firewall"
+	"github.com/czerwonk/junos_exporter/pkg/features/interfaces"
+	"github.com/czerwonk/junos_exporter/pkg/features/mnha"
+)
+
+// CollectorOptions are the options of the collectors, keyed by collector name in the config
+type CollectorOptions struct {
+	Alarm      *alarm.Options      `yaml:"alarm,omitempty"`
+	Firewall   *firewall.Options   `yaml:"firewall,omitempty"`
+	Interfaces *interfaces.Options `yaml:"interfaces,omitempty"`
+	MNHA       *mnha.Options       `yaml:"mnha,omitempty"`
+}
+
+// legacyCollectorOptions returns the collector options set by the fields predating collector_options
+func legacyCollectorOptions(interfaceNameRegex, firewallFilterNameRegex, srgIDs string) *CollectorOptions {
+	o := &CollectorOptions{}
+
+	if interfaceNameRegex != "" {
+		o.Interfaces = &interfaces.Options{NameRegex: interfaceNameRegex}
+	}
+
+	if firewallFilterNameRegex != "" {
+		o.Firewall = &firewall.Options{FilterNameRegex: firewallFilterNameRegex}
+	}
+
+	if srgIDs != "" {
+		o.MNHA = &mnha.Options{SRGIDs: mnha.ParseSRGIDs(srgIDs)}
+	}
+
+	return o
+}
+
+// overlayCollectorOptions sets the options set in src in dst (option by option)
+func overlayCollectorOptions(dst, src *CollectorOptions) {
+	if src == nil {
+		return
+	}
+
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src).Elem()
+
+	for i := 0; i < dv.NumField(); i++ {
+		s := sv.Field(i)
+		if s.IsNil() {
+			continue
+		}
+
+		d := dv.Field(i)
+		if d.IsNil() {
+			d.Set(reflect.New(d.Type().Elem()))
+		}
+
+		for j := 0; j < s.Elem().NumField(); j++ {
+			if f := s.Elem().Field(j); !f.IsZero() {
+				d.Elem().Field(j).Set(f)
+			}
+		}
+	}
+}
+
+// withDefaults makes sure the options of every collector are set
+func (o *CollectorOptions) withDefaults() *CollectorOptions {
+	v := reflect.ValueOf(o).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+	}
+
+	return o
+}
+
+func (o *CollectorOptions) check() error {
+	if o == nil {
+		return nil
+	}
+
+	regexes := make(map[string]string)
+	if o.Alarm != nil {
+		regexes["alarm.filter"] = o.Alarm.Filter
+	}
+	if o.Firewall != nil {
+		regexes["firewall.filter_name_regex"] = o.Firewall.FilterNameRegex
+	}
+	if o.Interfaces != nil {
+		regexes["interfaces.name_regex"] = o.Interfaces.NameRegex
+	}
+
+	errs := make([]string, 0)
+	for _, name := range sortedKeys(regexes) {
+		if _, err := regexp.Compile(regexes[name]); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid collector options: %s", strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// globalCollectorOptions returns the collector options set on the top level of the config
+func (c *Config) globalCollectorOptions() *CollectorOptions {
+	o := legacyCollectorOptions(c.InterfaceNameRegex, c.FirewallFilterNameRegex, c.MNHASRGIDs)
+	overlayCollectorOptions(o, c.CollectorOptions)
+
+	return o
+}
+
+// CollectorOptionsForDevice returns the collector options of a device after merging the global, group and device options.
+// Options of all collectors are set in the result (options not configured are empty).
+func (c *Config) CollectorOptionsForDevice(d *DeviceConfig) *CollectorOptions {
+	if d != nil && d.effectiveOptions != nil {
+		return d.effectiveOptions
+	}
+
+	o := c.globalCollectorOptions()
+	if d != nil {
+		overlayCollectorOptions(o, legacyCollectorOptions(d.InterfaceNameRegex, d.FirewallFilterNameRegex, d.MNHASRGIDs))
+		overlayCollectorOptions(o, d.CollectorOptions)
+	}
+
+	return o.withDefaults()
+}
+
+// resolveCollectorOptions merges the collector options in the order global -> groups -> device.
+// It has to be called before the settings of the groups are inherited by the device.
+func (c *Config) resolveCollectorOptions(d *DeviceConfig, groups []*GroupConfig) {
+	o := c.globalCollectorOptions()
+	for _, g := range groups {
+		overlayCollectorOptions(o, legacyCollectorOptions(g.InterfaceNameRegex, g.FirewallFilterNameRegex, ""))
+		overlayCollectorOptions(o, g.CollectorOptions)
+	}
+	overlayCollectorOptions(o, legacyCollectorOptions(d.InterfaceNameRegex, d.FirewallFilterNameRegex, d.MNHASRGIDs))
+	overlayCollectorOptions(o, d.CollectorOptions)
+
+	d.effectiveOptions = o.withDefaults()
+}
+
+// ForCollector returns the options of the collector of a feature (nil if the collector has no options)
+func (o *CollectorOptions) ForCollector(feature string) any {
+	v := reflect.ValueOf(o).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != feature {
+			continue
+		}
+
+		if v.Field(i).IsNil() {
+			return nil
+		}
+
+		return v.Field(i).Elem().Interface()
+	}
+
+	return nil
+}
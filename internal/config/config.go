@@ -3,25 +3,53 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 
-	"gopkg.in/yaml.v2"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
 )
 
 // Config represents the configuration for the exporter
 type Config struct {
-	Password                string          `yaml:"password"`
-	Targets                 []string        `yaml:"targets,omitempty"`
-	Devices                 []*DeviceConfig `yaml:"devices,omitempty"`
-	Features                FeatureConfig   `yaml:"features,omitempty"`
-	LSEnabled               bool            `yaml:"logical_systems,omitempty"`
-	IfDescRegStr            string          `yaml:"interface_description_regex,omitempty"`
-	IfDescReg               *regexp.Regexp  `yaml:"-"`
-	InterfaceNameRegex      string          `yaml:"interface_name_regex,omitempty"`
-	FirewallFilterNameRegex string          `yaml:"firewall_filter_name_regex,omitempty"`
-	MNHASRGIDs              string          `yaml:"mnha_srg_ids,omitempty"`
+	Password                string                        `yaml:"password"`
+	Targets                 []string                      `yaml:"targets,omitempty"`
+	Devices                 []*DeviceConfig               `yaml:"devices,omitempty"`
+	Groups                  map[string]*GroupConfig       `yaml:"groups,omitempty"`
+	Features                FeatureConfig                 `yaml:"features,omitempty"`
+	LSEnabled               bool                          `yaml:"logical_systems,omitempty"`
+	IfDescRegStr            string                        `yaml:"interface_description_regex,omitempty"`
+	IfDescReg               *regexp.Regexp                `yaml:"-"`
+	InterfaceNameRegex      string                        `yaml:"interface_name_regex,omitempty"`
+	FirewallFilterNameRegex string                        `yaml:"firewall_filter_name_regex,omitempty"`
+	MNHASRGIDs              string                        `yaml:"mnha_srg_ids,omitempty"`
+	DeviceFiles             []string                      `yaml:"device_files,omitempty"`
+	HTTPInventories         []*HTTPInventoryConfig        `yaml:"http_inventories,omitempty"`
+	Credentials             map[string]*CredentialsConfig `yaml:"credentials,omitempty"`
+	CollectorOptions        *CollectorOptions             `yaml:"collector_options,omitempty"`
+	RemoteWrite             *RemoteWriteConfig            `yaml:"remote_write,omitempty"`
+	OTLPMetrics             *OTLPMetricsConfig            `yaml:"otlp_metrics,omitempty"`
+	MetricRelabelConfigs    []*relabel.Config             `yaml:"metric_relabel_configs,omitempty"`
+	SeriesLimits            *SeriesLimitsConfig           `yaml:"series_limits,omitempty"`
+	DebugRPC                *DebugRPCConfig               `yaml:"debug_rpc,omitempty"`
+
+	// registry is the collector registry the features are validated against
+	registry *collector.Registry
+}
+
+// collectorRegistry returns the registry of the config (the default registry for configs not created by New or Load)
+func (c *Config) collectorRegistry() *collector.Registry {
+	if c.registry == nil {
+		return collector.DefaultRegistry
+	}
+
+	return c.registry
 }
 
 func (c *Config) load(dynamicIfaceLabels bool) error {
@@ -34,15 +62,123 @@ func (c *Config) load(dynamicIfaceLabels bool) error {
 		c.IfDescReg = re
 	}
 
-	for _, d := range c.Devices {
-		if d.IfDescRegStr != "" && dynamicIfaceLabels {
-			re, err := regexp.Compile(d.IfDescRegStr)
+	reg := c.collectorRegistry()
+
+	err := c.Features.check(reg)
+	if err != nil {
+		return err
+	}
+
+	err = c.CollectorOptions.check()
+	if err != nil {
+		return err
+	}
+
+	err = checkRelabelConfigs(c.MetricRelabelConfigs)
+	if err != nil {
+		return err
+	}
+
+	err = checkSeriesLimits(c.SeriesLimits, reg)
+	if err != nil {
+		return err
+	}
+
+	err = c.RemoteWrite.check()
+	if err != nil {
+		return fmt.Errorf("remote_write: %w", err)
+	}
+
+	err = c.OTLPMetrics.check()
+	if err != nil {
+		return fmt.Errorf("otlp_metrics: %w", err)
+	}
+
+	err = c.DebugRPC.check()
+	if err != nil {
+		return fmt.Errorf("debug_rpc: %w", err)
+	}
+
+	for _, name := range sortedKeys(c.Groups) {
+		if g := c.Groups[name]; g != nil {
+			err := g.Features.check(reg)
+			if err == nil {
+				err = g.CollectorOptions.check()
+			}
+
+			if err == nil {
+				err = checkRelabelConfigs(g.MetricRelabelConfigs)
+			}
+
+			if err == nil {
+				err = checkSeriesLimits(g.SeriesLimits, reg)
+			}
+
 			if err != nil {
-				return fmt.Errorf("unable to compile interface description regex %q: %w", d.IfDescRegStr, err)
+				return fmt.Errorf("group %s: %w", name, err)
 			}
+		}
+	}
+
+	for _, d := range c.Devices {
+		err := c.loadDevice(d, dynamicIfaceLabels)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) loadDevice(d *DeviceConfig, dynamicIfaceLabels bool) error {
+	err := c.resolveGroupsForDevice(d)
+	if err != nil {
+		return err
+	}
+
+	err = checkDeviceLabels(d)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CredentialsForDevice(d)
+	if err != nil {
+		return err
+	}
+
+	err = d.Features.check(c.collectorRegistry())
+	if err == nil {
+		err = d.CollectorOptions.check()
+	}
+
+	if err == nil {
+		err = checkRelabelConfigs(d.MetricRelabelConfigs)
+	}
+
+	if err == nil {
+		err = checkSeriesLimits(d.SeriesLimits, c.collectorRegistry())
+	}
+
+	if err != nil {
+		return fmt.Errorf("device %s: %w", d.TargetName(), err)
+	}
 
-			d.IfDescReg = re
+	if d.IfDescRegStr != "" && dynamicIfaceLabels {
+		re, err := regexp.Compile(d.IfDescRegStr)
+		if err != nil {
+			return fmt.Errorf("unable to compile interface description regex %q: %w", d.IfDescRegStr, err)
+		}
+
+		d.IfDescReg = re
+	}
+
+	if d.IsHostPattern {
+		hostPattern, err := regexp.Compile(d.Host)
+		if err != nil {
+			return err
 		}
+
+		d.HostPattern = hostPattern
 	}
 
 	return nil
@@ -50,21 +186,89 @@ func (c *Config) load(dynamicIfaceLabels bool) error {
 
 // DeviceConfig is the config representation of 1 device
 type DeviceConfig struct {
-	Host                    string         `yaml:"host"`
-	Username                string         `yaml:"username,omitempty"`
-	Password                string         `yaml:"password,omitempty"`
-	KeyFile                 string         `yaml:"key_file,omitempty"`
-	KeyPassphrase           string         `yaml:"key_passphrase,omitempty"`
-	Features                *FeatureConfig `yaml:"features,omitempty"`
-	IfDescRegStr            string         `yaml:"interface_description_regex,omitempty"`
-	IfDescReg               *regexp.Regexp `yaml:"-"`
-	IsHostPattern           bool           `yaml:"host_pattern,omitempty"`
-	HostPattern             *regexp.Regexp
-	InterfaceNameRegex      string `yaml:"interface_name_regex,omitempty"`
-	FirewallFilterNameRegex string `yaml:"firewall_filter_name_regex,omitempty"`
-	MNHASRGIDs              string `yaml:"mnha_srg_ids,omitempty"`
+	Host                    string              `yaml:"host,omitempty"`
+	Name                    string              `yaml:"name,omitempty"`
+	Address                 string              `yaml:"address,omitempty"`
+	DiscoverName            bool                `yaml:"discover_name,omitempty"`
+	Username                string              `yaml:"username,omitempty"`
+	Password                string              `yaml:"password,omitempty"`
+	KeyFile                 string              `yaml:"key_file,omitempty"`
+	KeyPassphrase           string              `yaml:"key_passphrase,omitempty"`
+	Credentials             string              `yaml:"credentials,omitempty"`
+	Features                *FeatureConfig      `yaml:"features,omitempty"`
+	IfDescRegStr            string              `yaml:"interface_description_regex,omitempty"`
+	IfDescReg               *regexp.Regexp      `yaml:"-"`
+	IsHostPattern           bool                `yaml:"host_pattern,omitempty"`
+	HostPattern             *regexp.Regexp      `yaml:"-"`
+	InterfaceNameRegex      string              `yaml:"interface_name_regex,omitempty"`
+	FirewallFilterNameRegex string              `yaml:"firewall_filter_name_regex,omitempty"`
+	MNHASRGIDs              string              `yaml:"mnha_srg_ids,omitempty"`
+	LogicalSystems          []string            `yaml:"logical_systems,omitempty"`
+	RoutingInstances        []string            `yaml:"routing_instances,omitempty"`
+	Groups                  []string            `yaml:"groups,omitempty"`
+	SSH                     *SSHConfig          `yaml:"ssh,omitempty"`
+	Labels                  map[string]string   `yaml:"labels,omitempty"`
+	CollectorOptions        *CollectorOptions   `yaml:"collector_options,omitempty"`
+	MetricRelabelConfigs    []*relabel.Config   `yaml:"metric_relabel_configs,omitempty"`
+	SeriesLimits            *SeriesLimitsConfig `yaml:"series_limits,omitempty"`
+
+	// featureKeys are the features set explicitly in the config file
+	featureKeys map[string]bool
+	// effectiveFeatures are the features after merging global, group and device features
+	effectiveFeatures *FeatureConfig
+	// effectiveOptions are the collector options after merging global, group and device options
+	effectiveOptions *CollectorOptions
+	// effectiveRelabelConfigs are the global, group and device relabel rules (in this order)
+	effectiveRelabelConfigs []*relabel.Config
+	// effectiveSeriesLimits are the series limits after merging global, group and device limits
+	effectiveSeriesLimits *SeriesLimitsConfig
+}
+
+// TargetName returns the name of the device used as target label and to match the target parameter
+func (d *DeviceConfig) TargetName() string {
+	if d.Name != "" && !d.IsHostPattern {
+		return d.Name
+	}
+
+	return d.Host
+}
+
+// AddressForTarget returns the address to connect to for a target of the device.
+// For host patterns the address is a template which can reference submatches of the pattern (e.g. "${1}.mgmt.example.com").
+// An empty string is returned if the target itself should be used as address.
+func (d *DeviceConfig) AddressForTarget(target string) string {
+	if d.IsHostPattern {
+		if d.Address == "" || d.HostPattern == nil {
+			return ""
+		}
+
+		m := d.HostPattern.FindStringSubmatchIndex(target)
+		if m == nil {
+			return ""
+		}
+
+		return string(d.HostPattern.ExpandString(nil, d.Address, target, m))
+	}
+
+	if d.Address != "" {
+		return d.Address
+	}
+
+	if d.Name != "" && d.Host != d.Name {
+		return d.Host
+	}
+
+	return ""
 }
 
+const (
+	// AllLogicalSystems can be used in the logical systems list of a device to scrape every logical system found on the device
+	AllLogicalSystems = "all"
+
+	// AllRoutingInstances can be used in the routing instances list of a device to scrape every VRF and virtual router found on the device
+	AllRoutingInstances = "all"
+)
+
 // FeatureConfig is the list of collectors enabled or disabled
 type FeatureConfig struct {
 	Alarm               bool `yaml:"alarm,omitempty"`
@@ -86,6 +290,7 @@ type FeatureConfig struct {
 	LLDP                bool `yaml:"lldp,omitempty"`
 	Routes              bool `yaml:"routes,omitempty"`
 	RoutingEngine       bool `yaml:"routing_engine,omitempty"`
+	RoutingInstance     bool `yaml:"routing_instance,omitempty"`
 	Firewall            bool `yaml:"firewall,omitempty"`
 	Interfaces          bool `yaml:"interfaces,omitempty"`
 	InterfaceDiagnostic bool `yaml:"interface_diagnostic,omitempty"`
@@ -119,27 +324,31 @@ type FeatureConfig struct {
 	SystemStatistics    bool `yaml:"system_statistics,omitempty"`
 	UFD                 bool `yaml:"ufd,omitempty"`
 	MNHA                bool `yaml:"mnha,omitempty"`
+
+	// Extra are the features of collectors registered by other modules embedding the exporter
+	Extra map[string]bool `yaml:",inline"`
 }
 
-// New creates a new config
-func New() *Config {
+// New creates a new config with the features enabled by default in the registry
+func New(reg *collector.Registry) *Config {
 	c := &Config{
-		Targets: make([]string, 0),
+		Targets:  make([]string, 0),
+		registry: reg,
 	}
 	setDefaultValues(c)
 
 	return c
 }
 
-// Load loads a config from reader
-func Load(reader io.Reader, dynamicIfaceLabels bool) (*Config, error) {
+// Load loads a config from reader. Features are validated against the collectors of the registry.
+func Load(reader io.Reader, dynamicIfaceLabels bool, reg *collector.Registry) (*Config, error) {
 	b, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
 
-	c := New()
-	err = yaml.Unmarshal(b, c)
+	c := New(reg)
+	err = unmarshalStrict(b, c)
 	if err != nil {
 		return nil, err
 	}
@@ -149,40 +358,89 @@ func Load(reader io.Reader, dynamicIfaceLabels bool) (*Config, error) {
 		return nil, err
 	}
 
-	for _, device := range c.Devices {
-		if device.IsHostPattern {
-			hostPattern, err := regexp.Compile(device.Host)
-			if err != nil {
-				return nil, err
-			}
-			device.HostPattern = hostPattern
+	return c, nil
+}
+
+// unmarshalStrict decodes YAML like yaml.Unmarshal, but rejects unknown fields
+func unmarshalStrict(b []byte, v any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	err := dec.Decode(v)
+	if errors.Is(err, io.EOF) {
+		// empty document
+		return nil
+	}
+
+	return err
+}
+
+// LoadDevices loads a list of devices (e.g. from a device file). The devices
+// are initialized like the devices of the config (groups, regexes, labels).
+func (c *Config) LoadDevices(b []byte, dynamicIfaceLabels bool) ([]*DeviceConfig, error) {
+	devices := make([]*DeviceConfig, 0)
+	err := unmarshalStrict(b, &devices)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.InitDevices(devices, dynamicIfaceLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// InitDevices initializes devices not loaded with the config (e.g. from an inventory)
+// like the devices of the config (groups, regexes, labels)
+func (c *Config) InitDevices(devices []*DeviceConfig, dynamicIfaceLabels bool) error {
+	for i, d := range devices {
+		if d == nil || d.TargetName() == "" {
+			return fmt.Errorf("device %d: host or name must be set", i)
+		}
+
+		err := c.loadDevice(d, dynamicIfaceLabels)
+		if err != nil {
+			return err
 		}
 	}
 
-	return c, nil
+	return nil
 }
 
-func setDefaultValues(c *Config) {
-	f := &c.Features
-	f.Alarm = true
-	f.BGP = true
-	f.Environment = true
-	f.Firewall = true
-	f.Interfaces = true
-	f.InterfaceDiagnostic = true
-	f.InterfaceQueue = true
-	f.ISIS = true
-	f.LDP = true
-	f.MACSec = true
-	f.OSPF = true
-	f.Routes = true
-	f.RoutingEngine = true
-	f.SystemStatistics = true
+// WithDevices returns a copy of the config with additional devices
+func (c *Config) WithDevices(devices []*DeviceConfig) *Config {
+	n := *c
+	n.Devices = append(slices.Clone(c.Devices), devices...)
+
+	return &n
+}
+
+// InitTargetDevices creates the device configs from the targets if no devices are configured
+func (c *Config) InitTargetDevices() {
+	if c.Devices != nil {
+		return
+	}
+
+	c.Devices = make([]*DeviceConfig, len(c.Targets))
+	for i, t := range c.Targets {
+		c.Devices[i] = &DeviceConfig{
+			Host: t,
+		}
+	}
 }
 
 // FeaturesForDevice gets the feature set configured for a device
 func (c *Config) FeaturesForDevice(host string) *FeatureConfig {
-	d := c.FindDeviceConfig(host)
+	return c.FeaturesForDeviceConfig(c.FindDeviceConfig(host))
+}
+
+// FeaturesForDeviceConfig gets the feature set of a device config (global features if d is nil)
+func (c *Config) FeaturesForDeviceConfig(d *DeviceConfig) *FeatureConfig {
+	if d != nil && d.effectiveFeatures != nil {
+		return d.effectiveFeatures
+	}
 
 	if d != nil && d.Features != nil {
 		return d.Features
@@ -191,6 +449,7 @@ func (c *Config) FeaturesForDevice(host string) *FeatureConfig {
 	return &c.Features
 }
 
+// FindDeviceConfig gets the config of a device by its target name
 func (c *Config) FindDeviceConfig(host string) *DeviceConfig {
 	for _, dc := range c.Devices {
 		if dc.HostPattern != nil {
@@ -198,7 +457,7 @@ func (c *Config) FindDeviceConfig(host string) *DeviceConfig {
 				return dc
 			}
 		} else {
-			if dc.Host == host {
+			if dc.TargetName() == host {
 				return dc
 			}
 		}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// CredentialsConfig is a named credential profile devices and groups can reference
+type CredentialsConfig struct {
+	Username      string        `yaml:"username,omitempty"`
+	Password      *SecretSource `yaml:"password,omitempty"`
+	Key           *SecretSource `yaml:"key,omitempty"`
+	KeyPassphrase *SecretSource `yaml:"key_passphrase,omitempty"`
+}
+
+// SecretSource defines where a secret is read from. Exactly one of Env, File and Exec must be set.
+type SecretSource struct {
+	// Env is the name of an environment variable containing the secret
+	Env string `yaml:"env,omitempty"`
+
+	// File is the path of a file containing the secret (trailing newline trimmed)
+	File string `yaml:"file,omitempty"`
+
+	// Exec is a command (and its arguments) printing the secret to stdout
+	Exec []string `yaml:"exec,omitempty"`
+
+	// TTL is the time the output of Exec is cached
+	TTL time.Duration `yaml:"ttl,omitempty"`
+}
+
+// String returns a description of the source (never the secret itself)
+func (s *SecretSource) String() string {
+	switch {
+	case s.Env != "":
+		return fmt.Sprintf("env %s", s.Env)
+	case s.File != "":
+		return fmt.Sprintf("file %s", s.File)
+	case len(s.Exec) > 0:
+		return fmt.Sprintf("exec %s", s.Exec[0])
+	default:
+		return "empty source"
+	}
+}
+
+func (s *SecretSource) check() error {
+	set := 0
+	if s.Env != "" {
+		set++
+	}
+	if s.File != "" {
+		set++
+	}
+	if len(s.Exec) > 0 {
+		set++
+	}
+
+	if set != 1 {
+		return fmt.Errorf("exactly one of env, file and exec must be set")
+	}
+
+	if s.TTL != 0 && len(s.Exec) == 0 {
+		return fmt.Errorf("ttl can only be used with exec")
+	}
+
+	return nil
+}
+
+func (p *CredentialsConfig) check() error {
+	if p.Password == nil && p.Key == nil {
+		return fmt.Errorf("password or key must be set")
+	}
+
+	if p.Password != nil && p.Key != nil {
+		return fmt.Errorf("password and key are mutually exclusive")
+	}
+
+	if p.KeyPassphrase != nil && p.Key == nil {
+		return fmt.Errorf("key_passphrase can only be used with key")
+	}
+
+	return nil
+}
+
+// CredentialsForDevice returns the credential profile referenced by a device (nil if none is referenced)
+func (c *Config) CredentialsForDevice(d *DeviceConfig) (*CredentialsConfig, error) {
+	if d.Credentials == "" {
+		return nil, nil
+	}
+
+	p, found := c.Credentials[d.Credentials]
+	if !found || p == nil {
+		return nil, fmt.Errorf("device %s references unknown credentials %q", d.TargetName(), d.Credentials)
+	}
+
+	return p, nil
+}
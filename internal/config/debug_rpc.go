@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DebugRPCConfig configures the troubleshooting endpoint /debug/rpc
+type DebugRPCConfig struct {
+	// AllowedCommands are regular expressions of commands which can be run in
+	// addition to the commands of the collectors. The expressions have to match the whole command.
+	AllowedCommands []string `yaml:"allowed_commands,omitempty"`
+
+	allowedCommands []*regexp.Regexp
+}
+
+func (c *DebugRPCConfig) check() error {
+	if c == nil {
+		return nil
+	}
+
+	c.allowedCommands = make([]*regexp.Regexp, len(c.AllowedCommands))
+	for i, expr := range c.AllowedCommands {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return fmt.Errorf("allowed_commands: invalid regex %q: %w", expr, err)
+		}
+
+		c.allowedCommands[i] = re
+	}
+
+	return nil
+}
+
+// CommandAllowed returns whether a command matches one of the allowed commands
+func (c *DebugRPCConfig) CommandAllowed(cmd string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, re := range c.allowedCommands {
+		if re.MatchString(cmd) {
+			return true
+		}
+	}
+
+	return false
+}
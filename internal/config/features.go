@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+var (
+	featureFieldsOnce sync.Once
+	featureFields     map[string]int
+)
+
+// featureFieldIndex returns the index of the field of a feature in FeatureConfig (-1 if the feature has no field)
+func featureFieldIndex(name string) int {
+	featureFieldsOnce.Do(func() {
+		featureFields = make(map[string]int)
+
+		t := reflect.TypeOf(FeatureConfig{})
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if tag != "" && t.Field(i).Type.Kind() == reflect.Bool {
+				featureFields[tag] = i
+			}
+		}
+	})
+
+	i, found := featureFields[name]
+	if !found {
+		return -1
+	}
+
+	return i
+}
+
+// Enabled returns whether a feature is enabled
+func (f *FeatureConfig) Enabled(name string) bool {
+	if i := featureFieldIndex(name); i >= 0 {
+		return reflect.ValueOf(f).Elem().Field(i).Bool()
+	}
+
+	return f.Extra[name]
+}
+
+// Set enables or disables a feature
+func (f *FeatureConfig) Set(name string, enabled bool) {
+	if i := featureFieldIndex(name); i >= 0 {
+		reflect.ValueOf(f).Elem().Field(i).SetBool(enabled)
+		return
+	}
+
+	if f.Extra == nil {
+		f.Extra = make(map[string]bool)
+	}
+
+	f.Extra[name] = enabled
+}
+
+// CollectorEnabled returns whether a collector is enabled by its feature or one of the features enabling it
+func (f *FeatureConfig) CollectorEnabled(r collector.Registration) bool {
+	if f.Enabled(r.Feature) {
+		return true
+	}
+
+	for _, name := range r.EnabledBy {
+		if f.Enabled(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *FeatureConfig) check(reg *collector.Registry) error {
+	if f == nil {
+		return nil
+	}
+
+	for _, name := range sortedKeys(f.Extra) {
+		if _, found := reg.RegistrationForFeature(name); !found {
+			return fmt.Errorf("unknown feature %q", name)
+		}
+	}
+
+	return nil
+}
+
+func setDefaultValues(c *Config) {
+	for _, r := range c.collectorRegistry().Registrations() {
+		if r.DefaultEnabled {
+			c.Features.Set(r.Feature, true)
+		}
+	}
+}
+
+// overlayFeatures sets the features in keys to the values in src
+func overlayFeatures(dst, src *FeatureConfig, keys map[string]bool) {
+	if src == nil {
+		return
+	}
+
+	// the map of dst may be shared with another config
+	dst.Extra = maps.Clone(dst.Extra)
+
+	for name := range keys {
+		dst.Set(name, src.Enabled(name))
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
+)
+
+// GroupConfig is a named set of settings shared by devices referencing the group
+type GroupConfig struct {
+	Username                string              `yaml:"username,omitempty"`
+	Password                string              `yaml:"password,omitempty"`
+	KeyFile                 string              `yaml:"key_file,omitempty"`
+	KeyPassphrase           string              `yaml:"key_passphrase,omitempty"`
+	Credentials             string              `yaml:"credentials,omitempty"`
+	Features                *FeatureConfig      `yaml:"features,omitempty"`
+	IfDescRegStr            string              `yaml:"interface_description_regex,omitempty"`
+	InterfaceNameRegex      string              `yaml:"interface_name_regex,omitempty"`
+	FirewallFilterNameRegex string              `yaml:"firewall_filter_name_regex,omitempty"`
+	SSH                     *SSHConfig          `yaml:"ssh,omitempty"`
+	Labels                  map[string]string   `yaml:"labels,omitempty"`
+	CollectorOptions        *CollectorOptions   `yaml:"collector_options,omitempty"`
+	MetricRelabelConfigs    []*relabel.Config   `yaml:"metric_relabel_configs,omitempty"`
+	SeriesLimits            *SeriesLimitsConfig `yaml:"series_limits,omitempty"`
+
+	// featureKeys are the features set explicitly in the config file
+	featureKeys map[string]bool
+}
+
+// SSHConfig are SSH options overriding the global settings
+type SSHConfig struct {
+	Port              int           `yaml:"port,omitempty"`
+	KeepAliveInterval time.Duration `yaml:"keep_alive_interval,omitempty"`
+	KeepAliveTimeout  time.Duration `yaml:"keep_alive_timeout,omitempty"`
+}
+
+// UnmarshalYAML records which features are set for the device, so only those override group and global features
+func (d *DeviceConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	type deviceConfig DeviceConfig
+	err := unmarshal((*deviceConfig)(d))
+	if err != nil {
+		return err
+	}
+
+	d.featureKeys, err = featureKeys(unmarshal)
+	return err
+}
+
+// UnmarshalYAML records which features are set for the group, so only those override global features
+func (g *GroupConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	type groupConfig GroupConfig
+	err := unmarshal((*groupConfig)(g))
+	if err != nil {
+		return err
+	}
+
+	g.featureKeys, err = featureKeys(unmarshal)
+	return err
+}
+
+func featureKeys(unmarshal func(any) error) (map[string]bool, error) {
+	var raw map[string]any
+	err := unmarshal(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	f, found := raw["features"]
+	if !found || f == nil {
+		return nil, nil
+	}
+
+	m, ok := f.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("features must be a map")
+	}
+
+	keys := make(map[string]bool)
+	for k := range m {
+		keys[k] = true
+	}
+
+	return keys, nil
+}
+
+// resolveGroupsForDevice merges the settings of the groups referenced by a device into the device config.
+// Settings of the device take precedence, if a device references multiple groups the last group wins.
+// Features, collector options and labels are merged field by field in the order global -> groups -> device.
+func (c *Config) resolveGroupsForDevice(d *DeviceConfig) error {
+	groups := make([]*GroupConfig, len(d.Groups))
+	for i, name := range d.Groups {
+		g, found := c.Groups[name]
+		if !found || g == nil {
+			return fmt.Errorf("device %s references unknown group %q", d.TargetName(), name)
+		}
+
+		groups[i] = g
+	}
+
+	c.resolveCollectorOptions(d, groups)
+	c.resolveRelabelConfigs(d, groups)
+	c.resolveSeriesLimits(d, groups)
+
+	for i := len(groups) - 1; i >= 0; i-- {
+		g := groups[i]
+		inherit(&d.Username, g.Username)
+		inherit(&d.Password, g.Password)
+		inherit(&d.KeyFile, g.KeyFile)
+		inherit(&d.KeyPassphrase, g.KeyPassphrase)
+		inherit(&d.Credentials, g.Credentials)
+		inherit(&d.IfDescRegStr, g.IfDescRegStr)
+		inherit(&d.InterfaceNameRegex, g.InterfaceNameRegex)
+		inherit(&d.FirewallFilterNameRegex, g.FirewallFilterNameRegex)
+		d.SSH = inheritSSH(d.SSH, g.SSH)
+		d.Labels = inheritLabels(d.Labels, g.Labels)
+	}
+
+	if d.Features == nil && len(groups) == 0 {
+		return nil
+	}
+
+	f := c.Features
+	for _, g := range groups {
+		overlayFeatures(&f, g.Features, g.featureKeys)
+	}
+	overlayFeatures(&f, d.Features, d.featureKeys)
+
+	d.effectiveFeatures = &f
+
+	return nil
+}
+
+func inherit(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+func inheritSSH(dst, src *SSHConfig) *SSHConfig {
+	if src == nil {
+		return dst
+	}
+
+	if dst == nil {
+		dst = &SSHConfig{}
+	}
+
+	if dst.Port == 0 {
+		dst.Port = src.Port
+	}
+
+	if dst.KeepAliveInterval == 0 {
+		dst.KeepAliveInterval = src.KeepAliveInterval
+	}
+
+	if dst.KeepAliveTimeout == 0 {
+		dst.KeepAliveTimeout = src.KeepAliveTimeout
+	}
+
+	return dst
+}
+
+func inheritLabels(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if dst == nil {
+		dst = make(map[string]string)
+	}
+
+	for k, v := range src {
+		if _, found := dst[k]; !found {
+			dst[k] = v
+		}
+	}
+
+	return dst
+}
+
+// EffectiveDeviceConfig returns the config used for a target after resolving groups and features
+func (c *Config) EffectiveDeviceConfig(host string) *DeviceConfig {
+	d := c.FindDeviceConfig(host)
+	if d == nil {
+		return &DeviceConfig{
+			Host:                    host,
+			Features:                &c.Features,
+			IfDescRegStr:            c.IfDescRegStr,
+			InterfaceNameRegex:      c.InterfaceNameRegex,
+			FirewallFilterNameRegex: c.FirewallFilterNameRegex,
+			MNHASRGIDs:              c.MNHASRGIDs,
+			CollectorOptions:        c.CollectorOptionsForDevice(nil),
+			MetricRelabelConfigs:    c.MetricRelabelConfigs,
+			SeriesLimits:            c.SeriesLimitsForDeviceConfig(nil),
+		}
+	}
+
+	e := *d
+	e.Host = host
+	e.Features = c.FeaturesForDevice(host)
+	e.CollectorOptions = c.CollectorOptionsForDevice(d)
+	e.MetricRelabelConfigs = c.RelabelConfigsForDeviceConfig(d)
+	e.SeriesLimits = c.SeriesLimitsForDeviceConfig(d)
+
+	return &e
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"net/url"
+	"time"
+)
+
+// DefaultHTTPInventoryRefreshInterval is used if no refresh interval is configured for an HTTP inventory
+const DefaultHTTPInventoryRefreshInterval = 5 * time.Minute
+
+// HTTPInventoryFields are the fields of an HTTP inventory device which can be mapped to labels and groups
+var HTTPInventoryFields = []string{"site", "role", "platform", "tenant"}
+
+// HTTPInventoryConfig is an HTTP JSON inventory (e.g. the NetBox device API) to load devices from
+type HTTPInventoryConfig struct {
+	URL             string              `yaml:"url"`
+	Token           string              `yaml:"token,omitempty"`
+	TokenFile       string              `yaml:"token_file,omitempty"`
+	Filters         map[string][]string `yaml:"filters,omitempty"`
+	RefreshInterval time.Duration       `yaml:"refresh_interval,omitempty"`
+
+	// Groups are the groups every device of the inventory is member of
+	Groups []string `yaml:"groups,omitempty"`
+
+	// GroupBy are fields (e.g. role) whose value is used as group of a device, if such a group is defined
+	GroupBy []string `yaml:"group_by,omitempty"`
+
+	// Labels maps label names to fields of the device. Default: site, role and platform are used as labels.
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// Interval returns the refresh interval of the inventory
+func (c *HTTPInventoryConfig) Interval() time.Duration {
+	if c.RefreshInterval > 0 {
+		return c.RefreshInterval
+	}
+
+	return DefaultHTTPInventoryRefreshInterval
+}
+
+// Name returns the URL of the inventory without user info, query and fragment, so it can be
+// used in logs and labels without exposing credentials passed in the URL
+func (c *HTTPInventoryConfig) Name() string {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return "invalid URL"
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
+
+// LabelFields returns the mapping of label names to fields of the device
+func (c *HTTPInventoryConfig) LabelFields() map[string]string {
+	if c.Labels != nil {
+		return c.Labels
+	}
+
+	return map[string]string{
+		"site":     "site",
+		"role":     "role",
+		"platform": "platform",
+	}
+}
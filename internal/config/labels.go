@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedLabelNames are the labels added by the exporter itself
+var reservedLabelNames = map[string]bool{
+	"target":           true,
+	"collector":        true,
+	"logical_system":   true,
+	"routing_instance": true,
+}
+
+func checkLabelName(name string) error {
+	if !labelNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid label name %q", name)
+	}
+
+	if strings.HasPrefix(name, "__") {
+		return fmt.Errorf("label name %q is reserved for internal use", name)
+	}
+
+	if ReservedLabelName(name) {
+		return fmt.Errorf("label name %q is used by the exporter", name)
+	}
+
+	return nil
+}
+
+// ReservedLabelName returns whether a label is added by the exporter itself
+// (e.g. logical_system and routing_instance), so it can not be a static label
+func ReservedLabelName(name string) bool {
+	return reservedLabelNames[name]
+}
+
+func checkDeviceLabels(d *DeviceConfig) error {
+	for _, name := range sortedKeys(d.Labels) {
+		if err := checkLabelName(name); err != nil {
+			return fmt.Errorf("device %s: %w", d.TargetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
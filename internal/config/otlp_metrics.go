@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// OTLPMetricsConfig configures the export of the metrics of all devices to an
+// OpenTelemetry Collector (or any other endpoint supporting OTLP)
+type OTLPMetricsConfig struct {
+	// Endpoint is the address of the endpoint (host:port) or, for protocol http, a URL
+	Endpoint string `yaml:"endpoint"`
+
+	// Protocol is the OTLP transport: grpc (default) or http (protobuf over HTTP)
+	Protocol string `yaml:"protocol,omitempty"`
+
+	// Insecure disables TLS
+	Insecure bool `yaml:"insecure,omitempty"`
+
+	// Interval is the interval the devices are scraped and exported in (default: 1m)
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// Timeout is the timeout of an export request (default: 10s)
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Headers are sent with every export request (e.g. for authentication)
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// ResourceAttributes are added to the resource of every device
+	ResourceAttributes map[string]string `yaml:"resource_attributes,omitempty"`
+}
+
+func (c *OTLPMetricsConfig) check() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Endpoint == "" {
+		return fmt.Errorf("endpoint must be set")
+	}
+
+	switch c.Protocol {
+	case "", "grpc", "http":
+	default:
+		return fmt.Errorf("invalid protocol %q (valid: grpc, http)", c.Protocol)
+	}
+
+	return nil
+}
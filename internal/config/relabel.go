@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
+)
+
+func checkRelabelConfigs(rules []*relabel.Config) error {
+	err := relabel.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("metric_relabel_configs: %w", err)
+	}
+
+	return nil
+}
+
+// resolveRelabelConfigs concatenates the relabel rules in the order global -> groups -> device
+func (c *Config) resolveRelabelConfigs(d *DeviceConfig, groups []*GroupConfig) {
+	rules := make([]*relabel.Config, 0)
+	rules = append(rules, c.MetricRelabelConfigs...)
+	for _, g := range groups {
+		rules = append(rules, g.MetricRelabelConfigs...)
+	}
+	rules = append(rules, d.MetricRelabelConfigs...)
+
+	d.effectiveRelabelConfigs = rules
+}
+
+// RelabelConfigsForDevice returns the relabel rules applied to the metrics of a device
+// (global rules followed by the rules of its groups and the device)
+func (c *Config) RelabelConfigsForDevice(host string) []*relabel.Config {
+	return c.RelabelConfigsForDeviceConfig(c.FindDeviceConfig(host))
+}
+
+// RelabelConfigsForDeviceConfig returns the relabel rules of a device config (global rules if d is nil)
+func (c *Config) RelabelConfigsForDeviceConfig(d *DeviceConfig) []*relabel.Config {
+	if d != nil && d.effectiveRelabelConfigs != nil {
+		return d.effectiveRelabelConfigs
+	}
+
+	if d != nil && len(d.MetricRelabelConfigs) > 0 {
+		return append(append([]*relabel.Config{}, c.MetricRelabelConfigs...), d.MetricRelabelConfigs...)
+	}
+
+	return c.MetricRelabelConfigs
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// RemoteWriteConfig configures the push mode: the devices are scraped in the
+// background and the metrics are sent to an endpoint supporting the Prometheus
+// remote-write protocol (e.g. for sites behind NAT)
+type RemoteWriteConfig struct {
+	URL string `yaml:"url"`
+
+	// ScrapeInterval is the interval the devices are scraped in (default: 1m)
+	ScrapeInterval time.Duration `yaml:"scrape_interval,omitempty"`
+
+	// RemoteTimeout is the timeout of a request to the endpoint (default: 30s)
+	RemoteTimeout time.Duration `yaml:"remote_timeout,omitempty"`
+
+	// QueueCapacity is the number of scrape results buffered while the endpoint
+	// is unavailable. The oldest result is dropped if the queue is full (default: 10).
+	QueueCapacity int `yaml:"queue_capacity,omitempty"`
+
+	// MaxRetries is the number of retries of a failed request before the result is dropped (default: 5)
+	MaxRetries int `yaml:"max_retries,omitempty"`
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between retries (default: 1s and 30s)
+	MinBackoff time.Duration `yaml:"min_backoff,omitempty"`
+	MaxBackoff time.Duration `yaml:"max_backoff,omitempty"`
+
+	BasicAuth       *BasicAuthConfig `yaml:"basic_auth,omitempty"`
+	BearerToken     string           `yaml:"bearer_token,omitempty"`
+	BearerTokenFile string           `yaml:"bearer_token_file,omitempty"`
+
+	// ExternalLabels are added to every series sent to the endpoint
+	ExternalLabels map[string]string `yaml:"external_labels,omitempty"`
+}
+
+// BasicAuthConfig are the credentials of HTTP basic authentication
+type BasicAuthConfig struct {
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password,omitempty"`
+	PasswordFile string `yaml:"password_file,omitempty"`
+}
+
+func (c *RemoteWriteConfig) check() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.URL == "" {
+		return fmt.Errorf("url must be set")
+	}
+
+	if _, err := url.Parse(c.URL); err != nil {
+		return err
+	}
+
+	if c.BearerToken != "" && c.BearerTokenFile != "" {
+		return fmt.Errorf("bearer_token and bearer_token_file are mutually exclusive")
+	}
+
+	if c.BasicAuth != nil {
+		if c.BearerToken != "" || c.BearerTokenFile != "" {
+			return fmt.Errorf("basic_auth and bearer_token are mutually exclusive")
+		}
+
+		if c.BasicAuth.Password != "" && c.BasicAuth.PasswordFile != "" {
+			return fmt.Errorf("password and password_file are mutually exclusive")
+		}
+	}
+
+	for _, name := range sortedKeys(c.ExternalLabels) {
+		if err := checkLabelName(name); err != nil {
+			return fmt.Errorf("external_labels: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+const (
+	// SeriesLimitDrop drops all series of a collector exceeding a limit
+	SeriesLimitDrop = "drop"
+
+	// SeriesLimitTruncate keeps the series of a collector up to the limit
+	SeriesLimitTruncate = "truncate"
+)
+
+// SeriesLimitsConfig limits the number of series collected from a device
+type SeriesLimitsConfig struct {
+	// MaxSeries is the maximum number of series of all collectors of the target (0: no limit)
+	MaxSeries int `yaml:"max_series,omitempty"`
+
+	// Action is applied to the series of a collector exceeding a limit: drop (default) or truncate
+	Action string `yaml:"action,omitempty"`
+
+	// Collectors are the maximum numbers of series of single collectors, keyed by feature name (0: no limit)
+	Collectors map[string]int `yaml:"collectors,omitempty"`
+}
+
+func (c *SeriesLimitsConfig) check(reg *collector.Registry) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.MaxSeries < 0 {
+		return fmt.Errorf("max_series must not be negative")
+	}
+
+	switch c.Action {
+	case "", SeriesLimitDrop, SeriesLimitTruncate:
+	default:
+		return fmt.Errorf("invalid action %q (valid: %s, %s)", c.Action, SeriesLimitDrop, SeriesLimitTruncate)
+	}
+
+	for _, name := range sortedKeys(c.Collectors) {
+		if _, found := reg.RegistrationForFeature(name); !found {
+			return fmt.Errorf("collectors: unknown feature %q", name)
+		}
+
+		if c.Collectors[name] < 0 {
+			return fmt.Errorf("collectors: %s: limit must not be negative", name)
+		}
+	}
+
+	return nil
+}
+
+func checkSeriesLimits(l *SeriesLimitsConfig, reg *collector.Registry) error {
+	err := l.check(reg)
+	if err != nil {
+		return fmt.Errorf("series_limits: %w", err)
+	}
+
+	return nil
+}
+
+// Enabled returns whether any limit is set
+func (c *SeriesLimitsConfig) Enabled() bool {
+	if c == nil {
+		return false
+	}
+
+	if c.MaxSeries > 0 {
+		return true
+	}
+
+	for _, limit := range c.Collectors {
+		if limit > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LimitForCollector returns the limit of the collector of a feature (0: no limit)
+func (c *SeriesLimitsConfig) LimitForCollector(feature string) int {
+	if c == nil {
+		return 0
+	}
+
+	return c.Collectors[feature]
+}
+
+// Truncate returns whether series up to the limit are kept if a limit is exceeded
+func (c *SeriesLimitsConfig) Truncate() bool {
+	return c != nil && c.Action == SeriesLimitTruncate
+}
+
+// overlaySeriesLimits sets the limits set in src in dst (limit by limit)
+func overlaySeriesLimits(dst, src *SeriesLimitsConfig) {
+	if src == nil {
+		return
+	}
+
+	if src.MaxSeries != 0 {
+		dst.MaxSeries = src.MaxSeries
+	}
+
+	if src.Action != "" {
+		dst.Action = src.Action
+	}
+
+	if len(src.Collectors) > 0 {
+		if dst.Collectors == nil {
+			dst.Collectors = make(map[string]int)
+		}
+
+		maps.Copy(dst.Collectors, src.Collectors)
+	}
+}
+
+// resolveSeriesLimits merges the series limits in the order global -> groups -> device
+func (c *Config) resolveSeriesLimits(d *DeviceConfig, groups []*GroupConfig) {
+	l := &SeriesLimitsConfig{}
+	overlaySeriesLimits(l, c.SeriesLimits)
+	for _, g := range groups {
+		overlaySeriesLimits(l, g.SeriesLimits)
+	}
+	overlaySeriesLimits(l, d.SeriesLimits)
+
+	d.effectiveSeriesLimits = l
+}
+
+// SeriesLimitsForDevice returns the series limits of a device after merging the global, group and device limits
+func (c *Config) SeriesLimitsForDevice(host string) *SeriesLimitsConfig {
+	return c.SeriesLimitsForDeviceConfig(c.FindDeviceConfig(host))
+}
+
+// SeriesLimitsForDeviceConfig returns the series limits of a device config (global limits if d is nil)
+func (c *Config) SeriesLimitsForDeviceConfig(d *DeviceConfig) *SeriesLimitsConfig {
+	if d != nil && d.effectiveSeriesLimits != nil {
+		return d.effectiveSeriesLimits
+	}
+
+	l := &SeriesLimitsConfig{}
+	overlaySeriesLimits(l, c.SeriesLimits)
+	if d != nil {
+		overlaySeriesLimits(l, d.SeriesLimits)
+	}
+
+	return l
+}
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: MIT
+
+// Package inventory loads devices from external sources of truth
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+// HTTP loads devices from an HTTP JSON inventory with the schema of the NetBox device API
+// (paginated by a next link, authentication by token)
+type HTTP struct {
+	cfg    *config.HTTPInventoryConfig
+	client *http.Client
+}
+
+// Device is a device of the inventory
+type Device struct {
+	Name       string `json:"name"`
+	PrimaryIP  *IP    `json:"primary_ip"`
+	Platform   *Ref   `json:"platform"`
+	Site       *Ref   `json:"site"`
+	Role       *Ref   `json:"role"`
+	DeviceRole *Ref   `json:"device_role"`
+	Tenant     *Ref   `json:"tenant"`
+}
+
+// IP is an IP address in CIDR notation
+type IP struct {
+	Address string `json:"address"`
+}
+
+// Ref is a reference to another object of the inventory
+type Ref struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// maxPages is the maximum number of pages fetched from an inventory
+const maxPages = 1000
+
+type page struct {
+	Next    string   `json:"next"`
+	Results []Device `json:"results"`
+}
+
+// NewHTTP creates a new HTTP inventory
+func NewHTTP(cfg *config.HTTPInventoryConfig, client *http.Client) *HTTP {
+	return &HTTP{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// Fetch gets all devices matching the filters of the inventory
+func (h *HTTP) Fetch(ctx context.Context) ([]Device, error) {
+	u, err := h.url()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := h.token()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]Device, 0)
+	seen := make(map[string]bool)
+	for u != "" && !seen[u] {
+		if len(seen) == maxPages {
+			return nil, fmt.Errorf("more than %d pages", maxPages)
+		}
+		seen[u] = true
+
+		p, err := h.fetchPage(ctx, u, token)
+		if err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, p.Results...)
+
+		u, err = nextPage(u, p.Next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return devices, nil
+}
+
+// nextPage resolves the next link of a page. The token is sent with every
+// request, so the link must point to the same scheme and host.
+func nextPage(current, next string) (string, error) {
+	if next == "" {
+		return "", nil
+	}
+
+	cu, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+
+	nu, err := cu.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", next, err)
+	}
+
+	if nu.Scheme != cu.Scheme || nu.Host != cu.Host {
+		return "", fmt.Errorf("next link %s points to another host than %s", nu.Redacted(), cu.Redacted())
+	}
+
+	return nu.String(), nil
+}
+
+func (h *HTTP) url() (string, error) {
+	u, err := url.Parse(h.cfg.URL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	for k, values := range h.cfg.Filters {
+		for _, v := range values {
+			q.Add(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (h *HTTP) token() (string, error) {
+	if h.cfg.TokenFile == "" {
+		return h.cfg.Token, nil
+	}
+
+	b, err := os.ReadFile(h.cfg.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+func (h *HTTP) fetchPage(ctx context.Context, u, token string) (*page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, u)
+	}
+
+	p := &page{}
+	err = json.NewDecoder(resp.Body).Decode(p)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode response from %s: %w", u, err)
+	}
+
+	return p, nil
+}
+
+// DeviceConfigs maps the devices of the inventory to device configs. Devices
+// without name or primary IP are skipped. Fields used in group_by are only
+// used as group if a group with this name exists.
+func (h *HTTP) DeviceConfigs(devices []Device, groups map[string]*config.GroupConfig) []*config.DeviceConfig {
+	labelFields := h.cfg.LabelFields()
+
+	configs := make([]*config.DeviceConfig, 0, len(devices))
+	for _, d := range devices {
+		if d.Name == "" || d.PrimaryIP == nil || d.PrimaryIP.Address == "" {
+			continue
+		}
+
+		dc := &config.DeviceConfig{
+			Name:    d.Name,
+			Address: address(d.PrimaryIP.Address),
+			Groups:  slices.Clone(h.cfg.Groups),
+		}
+
+		for _, field := range h.cfg.GroupBy {
+			g := d.field(field)
+			if _, found := groups[g]; found && !slices.Contains(dc.Groups, g) {
+				dc.Groups = append(dc.Groups, g)
+			}
+		}
+
+		for name, field := range labelFields {
+			if v := d.field(field); v != "" {
+				if dc.Labels == nil {
+					dc.Labels = make(map[string]string)
+				}
+
+				dc.Labels[name] = v
+			}
+		}
+
+		configs = append(configs, dc)
+	}
+
+	return configs
+}
+
+func (d *Device) field(name string) string {
+	var r *Ref
+
+	switch name {
+	case "site":
+		r = d.Site
+	case "role":
+		r = d.Role
+		if r == nil {
+			r = d.DeviceRole
+		}
+	case "platform":
+		r = d.Platform
+	case "tenant":
+		r = d.Tenant
+	}
+
+	if r == nil {
+		return ""
+	}
+
+	return r.Slug
+}
+
+// address strips the prefix length of an address in CIDR notation
+func address(cidr string) string {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return cidr
+	}
+
+	return ip.String()
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		assert.Equal(t, []string{"junos"}, r.URL.Query()["tag"])
+		assert.Equal(t, []string{"fra1", "ber1"}, r.URL.Query()["site"])
+
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprintf(w, `{
+  "count": 3,
+  "next": "%s/api/dcim/devices/?offset=2&tag=junos&site=fra1&site=ber1",
+  "results": [
+    {"name": "fra1-pe1", "primary_ip": {"address": "10.12.0.5/32"}, "site": {"slug": "fra1"}, "role": {"slug": "pe"}, "platform": {"slug": "junos"}},
+    {"name": "fra1-pe2", "primary_ip": null, "site": {"slug": "fra1"}}
+  ]
+}`, srv.URL)
+		case "2":
+			fmt.Fprint(w, `{
+  "count": 3,
+  "next": null,
+  "results": [
+    {"name": "ber1-p1", "primary_ip": {"address": "2001:db8::1/128"}, "site": {"slug": "ber1"}, "device_role": {"slug": "p"}}
+  ]
+}`)
+		}
+	}))
+
+	return srv
+}
+
+func TestHTTPFetch(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	inv := NewHTTP(&config.HTTPInventoryConfig{
+		URL:   srv.URL + "/api/dcim/devices/",
+		Token: "secret",
+		Filters: map[string][]string{
+			"tag":  {"junos"},
+			"site": {"fra1", "ber1"},
+		},
+		Groups:  []string{"all"},
+		GroupBy: []string{"role"},
+	}, srv.Client())
+
+	devices, err := inv.Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(devices), "device count")
+
+	configs := inv.DeviceConfigs(devices, map[string]*config.GroupConfig{
+		"all": {},
+		"pe":  {},
+	})
+	assert.Equal(t, []*config.DeviceConfig{
+		{
+			Name:    "fra1-pe1",
+			Address: "10.12.0.5",
+			Groups:  []string{"all", "pe"},
+			Labels:  map[string]string{"site": "fra1", "role": "pe", "platform": "junos"},
+		},
+		{
+			Name:    "ber1-p1",
+			Address: "2001:db8::1",
+			Groups:  []string{"all"},
+			Labels:  map[string]string{"site": "ber1", "role": "p"},
+		},
+	}, configs)
+}
+
+func TestHTTPFetchShouldFailWithoutToken(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	inv := NewHTTP(&config.HTTPInventoryConfig{
+		URL: srv.URL + "/api/dcim/devices/",
+	}, srv.Client())
+
+	_, err := inv.Fetch(context.Background())
+	assert.ErrorContains(t, err, "unexpected status 403 Forbidden")
+}
+
+func TestHTTPFetchShouldNotFollowNextToOtherHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request with authorization %q sent to other host", r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"next": "%s/api/dcim/devices/?offset=50", "results": []}`, other.URL)
+	}))
+	defer srv.Close()
+
+	inv := NewHTTP(&config.HTTPInventoryConfig{
+		URL:   srv.URL + "/api/dcim/devices/",
+		Token: "secret",
+	}, srv.Client())
+
+	_, err := inv.Fetch(context.Background())
+	assert.ErrorContains(t, err, "points to another host")
+}
+
+func TestHTTPFetchShouldStopOnPageAlreadySeen(t *testing.T) {
+	requests := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"next": "%s/api/dcim/devices/", "results": [{"name": "fra1-pe1", "primary_ip": {"address": "10.12.0.5/32"}}]}`, srv.URL)
+	}))
+	defer srv.Close()
+
+	inv := NewHTTP(&config.HTTPInventoryConfig{
+		URL: srv.URL + "/api/dcim/devices/",
+	}, srv.Client())
+
+	devices, err := inv.Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(devices), "device count")
+	assert.Equal(t, 1, requests, "request count")
+}
+
+func TestHTTPFetchShouldFailAfterMaxPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var offset int
+		fmt.Sscan(r.URL.Query().Get("offset"), &offset)
+		fmt.Fprintf(w, `{"next": "?offset=%d", "results": []}`, offset+1)
+	}))
+	defer srv.Close()
+
+	inv := NewHTTP(&config.HTTPInventoryConfig{
+		URL: srv.URL + "/api/dcim/devices/",
+	}, srv.Client())
+
+	_, err := inv.Fetch(context.Background())
+	assert.ErrorContains(t, err, "more than 1000 pages")
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: MIT
+
+// gen writes the mapping table of the v1 and v2 metric names
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+
+	"github.com/czerwonk/junos_exporter/internal/metricnames"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	featuresDir = flag.String("features", "pkg/features", "Directory of the collector packages")
+	out         = flag.String("out", "docs/metric-names-v2.md", "Path of the generated markdown file")
+)
+
+func main() {
+	flag.Parse()
+
+	metrics, err := metricnames.Scan(*featuresDir)
+	if err != nil {
+		log.Fatalf("could not scan collectors: %v", err)
+	}
+
+	var b bytes.Buffer
+	if err := metricnames.WriteMarkdown(&b, metrics); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, b.Bytes(), 0o644); err != nil {
+		log.Fatalf("could not write %s: %v", *out, err)
+	}
+}
@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: MIT
+
+// Package metricnames extracts the names and types of the metrics of the
+// collectors from their source code to generate the mapping table of the
+// v1 and v2 metric names (see pkg/naming).
+package metricnames
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+)
+
+// Metric is a metric reported by a collector
+type Metric struct {
+	Name      string
+	Collector string
+	Type      dto.MetricType
+}
+
+// Scan returns the metrics of the collectors in the sub directories of dir
+// (one package per collector) sorted by name. Metrics are found by their
+// descriptors (prometheus.NewDesc or collector.MetricNames.NewDesc) if the name is a constant expression, the
+// type is derived from the prometheus.MustNewConstMetric calls using the
+// descriptor. Metrics without such a call are considered gauges.
+func Scan(dir string) ([]*Metric, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]*Metric)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		p, err := scanPackage(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range p.metrics(e.Name()) {
+			if _, found := metrics[m.Name]; !found {
+				metrics[m.Name] = m
+			}
+		}
+	}
+
+	res := make([]*Metric, 0, len(metrics))
+	for _, m := range metrics {
+		res = append(res, m)
+	}
+
+	slices.SortFunc(res, func(a, b *Metric) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return res, nil
+}
+
+type pkg struct {
+	consts map[string]ast.Expr         // package level string constants and variables
+	descs  map[string][]string         // metric names by the variable holding the descriptor
+	types  map[string][]dto.MetricType // types by the variable holding the descriptor
+}
+
+func scanPackage(dir string) (*pkg, error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pkg{
+		consts: make(map[string]ast.Expr),
+		descs:  make(map[string][]string),
+		types:  make(map[string][]dto.MetricType),
+	}
+
+	parsed := make([]*ast.File, 0, len(files))
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+
+		af, err := parser.ParseFile(fset, f, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", f, err)
+		}
+
+		parsed = append(parsed, af)
+		p.addConsts(af)
+	}
+
+	for _, af := range parsed {
+		for _, d := range af.Decls {
+			p.scanDecl(d)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *pkg) addConsts(f *ast.File) {
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		for _, s := range gd.Specs {
+			vs, ok := s.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			for i, n := range vs.Names {
+				if i < len(vs.Values) {
+					p.consts[n.Name] = vs.Values[i]
+				}
+			}
+		}
+	}
+}
+
+// scanDecl finds the descriptors and their usage in a declaration. Local
+// string variables are resolved in the order of their assignments.
+func (p *pkg) scanDecl(d ast.Decl) {
+	locals := make(map[string]string)
+
+	ast.Inspect(d, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range x.Lhs {
+				if i >= len(x.Rhs) {
+					break
+				}
+
+				id, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				if v, ok := p.eval(x.Rhs[i], locals, 0); ok {
+					locals[id.Name] = v
+				}
+			}
+
+			p.addDescs(x.Lhs, x.Rhs, locals)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(x.Names))
+			for i, id := range x.Names {
+				lhs[i] = id
+
+				if _, isFunc := d.(*ast.FuncDecl); isFunc && i < len(x.Values) {
+					if v, ok := p.eval(x.Values[i], locals, 0); ok {
+						locals[id.Name] = v
+					}
+				}
+			}
+
+			p.addDescs(lhs, x.Values, locals)
+		case *ast.KeyValueExpr:
+			p.addDescs([]ast.Expr{x.Key}, []ast.Expr{x.Value}, locals)
+		case *ast.CallExpr:
+			p.addUsage(x, locals)
+		}
+
+		return true
+	})
+}
+
+func (p *pkg) addDescs(lhs, rhs []ast.Expr, locals map[string]string) {
+	for i, r := range rhs {
+		if i >= len(lhs) {
+			return
+		}
+
+		name, ok := p.descName(r, locals)
+		if !ok {
+			continue
+		}
+
+		k := key(lhs[i])
+		if k != "" && !slices.Contains(p.descs[k], name) {
+			p.descs[k] = append(p.descs[k], name)
+		}
+	}
+}
+
+func (p *pkg) addUsage(call *ast.CallExpr, locals map[string]string) {
+	if !isCall(call, "prometheus", "MustNewConstMetric") && !isCall(call, "prometheus", "NewConstMetric") {
+		return
+	}
+
+	if len(call.Args) < 2 {
+		return
+	}
+
+	t, ok := valueType(call.Args[1])
+	if !ok {
+		return
+	}
+
+	k := key(call.Args[0])
+	if name, ok := p.descName(call.Args[0], locals); ok {
+		// descriptor created in place
+		k = "inline:" + name
+		p.descs[k] = []string{name}
+	}
+
+	if k != "" && !slices.Contains(p.types[k], t) {
+		p.types[k] = append(p.types[k], t)
+	}
+}
+
+func (p *pkg) descName(e ast.Expr, locals map[string]string) (string, bool) {
+	call, ok := e.(*ast.CallExpr)
+	if !ok || !isNewDesc(call) || len(call.Args) == 0 {
+		return "", false
+	}
+
+	return p.eval(call.Args[0], locals, 0)
+}
+
+// eval evaluates a constant string expression
+func (p *pkg) eval(e ast.Expr, locals map[string]string, depth int) (string, bool) {
+	if depth > 10 {
+		return "", false
+	}
+
+	switch x := e.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.STRING {
+			return "", false
+		}
+
+		s, err := strconv.Unquote(x.Value)
+		return s, err == nil
+	case *ast.ParenExpr:
+		return p.eval(x.X, locals, depth+1)
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return "", false
+		}
+
+		l, ok := p.eval(x.X, locals, depth+1)
+		if !ok {
+			return "", false
+		}
+
+		r, ok := p.eval(x.Y, locals, depth+1)
+		return l + r, ok
+	case *ast.Ident:
+		if v, found := locals[x.Name]; found {
+			return v, true
+		}
+
+		if c, found := p.consts[x.Name]; found {
+			return p.eval(c, nil, depth+1)
+		}
+	}
+
+	return "", false
+}
+
+func (p *pkg) metrics(collector string) []*Metric {
+	res := make([]*Metric, 0)
+	for k, names := range p.descs {
+		t := dto.MetricType_GAUGE
+		if slices.Contains(p.types[k], dto.MetricType_COUNTER) {
+			t = dto.MetricType_COUNTER
+		}
+
+		for _, n := range names {
+			res = append(res, &Metric{Name: n, Collector: collector, Type: t})
+		}
+	}
+
+	return res
+}
+
+// key returns the name of the variable or field holding a descriptor
+func key(e ast.Expr) string {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	}
+
+	return ""
+}
+
+func isCall(call *ast.CallExpr, pkg, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg
+}
+
+// isNewDesc returns whether call is prometheus.NewDesc or the method of collector.MetricNames
+func isNewDesc(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "NewDesc" {
+		return false
+	}
+
+	_, ok = sel.X.(*ast.Ident)
+	return ok
+}
+
+func valueType(e ast.Expr) (dto.MetricType, bool) {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return dto.MetricType_UNTYPED, false
+	}
+
+	switch sel.Sel.Name {
+	case "CounterValue":
+		return dto.MetricType_COUNTER, true
+	case "GaugeValue":
+		return dto.MetricType_GAUGE, true
+	}
+
+	return dto.MetricType_UNTYPED, false
+}
+
+// WriteMarkdown writes the mapping table of the metrics whose name or type differs in the v2 scheme
+func WriteMarkdown(w io.Writer, metrics []*Metric) error {
+	var b strings.Builder
+	b.WriteString("# Metric names v2\n\n")
+	b.WriteString("<!-- Code generated by internal/metricnames/gen. DO NOT EDIT. -->\n\n")
+	b.WriteString("Metrics whose name or type differs in the v2 naming scheme (`-metrics.naming v2`).\n")
+	b.WriteString("All other metrics are reported with the same name and type in both schemes.\n\n")
+	b.WriteString("| Collector | v1 name | v1 type | v2 name | v2 type |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, m := range metrics {
+		name, t := naming.Name(m.Name, m.Type)
+		if name == m.Name && t == m.Type {
+			continue
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", m.Collector, m.Name, typeName(m.Type), name, typeName(t))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func typeName(t dto.MetricType) string {
+	return strings.ToLower(t.String())
+}
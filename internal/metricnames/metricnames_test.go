@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+
+package metricnames
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil/promlint"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+)
+
+func TestMarkdownUpToDate(t *testing.T) {
+	metrics, err := Scan("../../pkg/features")
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	assert.NoError(t, WriteMarkdown(&b, metrics))
+
+	expected, err := os.ReadFile("../../docs/metric-names-v2.md")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), b.String(), "docs/metric-names-v2.md is outdated, run go generate ./pkg/naming")
+}
+
+func TestScan(t *testing.T) {
+	metrics, err := Scan("../../pkg/features")
+	assert.NoError(t, err)
+
+	types := make(map[string]dto.MetricType)
+	for _, m := range metrics {
+		types[m.Name] = m.Type
+	}
+
+	assert.Equal(t, dto.MetricType_COUNTER, types["junos_interface_receive_bytes"])
+	assert.Equal(t, dto.MetricType_GAUGE, types["junos_bgp_session_messages_input_count"])
+	assert.Equal(t, dto.MetricType_GAUGE, types["junos_rpki_statistics_memory"], "name with local prefix")
+}
+
+func TestV2Names(t *testing.T) {
+	metrics, err := Scan("../../pkg/features")
+	assert.NoError(t, err)
+
+	v1 := make(map[string]bool)
+	for _, m := range metrics {
+		v1[m.Name] = true
+	}
+
+	v2 := make(map[string]string)
+	mfs := make([]*dto.MetricFamily, 0, len(metrics))
+	for _, m := range metrics {
+		name, typ := naming.Name(m.Name, m.Type)
+
+		if other, found := v2[name]; found {
+			t.Errorf("%s and %s have the same v2 name %s", other, m.Name, name)
+		}
+		v2[name] = m.Name
+
+		if name != m.Name && v1[name] {
+			t.Errorf("v2 name of %s is the v1 name of another metric", m.Name)
+		}
+
+		mfs = append(mfs, &dto.MetricFamily{Name: &name, Type: &typ})
+	}
+
+	problems, err := promlint.NewWithMetricFamilies(mfs).Lint()
+	assert.NoError(t, err)
+
+	for _, p := range problems {
+		if strings.Contains(p.Text, `"_total"`) || strings.Contains(p.Text, `"_count"`) {
+			t.Errorf("%s: %s", p.Metric, p.Text)
+		}
+	}
+}
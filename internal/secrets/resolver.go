@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+
+// Package secrets reads the secrets of credential profiles from their sources
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+const (
+	// DefaultExecTTL is the time the output of a command is cached if no TTL is configured
+	DefaultExecTTL = 5 * time.Minute
+
+	execTimeout = 30 * time.Second
+)
+
+// Resolver reads secrets from environment variables, files and commands.
+// The output of commands is cached for the TTL of the source, environment
+// variables and files are cached until Reset is called.
+type Resolver struct {
+	mu    sync.Mutex
+	cache map[string]*cachedSecret
+	now   func() time.Time
+	run   func(ctx context.Context, args []string) ([]byte, error)
+}
+
+// cachedSecret is the secret of a source. mu is held while the secret is
+// read, so only callers resolving the same source wait for a command.
+type cachedSecret struct {
+	mu      sync.Mutex
+	value   string
+	expires time.Time
+}
+
+// NewResolver creates a new resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		cache: make(map[string]*cachedSecret),
+		now:   time.Now,
+		run:   runCommand,
+	}
+}
+
+// Resolve returns the secret of a source. Errors never contain the secret itself.
+func (r *Resolver) Resolve(s *config.SecretSource) (string, error) {
+	switch {
+	case s.Env != "":
+		return r.cached("env\x00"+s.Env, 0, func() (string, error) {
+			return readEnv(s.Env)
+		})
+	case s.File != "":
+		return r.cached("file\x00"+s.File, 0, func() (string, error) {
+			return readFile(s.File)
+		})
+	case len(s.Exec) > 0:
+		ttl := s.TTL
+		if ttl == 0 {
+			ttl = DefaultExecTTL
+		}
+
+		return r.cached("exec\x00"+strings.Join(s.Exec, "\x00"), ttl, func() (string, error) {
+			return r.exec(s.Exec)
+		})
+	default:
+		return "", fmt.Errorf("no secret source set")
+	}
+}
+
+// cached returns the cached secret of key or reads it if it is not cached or
+// expired (a ttl of 0 caches the secret until Reset). Errors are not cached.
+func (r *Resolver) cached(key string, ttl time.Duration, read func() (string, error)) (string, error) {
+	r.mu.Lock()
+	c, found := r.cache[key]
+	if !found {
+		c = &cachedSecret{}
+		r.cache[key] = c
+	}
+	r.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.value != "" && (c.expires.IsZero() || r.now().Before(c.expires)) {
+		return c.value, nil
+	}
+
+	v, err := read()
+	if err != nil {
+		return "", err
+	}
+
+	c.value = v
+	c.expires = time.Time{}
+	if ttl > 0 {
+		c.expires = r.now().Add(ttl)
+	}
+
+	return v, nil
+}
+
+func readEnv(name string) (string, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return "", fmt.Errorf("environment variable %q is empty or unset", name)
+	}
+
+	return v, nil
+}
+
+func readFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func (r *Resolver) exec(args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	out, err := r.run(ctx, args)
+	if err != nil {
+		return "", fmt.Errorf("command %s failed: %w", args[0], err)
+	}
+
+	v := strings.TrimRight(string(out), "\r\n")
+	if v == "" {
+		return "", fmt.Errorf("command %s returned an empty secret", args[0])
+	}
+
+	return v, nil
+}
+
+// Reset drops all cached secrets, so they are read again on next use
+func (r *Resolver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.cache)
+}
+
+// runCommand runs a command and returns its stdout. The output is not part of
+// the error, since it might contain the secret.
+func runCommand(ctx context.Context, args []string) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
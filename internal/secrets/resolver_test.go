@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+func TestResolveEnvAndFile(t *testing.T) {
+	t.Setenv("JUNOS_TEST_SECRET", "from-env")
+
+	f := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(f, []byte("from-file\n"), 0600))
+
+	r := NewResolver()
+
+	v, err := r.Resolve(&config.SecretSource{Env: "JUNOS_TEST_SECRET"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", v)
+
+	v, err = r.Resolve(&config.SecretSource{File: f})
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", v)
+
+	_, err = r.Resolve(&config.SecretSource{Env: "JUNOS_TEST_SECRET_UNSET"})
+	assert.Error(t, err)
+
+	t.Setenv("JUNOS_TEST_SECRET", "rotated")
+	assert.NoError(t, os.WriteFile(f, []byte("rotated\n"), 0600))
+
+	v, _ = r.Resolve(&config.SecretSource{Env: "JUNOS_TEST_SECRET"})
+	assert.Equal(t, "from-env", v, "cached until reset")
+	v, _ = r.Resolve(&config.SecretSource{File: f})
+	assert.Equal(t, "from-file", v, "cached until reset")
+
+	r.Reset()
+	v, _ = r.Resolve(&config.SecretSource{Env: "JUNOS_TEST_SECRET"})
+	assert.Equal(t, "rotated", v)
+	v, _ = r.Resolve(&config.SecretSource{File: f})
+	assert.Equal(t, "rotated", v)
+}
+
+func TestResolveExecShouldCacheOutput(t *testing.T) {
+	now := time.Now()
+	calls := 0
+
+	r := NewResolver()
+	r.now = func() time.Time { return now }
+	r.run = func(_ context.Context, args []string) ([]byte, error) {
+		calls++
+		return []byte(args[1] + "\n"), nil
+	}
+
+	s := &config.SecretSource{Exec: []string{"echo", "s3cret"}, TTL: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		v, err := r.Resolve(s)
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cret", v)
+	}
+	assert.Equal(t, 1, calls, "command should run once within the TTL")
+
+	now = now.Add(time.Minute)
+	_, err := r.Resolve(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls, "command should run again after the TTL")
+
+	r.Reset()
+	_, err = r.Resolve(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls, "command should run again after reset")
+}
+
+func TestResolveExecShouldNotBlockOtherSources(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	r := NewResolver()
+	r.run = func(_ context.Context, args []string) ([]byte, error) {
+		if args[1] == "slow" {
+			<-release
+		}
+
+		return []byte(args[1]), nil
+	}
+
+	go r.Resolve(&config.SecretSource{Exec: []string{"echo", "slow"}})
+
+	done := make(chan string)
+	go func() {
+		v, _ := r.Resolve(&config.SecretSource{Exec: []string{"echo", "fast"}})
+		done <- v
+	}()
+
+	select {
+	case v := <-done:
+		assert.Equal(t, "fast", v)
+	case <-time.After(5 * time.Second):
+		t.Fatal("command of another source waits for a running command")
+	}
+}
+
+func TestResolveExecShouldNotLeakOutput(t *testing.T) {
+	_, err := NewResolver().Resolve(&config.SecretSource{Exec: []string{"sh", "-c", "echo s3cret; exit 1"}})
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cret")
+
+	v, err := NewResolver().Resolve(&config.SecretSource{Exec: []string{"sh", "-c", "echo s3cret"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", v)
+}
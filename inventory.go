@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// inventorySource is a list of devices loaded from a device file or an HTTP inventory
+type inventorySource struct {
+	name    string
+	devices []*config.DeviceConfig
+}
+
+// configWithInventories returns a copy of the config extended by the devices
+// of the device files and HTTP inventories. Devices already defined in the
+// config or another source are ignored.
+func (a *app) configWithInventories(c *config.Config) *config.Config {
+	a.httpInventory.sync(c)
+
+	sources := a.deviceFiles.devices(c)
+	sources = append(sources, a.httpInventory.devices(c)...)
+	if len(sources) == 0 {
+		return c
+	}
+
+	c.InitTargetDevices()
+
+	targets := make(map[string]bool)
+	for _, d := range c.Devices {
+		targets[d.TargetName()] = true
+	}
+
+	merged := make([]*config.DeviceConfig, 0)
+	for _, s := range sources {
+		for _, d := range s.devices {
+			if targets[d.TargetName()] {
+				log.Errorf("%s: device %s is already defined, ignoring it", s.name, d.TargetName())
+				continue
+			}
+
+			targets[d.TargetName()] = true
+			merged = append(merged, d)
+		}
+	}
+
+	return c.WithDevices(merged)
+}
+
+// applyInventories merges the current devices of the inventories into the
+// running config. The caller has to hold the write lock of a.mu.
+func (a *app) applyInventories() error {
+	err := a.exp.Reload(a.configWithInventories(a.baseCfg))
+	if err != nil {
+		return fmt.Errorf("could not initialize devices: %w", err)
+	}
+
+	return nil
+}
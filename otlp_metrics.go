@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/otlpmetrics"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runOTLPMetrics scrapes all devices in the interval of the otlp_metrics
+// section of the config and exports the metrics via OTLP. Changes of the
+// section are applied on reload.
+func (a *app) runOTLPMetrics(ctx context.Context) {
+	var e *otlpmetrics.Exporter
+	var current *config.OTLPMetricsConfig
+	interval := otlpmetrics.DefaultInterval
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	defer func() {
+		if e != nil {
+			shutdownOTLPMetrics(e)
+		}
+	}()
+
+	for {
+		cfg := a.otlpMetricsConfig()
+		if !reflect.DeepEqual(cfg, current) {
+			if e != nil {
+				shutdownOTLPMetrics(e)
+				e = nil
+			}
+
+			// current is only set once the exporter is created, so it is created again in the next interval on errors
+			current = nil
+			if cfg != nil {
+				var err error
+				log.Infof("Exporting metrics via OTLP to %s", cfg.Endpoint)
+				e, err = otlpmetrics.New(ctx, cfg, resourceDefinition(), a.exp.StaticLabels)
+				if err != nil {
+					log.Errorf("Could not create OTLP exporter, retrying in the next interval: %s", err)
+				} else {
+					current = cfg
+				}
+
+				i := cfg.Interval
+				if i <= 0 {
+					i = otlpmetrics.DefaultInterval
+				}
+
+				if i != interval {
+					interval = i
+					ticker.Reset(interval)
+				}
+			}
+		}
+
+		if e != nil {
+			a.exportMetrics(ctx, e, interval)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *app) otlpMetricsConfig() *config.OTLPMetricsConfig {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.baseCfg.OTLPMetrics
+}
+
+// exportMetrics scrapes all devices and exports the result. The scrape and
+// the export are abandoned after timeout, so they do not overlap with the next one.
+func (a *app) exportMetrics(ctx context.Context, e *otlpmetrics.Exporter, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	mfs, err := a.exp.Gather(ctx)
+	if err != nil {
+		log.Errorf("Error while scraping devices for OTLP export: %v", err)
+	}
+
+	err = e.Export(ctx, mfs)
+	if err != nil {
+		log.Errorf("Error while exporting metrics via OTLP: %v", err)
+	}
+}
+
+func shutdownOTLPMetrics(e *otlpmetrics.Exporter) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := e.Shutdown(ctx); err != nil {
+		log.Errorf("failed to shutdown OTLP metrics exporter: %v", err)
+	}
+}
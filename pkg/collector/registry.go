@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: MIT
+
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+// Registration describes a collector known to the exporter. Flags, config
+// parsing and the collectors of a device are derived from the registrations.
+type Registration struct {
+	// Key identifies the collector (e.g. "iface")
+	Key string
+
+	// Feature is the name of the feature in the features section of the config file (e.g. "interfaces")
+	Feature string
+
+	// Flag is the name of the command line flag enabling the feature (e.g. "interfaces.enabled")
+	Flag string
+
+	// Help is the description of the flag
+	Help string
+
+	// DefaultEnabled is whether the feature is enabled by default
+	DefaultEnabled bool
+
+	// EnabledBy are other features enabling the collector as well
+	EnabledBy []string
+
+	// LogicalSystems is whether the collector can be scraped per logical system
+	LogicalSystems bool
+
+	// RoutingInstances is whether the collector can be scraped per routing instance
+	RoutingInstances bool
+
+	// Commands are the commands run by the collector, including the variants for logical systems
+	// and routing instances. Values are replaced by * as by rpc.NormalizeCommand. Only these
+	// commands are accepted by the debug RPC endpoint of the exporter for the collector.
+	Commands []string
+
+	// New creates the collector. It is nil for features without collector (e.g. options of the RPC client).
+	New func(p *Params) RPCCollector
+}
+
+// RunsCommand returns whether a command is one of the commands of the collector
+func (r Registration) RunsCommand(cmd string) bool {
+	tokens := strings.Fields(rpc.NormalizeCommand(cmd))
+	for _, c := range r.Commands {
+		if matchCommand(strings.Fields(c), tokens) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCommand returns whether the tokens of a command match a pattern, * matches any token
+func matchCommand(pattern, tokens []string) bool {
+	if len(pattern) != len(tokens) {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p != "*" && p != tokens[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Params are the parameters a collector is created with
+type Params struct {
+	LogicalSystem   string
+	RoutingInstance string
+
+	// InterfaceDescriptionRegex is the regex used to get labels from interface descriptions
+	InterfaceDescriptionRegex *regexp.Regexp
+
+	// Options are the collector options configured for the device (the type is defined by the collector)
+	Options any
+
+	// DroppedMetric returns whether all series of a metric are dropped by the relabel rules of the device (nil: no metric is dropped)
+	DroppedMetric func(name string) bool
+}
+
+// Dropped returns whether all series of the metrics with the fully-qualified names are dropped by the
+// relabel rules of the device, so a collector can skip the RPCs needed for them (see MetricNames)
+func (p *Params) Dropped(names ...string) bool {
+	if p == nil || p.DroppedMetric == nil || len(names) == 0 {
+		return false
+	}
+
+	for _, name := range names {
+		if !p.DroppedMetric(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MetricNames creates metric descriptions and keeps the fully-qualified names of their metrics,
+// since prometheus.Desc has no accessor for the name
+type MetricNames struct {
+	names []string
+}
+
+// NewDesc creates a description like prometheus.NewDesc and adds the name of its metric
+func (m *MetricNames) NewDesc(fqName, help string, variableLabels []string, constLabels prometheus.Labels) *prometheus.Desc {
+	m.names = append(m.names, fqName)
+	return prometheus.NewDesc(fqName, help, variableLabels, constLabels)
+}
+
+// Names returns the fully-qualified names of the metrics of the descriptions created
+func (m *MetricNames) Names() []string {
+	return m.names
+}
+
+// OptionsOf returns the options of type T passed to a collector (zero value if no options are set)
+func OptionsOf[T any](p *Params) T {
+	o, _ := p.Options.(T)
+	return o
+}
+
+// Registry holds the registered collectors
+type Registry struct {
+	mu            sync.RWMutex
+	registrations map[string]Registration
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		registrations: make(map[string]Registration),
+	}
+}
+
+// DefaultRegistry is the registry the collectors register with in their init function (see Register)
+var DefaultRegistry = NewRegistry()
+
+// Register registers a collector with the default registry. It is meant to be called in the init function
+// of the package implementing the collector and panics if the key, feature or flag is already registered.
+func Register(r Registration) {
+	DefaultRegistry.Register(r)
+}
+
+// Registrations returns all collectors registered with the default registry ordered by key
+func Registrations() []Registration {
+	return DefaultRegistry.Registrations()
+}
+
+// RegistrationForFeature returns the registration of a feature in the default registry
+func RegistrationForFeature(feature string) (Registration, bool) {
+	return DefaultRegistry.RegistrationForFeature(feature)
+}
+
+// Register registers a collector. It panics if the key, feature or flag is already registered.
+func (reg *Registry) Register(r Registration) {
+	if r.Key == "" || r.Feature == "" {
+		panic("collector: key and feature must be set")
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for _, e := range reg.registrations {
+		if e.Key == r.Key || e.Feature == r.Feature || (r.Flag != "" && e.Flag == r.Flag) {
+			panic(fmt.Sprintf("collector: %s (feature %s) is already registered", r.Key, r.Feature))
+		}
+	}
+
+	reg.registrations[r.Key] = r
+}
+
+// Registrations returns all registered collectors ordered by key
+func (reg *Registry) Registrations() []Registration {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	regs := make([]Registration, 0, len(reg.registrations))
+	for _, r := range reg.registrations {
+		regs = append(regs, r)
+	}
+
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].Key < regs[j].Key
+	})
+
+	return regs
+}
+
+// RegistrationForFeature returns the registration of a feature
+func (reg *Registry) RegistrationForFeature(feature string) (Registration, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	for _, r := range reg.registrations {
+		if r.Feature == feature {
+			return r, true
+		}
+	}
+
+	return Registration{}, false
+}
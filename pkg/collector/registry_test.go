@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(Registration{Key: "test_b", Feature: "test_b", Flag: "test-b.enabled"})
+	reg.Register(Registration{Key: "test_a", Feature: "test_a"})
+
+	keys := make([]string, 0)
+	for _, r := range reg.Registrations() {
+		keys = append(keys, r.Key)
+	}
+	assert.Equal(t, []string{"test_a", "test_b"}, keys, "ordered by key")
+
+	r, found := reg.RegistrationForFeature("test_b")
+	assert.True(t, found)
+	assert.Equal(t, "test-b.enabled", r.Flag)
+
+	_, found = RegistrationForFeature("test_b")
+	assert.False(t, found, "not in the default registry")
+
+	assert.Panics(t, func() {
+		reg.Register(Registration{Key: "test_c", Feature: "test_c", Flag: "test-b.enabled"})
+	}, "duplicate flag")
+	assert.Panics(t, func() {
+		reg.Register(Registration{Key: "test_a", Feature: "test_x"})
+	}, "duplicate key")
+}
+
+func TestOptionsOf(t *testing.T) {
+	type options struct {
+		Filter string
+	}
+
+	assert.Equal(t, options{Filter: "x"}, OptionsOf[options](&Params{Options: options{Filter: "x"}}))
+	assert.Equal(t, options{}, OptionsOf[options](&Params{}))
+}
+
+func TestParamsDropped(t *testing.T) {
+	p := &Params{DroppedMetric: func(name string) bool {
+		return strings.HasPrefix(name, "junos_nat_pool_")
+	}}
+	assert.True(t, p.Dropped("junos_nat_pool_users"))
+	assert.False(t, p.Dropped("junos_nat_pool_users", "junos_nat_service_set_sessions"), "not all metrics dropped")
+	assert.False(t, p.Dropped())
+
+	assert.False(t, (&Params{}).Dropped("junos_nat_pool_users"), "no relabel rules")
+	assert.False(t, (*Params)(nil).Dropped("junos_nat_pool_users"))
+}
+
+func TestMetricNames(t *testing.T) {
+	var m MetricNames
+	pool := m.NewDesc("junos_nat_pool_users", "", []string{"target"}, nil)
+	m.NewDesc("junos_nat_service_set_sessions", "", []string{"target"}, nil)
+
+	assert.Equal(t, prometheus.NewDesc("junos_nat_pool_users", "", []string{"target"}, nil).String(), pool.String())
+	assert.Equal(t, []string{"junos_nat_pool_users", "junos_nat_service_set_sessions"}, m.Names())
+}
+
+func TestRunsCommand(t *testing.T) {
+	r := Registration{Commands: []string{
+		"show bgp neighbor",
+		"show bgp neighbor instance *",
+		"show interfaces extensive *",
+	}}
+
+	assert.True(t, r.RunsCommand("show bgp neighbor"))
+	assert.True(t, r.RunsCommand("show  bgp neighbor instance CUSTOMER-A"))
+	assert.True(t, r.RunsCommand("show interfaces extensive ae0"), "keyword-like values match *")
+	assert.True(t, r.RunsCommand("show interfaces extensive xe-0/0/0"))
+	assert.False(t, r.RunsCommand("show bgp neighbor 192.0.2.1 instance CUSTOMER-A"))
+	assert.False(t, r.RunsCommand("show bgp"))
+	assert.False(t, (Registration{}).RunsCommand("show bgp neighbor"))
+}
@@ -41,3 +41,11 @@ type RPCCollector interface {
 	// Collect collects metrics from JunOS
 	Collect(client Client, ch chan<- prometheus.Metric, labelValues []string) error
 }
+
+// StateCollector is implemented by collectors providing the parsed RPC results
+// as structured data (served as JSON by the API of the exporter). The JSON
+// representation of the result is part of the versioned API schema.
+type StateCollector interface {
+	// State runs the RPCs of the collector and returns the parsed results
+	State(client Client) (any, error)
+}
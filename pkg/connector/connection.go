@@ -4,6 +4,7 @@ package connector
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net"
 	"sync"
@@ -26,6 +27,7 @@ type SSHConnection struct {
 	done              chan struct{}
 	keepAliveInterval time.Duration
 	keepAliveTimeout  time.Duration
+	metrics           *Metrics
 }
 
 func NewSSHConnection(device *Device, keepAliveInterval time.Duration, keepAliveTimeout time.Duration) *SSHConnection {
@@ -70,10 +72,22 @@ func (c *SSHConnection) Stop(err error) {
 	}
 
 	c.isConnected = false
+	c.metrics.connectionClosed()
 }
 
 // RunCommand runs a command against the device
 func (c *SSHConnection) RunCommand(cmd string) ([]byte, error) {
+	return c.RunCommandContext(context.Background(), cmd)
+}
+
+// RunCommandContext runs a command against the device. If ctx is done before
+// the command finished, the session of the command is closed. The connection
+// is kept open in this case.
+func (c *SSHConnection) RunCommandContext(ctx context.Context, cmd string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	c.setLastUsed(time.Now())
 
 	sshClient := c.getSSHClient()
@@ -84,15 +98,25 @@ func (c *SSHConnection) RunCommand(cmd string) ([]byte, error) {
 
 	session, err := c.sshClient.NewSession()
 	if err != nil {
+		c.metrics.sessionFailed(c.device.Host)
 		c.Stop(fmt.Errorf("SSH session failure"))
 		return nil, fmt.Errorf("could not open session with %s: %w", c.device.Host, err)
 	}
 	defer session.Close()
 
+	stop := context.AfterFunc(ctx, func() {
+		session.Close()
+	})
+	defer stop()
+
 	var b = &bytes.Buffer{}
 	session.Stdout = b
 
 	err = session.Run(cmd)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("command %q on %s aborted: %w", cmd, c.device.Host, ctx.Err())
+	}
+
 	if err != nil {
 		c.Stop(fmt.Errorf("failed running command"))
 		return nil, fmt.Errorf("could not run command %q on %s: %w", cmd, c.device.Host, err)
@@ -137,6 +161,7 @@ func (c *SSHConnection) testSSHClient() bool {
 	_, _, err := sshClient.SendRequest("keepalive@golang.org", true, nil)
 	if err != nil {
 		log.Infof("SSH keepalive request to %s failed: %v", c.device, err)
+		c.metrics.keepAliveFailed(c.device.Host)
 		c.Stop(fmt.Errorf("keepalive failed"))
 		return false
 	}
@@ -152,7 +177,7 @@ func (c *SSHConnection) connect() error {
 
 	c.device.Auth(cfg)
 
-	host := tcpAddressForHost(c.device.Host)
+	host := tcpAddressForDevice(c.device)
 	log.Infof("Establishing TCP connection with %s", host)
 
 	tcpConn, err := net.DialTimeout("tcp", host, cfg.Timeout)
@@ -172,6 +197,7 @@ func (c *SSHConnection) connect() error {
 	c.tcpConn = tcpConn
 	c.sshClient = ssh.NewClient(sshConn, chans, reqs)
 	c.isConnected = true
+	c.metrics.connectionOpened()
 
 	return nil
 }
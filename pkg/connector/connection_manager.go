@@ -5,6 +5,7 @@ package connector
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +47,13 @@ func WithExpiredConnectionTimeout(d time.Duration) Option {
 	}
 }
 
+// WithMetrics records the metrics of the SSH connections in m
+func WithMetrics(m *Metrics) Option {
+	return func(cm *SSHConnectionManager) {
+		cm.metrics = m
+	}
+}
+
 // SSHConnectionManager manages SSH connections to different devices
 type SSHConnectionManager struct {
 	connections              map[string]*SSHConnection
@@ -54,6 +62,7 @@ type SSHConnectionManager struct {
 	keepAliveInterval        time.Duration
 	keepAliveTimeout         time.Duration
 	expiredConnectionTimeout time.Duration
+	metrics                  *Metrics
 }
 
 // NewConnectionManager creates a new connection manager
@@ -98,9 +107,21 @@ func (m *SSHConnectionManager) getExistingConnection(device *Device) *SSHConnect
 
 func (m *SSHConnectionManager) connect(device *Device) (*SSHConnection, error) {
 	log.Infof("Creating SSH connection with %s", device.Host)
-	c := NewSSHConnection(device, m.keepAliveInterval, m.keepAliveTimeout)
+	keepAliveInterval := m.keepAliveInterval
+	if device.KeepAliveInterval > 0 {
+		keepAliveInterval = device.KeepAliveInterval
+	}
+
+	keepAliveTimeout := m.keepAliveTimeout
+	if device.KeepAliveTimeout > 0 {
+		keepAliveTimeout = device.KeepAliveTimeout
+	}
+
+	c := NewSSHConnection(device, keepAliveInterval, keepAliveTimeout)
+	c.metrics = m.metrics
 	err := c.Start(m.expiredConnectionTimeout)
 	if err != nil {
+		m.metrics.connectFailed(device.Host)
 		return nil, fmt.Errorf("unable to get new SSH connection: %w", err)
 	}
 
@@ -112,10 +133,35 @@ func (m *SSHConnectionManager) connect(device *Device) (*SSHConnection, error) {
 		return existingCon, nil
 	}
 
+	_, reconnect := m.connections[device.Host]
+	m.metrics.connected(device.Host, reconnect)
+
 	m.connections[device.Host] = c
 	return c, nil
 }
 
+func tcpAddressForDevice(device *Device) string {
+	host := device.Host
+	if device.Address != "" {
+		host = device.Address
+	}
+
+	if device.Port == 0 || hostHasPort(host) {
+		return tcpAddressForHost(host)
+	}
+
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(device.Port))
+}
+
+func hostHasPort(host string) bool {
+	if !strings.Contains(host, ":") {
+		return false
+	}
+
+	_, _, err := net.SplitHostPort(host)
+	return err == nil
+}
+
 func tcpAddressForHost(host string) string {
 	colonCount := strings.Count(host, ":")
 
@@ -143,6 +189,31 @@ func formatHost(host string) string {
 	return "[" + host + "]"
 }
 
+// Devices returns the devices the manager holds connections for
+func (m *SSHConnectionManager) Devices() []*Device {
+	m.connectionsMu.RLock()
+	defer m.connectionsMu.RUnlock()
+
+	devices := make([]*Device, 0, len(m.connections))
+	for _, c := range m.connections {
+		devices = append(devices, c.Device())
+	}
+
+	return devices
+}
+
+// Close closes the connection to a host and stops its keep alive
+func (m *SSHConnectionManager) Close(host string) {
+	m.connectionsMu.Lock()
+	c, found := m.connections[host]
+	delete(m.connections, host)
+	m.connectionsMu.Unlock()
+
+	if found {
+		c.Stop(fmt.Errorf("connection closed by manager"))
+	}
+}
+
 // CloseAll closes all TCP connections and stops keep alives
 func (m *SSHConnectionManager) CloseAll() {
 	for _, c := range m.connections {
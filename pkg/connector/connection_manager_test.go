@@ -3,9 +3,12 @@
 package connector
 
 import (
+	"net"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTCPAddressForHost(t *testing.T) {
@@ -59,3 +62,83 @@ func TestTCPAddressForHost(t *testing.T) {
 		})
 	}
 }
+
+func TestTCPAddressForDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		device   *Device
+		expected string
+	}{
+		{
+			name:     "hostname with configured port",
+			device:   &Device{Host: "test.routing.rocks", Port: 2222},
+			expected: "test.routing.rocks:2222",
+		},
+		{
+			name:     "port in host takes precedence",
+			device:   &Device{Host: "test.routing.rocks:22", Port: 2222},
+			expected: "test.routing.rocks:22",
+		},
+		{
+			name:     "IPv6 with configured port",
+			device:   &Device{Host: "[2001:678:1e0:f00::1]", Port: 2222},
+			expected: "[2001:678:1e0:f00::1]:2222",
+		},
+		{
+			name:     "IPv6 without brackets with configured port",
+			device:   &Device{Host: "2001:678:1e0:f00::1", Port: 2222},
+			expected: "[2001:678:1e0:f00::1]:2222",
+		},
+		{
+			name:     "address differing from host",
+			device:   &Device{Host: "fra1-pe1", Address: "10.12.0.5"},
+			expected: "10.12.0.5:22",
+		},
+		{
+			name:     "address with configured port",
+			device:   &Device{Host: "fra1-pe1", Address: "10.12.0.5", Port: 2222},
+			expected: "10.12.0.5:2222",
+		},
+		{
+			name:     "no configured port",
+			device:   &Device{Host: "127.0.0.1"},
+			expected: "127.0.0.1:22",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, tcpAddressForDevice(test.device))
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	m := NewConnectionManager()
+	m.connections["router1"] = NewSSHConnection(&Device{Host: "router1"}, m.keepAliveInterval, m.keepAliveTimeout)
+	m.connections["router2"] = NewSSHConnection(&Device{Host: "router2"}, m.keepAliveInterval, m.keepAliveTimeout)
+
+	m.Close("router1")
+	m.Close("router3")
+
+	devices := m.Devices()
+	assert.Equal(t, 1, len(devices), "device count")
+	assert.Equal(t, "router2", devices[0].Host)
+}
+
+func TestConnectionManagerMetrics(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	l.Close()
+
+	m := NewMetrics()
+	cm := NewConnectionManager(WithMetrics(m))
+
+	_, err = cm.GetSSHConnection(&Device{Host: "router1", Address: addr, Auth: AuthByPassword("user", "secret")})
+	require.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.connectFailures.WithLabelValues("router1")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.connects.WithLabelValues("router1")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.connections))
+}
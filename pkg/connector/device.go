@@ -4,6 +4,7 @@ package connector
 
 import (
 	"io"
+	"time"
 
 	"golang.org/x/crypto/ssh"
 )
@@ -12,6 +13,20 @@ import (
 type Device struct {
 	Host string
 	Auth AuthMethod
+
+	// Address is the address to connect to if it differs from Host (e.g. Host is a friendly name)
+	Address string
+
+	// AuthID identifies the credentials used by Auth. Existing connections
+	// are kept on config reload as long as it does not change.
+	AuthID string
+
+	// Port is the SSH port to connect to if Host does not contain a port (default 22)
+	Port int
+
+	// KeepAliveInterval and KeepAliveTimeout override the settings of the connection manager if set
+	KeepAliveInterval time.Duration
+	KeepAliveTimeout  time.Duration
 }
 
 // AuthMethod is the method to use to authenticate agaist the device
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+
+package connector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are metrics of the SSH connections of a connection manager
+type Metrics struct {
+	connects          *prometheus.CounterVec
+	connectFailures   *prometheus.CounterVec
+	reconnects        *prometheus.CounterVec
+	keepAliveFailures *prometheus.CounterVec
+	sessionFailures   *prometheus.CounterVec
+	connections       prometheus.Gauge
+}
+
+// NewMetrics creates the metrics of SSH connections. The metrics have to be
+// registered to be exposed, managers record them when created with WithMetrics.
+func NewMetrics() *Metrics {
+	l := []string{"target"}
+
+	return &Metrics{
+		connects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_ssh_connects_total",
+			Help: "Number of established SSH connections",
+		}, l),
+		connectFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_ssh_connect_failures_total",
+			Help: "Number of failed attempts to establish an SSH connection",
+		}, l),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_ssh_reconnects_total",
+			Help: "Number of SSH connections established to replace a lost or expired connection",
+		}, l),
+		keepAliveFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_ssh_keepalive_failures_total",
+			Help: "Number of failed keep alive requests",
+		}, l),
+		sessionFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_ssh_session_failures_total",
+			Help: "Number of SSH sessions which could not be opened",
+		}, l),
+		connections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "junos_exporter_ssh_connections",
+			Help: "Number of active SSH connections",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector interface
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.connects.Describe(ch)
+	m.connectFailures.Describe(ch)
+	m.reconnects.Describe(ch)
+	m.keepAliveFailures.Describe(ch)
+	m.sessionFailures.Describe(ch)
+	m.connections.Describe(ch)
+}
+
+// Collect implements prometheus.Collector interface
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.connects.Collect(ch)
+	m.connectFailures.Collect(ch)
+	m.reconnects.Collect(ch)
+	m.keepAliveFailures.Collect(ch)
+	m.sessionFailures.Collect(ch)
+	m.connections.Collect(ch)
+}
+
+func (m *Metrics) connected(host string, reconnect bool) {
+	if m == nil {
+		return
+	}
+
+	m.connects.WithLabelValues(host).Inc()
+	if reconnect {
+		m.reconnects.WithLabelValues(host).Inc()
+	}
+}
+
+func (m *Metrics) connectFailed(host string) {
+	if m == nil {
+		return
+	}
+
+	m.connectFailures.WithLabelValues(host).Inc()
+}
+
+func (m *Metrics) keepAliveFailed(host string) {
+	if m == nil {
+		return
+	}
+
+	m.keepAliveFailures.WithLabelValues(host).Inc()
+}
+
+func (m *Metrics) sessionFailed(host string) {
+	if m == nil {
+		return
+	}
+
+	m.sessionFailures.WithLabelValues(host).Inc()
+}
+
+func (m *Metrics) connectionOpened() {
+	if m == nil {
+		return
+	}
+
+	m.connections.Inc()
+}
+
+func (m *Metrics) connectionClosed() {
+	if m == nil {
+		return
+	}
+
+	m.connections.Dec()
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: MIT
+
+package discovery
+
+import (
+	"fmt"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+type systemInformationResult struct {
+	Information struct {
+		Hostname string `xml:"host-name"`
+	} `xml:"system-information"`
+}
+
+// Hostname returns the hostname configured on the device
+func Hostname(client collector.Client) (string, error) {
+	var x systemInformationResult
+	err := client.RunCommandAndParse("show system information", &x)
+	if err != nil {
+		return "", err
+	}
+
+	if x.Information.Hostname == "" {
+		return "", fmt.Errorf("no hostname configured")
+	}
+
+	return x.Information.Hostname, nil
+}
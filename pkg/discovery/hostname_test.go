@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package discovery
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostname(t *testing.T) {
+	body := `
+<rpc-reply xmlns:junos="http://xml.juniper.net/junos/23.4R1/junos">
+    <system-information>
+        <hardware-model>mx204</hardware-model>
+        <os-name>junos</os-name>
+        <os-version>23.4R1.9</os-version>
+        <serial-number>AB123</serial-number>
+        <host-name>fra1-pe1</host-name>
+    </system-information>
+</rpc-reply>`
+
+	var x systemInformationResult
+	err := xml.Unmarshal([]byte(body), &x)
+	assert.NoError(t, err)
+	assert.Equal(t, "fra1-pe1", x.Information.Hostname)
+}
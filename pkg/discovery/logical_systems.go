@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+
+package discovery
+
+import (
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+type logicalSystemsResult struct {
+	Information struct {
+		LogicalSystems []struct {
+			Name string `xml:"logical-system-name"`
+		} `xml:"logical-system"`
+	} `xml:"logical-system-information"`
+}
+
+// LogicalSystems returns the names of the logical systems configured on the device
+func LogicalSystems(client collector.Client) ([]string, error) {
+	var x logicalSystemsResult
+	err := client.RunCommandAndParse("show logical-systems", &x)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.names(), nil
+}
+
+func (x *logicalSystemsResult) names() []string {
+	names := make([]string, 0, len(x.Information.LogicalSystems))
+	for _, ls := range x.Information.LogicalSystems {
+		if ls.Name == "" {
+			continue
+		}
+
+		names = append(names, ls.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: MIT
+
+package discovery
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogicalSystems(t *testing.T) {
+	body := `
+<rpc-reply xmlns:junos="http://xml.juniper.net/junos/23.4R1/junos">
+    <logical-system-information>
+        <logical-system>
+            <logical-system-name>customer-a</logical-system-name>
+        </logical-system>
+        <logical-system>
+            <logical-system-name>customer-b</logical-system-name>
+        </logical-system>
+        <logical-system>
+            <logical-system-name></logical-system-name>
+        </logical-system>
+    </logical-system-information>
+</rpc-reply>`
+
+	var x logicalSystemsResult
+	err := xml.Unmarshal([]byte(body), &x)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"customer-a", "customer-b"}, x.names())
+}
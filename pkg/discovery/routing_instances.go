@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+
+package discovery
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+// routingInstanceTypes are the instance types running their own routing protocols
+var routingInstanceTypes = []string{"vrf", "virtual-router"}
+
+type routingInstancesResult struct {
+	Information struct {
+		Instances []struct {
+			Name string `xml:"instance-name"`
+			Type string `xml:"instance-type"`
+		} `xml:"instance-core"`
+	} `xml:"instance-information"`
+}
+
+// RoutingInstances returns the names of the VRF and virtual-router instances configured on the device
+func RoutingInstances(client collector.Client) ([]string, error) {
+	var x routingInstancesResult
+	err := client.RunCommandAndParse("show route instance", &x)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.names(), nil
+}
+
+func (x *routingInstancesResult) names() []string {
+	names := make([]string, 0, len(x.Information.Instances))
+	for _, inst := range x.Information.Instances {
+		if strings.HasPrefix(inst.Name, "__") || !slices.Contains(routingInstanceTypes, inst.Type) {
+			continue
+		}
+
+		names = append(names, inst.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: MIT
+
+package discovery
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingInstances(t *testing.T) {
+	body := `
+<rpc-reply xmlns:junos="http://xml.juniper.net/junos/23.4R1/junos">
+    <instance-information xmlns="http://xml.juniper.net/junos/23.4R1/junos-routing" junos:style="terse">
+        <instance-core>
+            <instance-name>master</instance-name>
+            <instance-type>forwarding</instance-type>
+        </instance-core>
+        <instance-core>
+            <instance-name>__juniper_private1__</instance-name>
+            <instance-type>forwarding</instance-type>
+        </instance-core>
+        <instance-core>
+            <instance-name>CUSTOMER-A</instance-name>
+            <instance-type>vrf</instance-type>
+        </instance-core>
+        <instance-core>
+            <instance-name>MGMT</instance-name>
+            <instance-type>virtual-router</instance-type>
+        </instance-core>
+        <instance-core>
+            <instance-name>VS1</instance-name>
+            <instance-type>virtual-switch</instance-type>
+        </instance-core>
+    </instance-information>
+</rpc-reply>`
+
+	var x routingInstancesResult
+	err := xml.Unmarshal([]byte(body), &x)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"CUSTOMER-A", "MGMT"}, x.names())
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// APIVersion is the version of the schema of the JSON API. Incompatible
+// changes of the schema (including the JSON representation of the state of
+// the collectors) require a new version.
+const APIVersion = "v1"
+
+var (
+	// ErrUnknownTarget is returned if the target is not in the config
+	ErrUnknownTarget = errors.New("unknown target")
+
+	// ErrUnknownCollector is returned if the collector is unknown, disabled for the device or does not provide its state
+	ErrUnknownCollector = errors.New("unknown collector")
+)
+
+type apiResponse struct {
+	APIVersion string     `json:"api_version"`
+	Target     string     `json:"target,omitempty"`
+	Collector  string     `json:"collector,omitempty"`
+	Timestamp  *time.Time `json:"timestamp,omitempty"`
+	Data       any        `json:"data,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type apiTarget struct {
+	Target     string   `json:"target"`
+	Collectors []string `json:"collectors"`
+}
+
+// State runs the RPCs of the collector of a feature (e.g. "bgp") against the
+// device of the target and returns the parsed results. The same connection
+// and settings are used as for scrapes.
+func (e *Exporter) State(ctx context.Context, target, feature string) (any, error) {
+	cfg, devs := e.snapshot()
+
+	ctx, span := tracer.Start(ctx, "State", trace.WithAttributes(
+		attribute.String("target", target),
+		attribute.String("collector", feature),
+	))
+	defer span.End()
+
+	d, err := e.deviceForTarget(target, devs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+
+	col := e.stateCollector(cfg, d, feature)
+	if col == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCollector, feature)
+	}
+
+	cl, err := e.clientForDevice(d, cfg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("could not connect to %s: %w", target, err)
+	}
+
+	s, err := col.State(&clientTracingAdapter{cl: cl, ctx: ctx})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// stateCollector returns the collector of the feature if it is enabled for the device and provides its state
+func (e *Exporter) stateCollector(cfg *config.Config, d *connector.Device, feature string) collector.StateCollector {
+	f := cfg.FeaturesForDevice(d.Host)
+	opts := e.deviceCollectorOptions(cfg, d.Host)
+
+	for _, r := range e.collectorRegistrations() {
+		if r.Feature != feature || r.New == nil || !e.collectorEnabled(f, r) {
+			continue
+		}
+
+		col, ok := r.New(&collector.Params{
+			InterfaceDescriptionRegex: deviceInterfaceRegex(cfg, d.Host),
+			Options:                   opts.ForCollector(r.Feature),
+		}).(collector.StateCollector)
+		if ok {
+			return col
+		}
+	}
+
+	return nil
+}
+
+// stateFeatures returns the features enabled for the device whose collector provides its state
+func (e *Exporter) stateFeatures(cfg *config.Config, d *connector.Device) []string {
+	features := make([]string, 0)
+	for _, r := range e.collectorRegistrations() {
+		if e.stateCollector(cfg, d, r.Feature) != nil {
+			features = append(features, r.Feature)
+		}
+	}
+
+	slices.Sort(features)
+	return features
+}
+
+// APIHandler returns the handler of the JSON API:
+//
+//	GET /api/v1/targets                       devices of the config and the collectors providing their state
+//	GET /api/v1/targets/{target}              collectors providing their state for the target
+//	GET /api/v1/targets/{target}/{collector}  state of a collector (e.g. bgp), see docs/api-v1.md
+func (e *Exporter) APIHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/"+APIVersion+"/targets", e.handleTargets)
+	mux.HandleFunc("GET /api/"+APIVersion+"/targets/{target}", e.handleTarget)
+	mux.HandleFunc("GET /api/"+APIVersion+"/targets/{target}/{collector}", e.handleState)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(w, http.StatusNotFound, &apiResponse{Error: "not found"})
+	})
+
+	return mux
+}
+
+func (e *Exporter) handleTargets(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	targets := make([]apiTarget, 0, len(e.devices))
+	for _, d := range e.devices {
+		targets = append(targets, apiTarget{Target: d.Host, Collectors: e.stateFeatures(e.cfg, d)})
+	}
+	e.mu.RUnlock()
+
+	writeAPIResponse(w, http.StatusOK, &apiResponse{Data: targets})
+}
+
+func (e *Exporter) handleTarget(w http.ResponseWriter, r *http.Request) {
+	target := r.PathValue("target")
+
+	e.mu.RLock()
+	d, err := e.deviceForTarget(target, e.devices, e.cfg)
+	var features []string
+	if err == nil && d != nil {
+		features = e.stateFeatures(e.cfg, d)
+	}
+	e.mu.RUnlock()
+
+	switch {
+	case err != nil:
+		writeAPIResponse(w, http.StatusBadRequest, &apiResponse{Target: target, Error: err.Error()})
+	case d == nil:
+		writeAPIResponse(w, http.StatusNotFound, &apiResponse{Target: target, Error: fmt.Sprintf("%s: %s", ErrUnknownTarget, target)})
+	default:
+		writeAPIResponse(w, http.StatusOK, &apiResponse{Target: target, Data: apiTarget{Target: target, Collectors: features}})
+	}
+}
+
+func (e *Exporter) handleState(w http.ResponseWriter, r *http.Request) {
+	target := r.PathValue("target")
+	feature := r.PathValue("collector")
+
+	res := &apiResponse{Target: target, Collector: feature}
+
+	s, err := e.State(r.Context(), target, feature)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, ErrUnknownTarget) || errors.Is(err, ErrUnknownCollector) {
+			status = http.StatusNotFound
+		} else {
+			log.Errorf("API request for %s of %s failed: %v", feature, target, err)
+		}
+
+		res.Error = err.Error()
+		writeAPIResponse(w, status, res)
+		return
+	}
+
+	now := time.Now().UTC()
+	res.Timestamp = &now
+	res.Data = s
+	writeAPIResponse(w, http.StatusOK, res)
+}
+
+func writeAPIResponse(w http.ResponseWriter, status int, res *apiResponse) {
+	res.APIVersion = APIVersion
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Errorf("Could not write API response: %v", err)
+	}
+}
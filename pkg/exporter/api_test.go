@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+type stateCollector struct {
+	slowCollector
+}
+
+func (*stateCollector) State(client collector.Client) (any, error) {
+	return map[string]string{"state": "ok"}, nil
+}
+
+func TestAPIHandler(t *testing.T) {
+	regs := []collector.Registration{
+		{
+			Key:     "test",
+			Feature: "test",
+			New: func(p *collector.Params) collector.RPCCollector {
+				return &slowCollector{}
+			},
+		},
+		{
+			Key:     "state",
+			Feature: "state",
+			New: func(p *collector.Params) collector.RPCCollector {
+				return &stateCollector{}
+			},
+		},
+	}
+
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	}
+	c.Features.Set("test", true)
+	c.Features.Set("state", true)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectors(regs...))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			path:           "/api/v1/targets",
+			expectedStatus: 200,
+			expectedBody:   `{"api_version": "v1", "data": [{"target": "router1", "collectors": ["state"]}]}`,
+		},
+		{
+			path:           "/api/v1/targets/router1",
+			expectedStatus: 200,
+			expectedBody:   `{"api_version": "v1", "target": "router1", "data": {"target": "router1", "collectors": ["state"]}}`,
+		},
+		{
+			path:           "/api/v1/targets/router2",
+			expectedStatus: 404,
+			expectedBody:   `{"api_version": "v1", "target": "router2", "error": "unknown target: router2"}`,
+		},
+		{
+			path:           "/api/v1/targets/router1/test",
+			expectedStatus: 404,
+			expectedBody:   `{"api_version": "v1", "target": "router1", "collector": "test", "error": "unknown collector: test"}`,
+		},
+		{
+			path:           "/api/v1/targets/router1/state",
+			expectedStatus: 502,
+			expectedBody:   `{"api_version": "v1", "target": "router1", "collector": "state", "error": "could not connect to router1: unreachable"}`,
+		},
+		{
+			path:           "/api/v2/targets",
+			expectedStatus: 404,
+			expectedBody:   `{"api_version": "v1", "error": "not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			e.APIHandler().ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
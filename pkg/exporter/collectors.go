@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
+)
+
+type collectors struct {
+	// mu protects the maps, since the collectors of logical systems and routing instances are initialized concurrently by device
+	mu            sync.RWMutex
+	logicalSystem string
+	collectors    map[string]collector.RPCCollector
+	devices       map[string][]collector.RPCCollector
+	features      map[string]string
+	instanceAware map[string]bool // names of the collectors supporting routing instances
+	cfg           *config.Config
+	exporter      *Exporter
+}
+
+func (e *Exporter) collectorsForDevices(devices []*connector.Device, cfg *config.Config, logicalSystem string) *collectors {
+	c := &collectors{
+		logicalSystem: logicalSystem,
+		collectors:    make(map[string]collector.RPCCollector),
+		devices:       make(map[string][]collector.RPCCollector),
+		features:      make(map[string]string),
+		instanceAware: make(map[string]bool),
+		cfg:           cfg,
+		exporter:      e,
+	}
+
+	for _, d := range devices {
+		c.initCollectorsForDevices(d, deviceInterfaceRegex(cfg, d.Host))
+	}
+
+	return c
+}
+
+func (c *collectors) initCollectorsForDevices(device *connector.Device, descRe *regexp.Regexp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := c.cfg.FeaturesForDevice(device.Host)
+	opts := c.exporter.deviceCollectorOptions(c.cfg, device.Host)
+	dropped := droppedMetricFunc(c.cfg.RelabelConfigsForDevice(device.Host), c.exporter.metricNaming)
+
+	c.devices[device.Host] = make([]collector.RPCCollector, 0)
+
+	for _, r := range c.exporter.collectorRegistrations() {
+		if r.New == nil {
+			continue
+		}
+
+		p := &collector.Params{
+			LogicalSystem:             c.logicalSystem,
+			InterfaceDescriptionRegex: descRe,
+			Options:                   opts.ForCollector(r.Feature),
+			DroppedMetric:             dropped,
+		}
+		c.addCollectorIfEnabledForDevice(device, r, c.exporter.collectorEnabled(f, r), func() collector.RPCCollector {
+			return r.New(p)
+		})
+	}
+}
+
+// initCollectorsForLogicalSystem initializes the collectors supporting logical systems for one logical system of the device
+func (c *collectors) initCollectorsForLogicalSystem(device *connector.Device, logicalSystem string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := c.cfg.FeaturesForDevice(device.Host)
+	opts := c.exporter.deviceCollectorOptions(c.cfg, device.Host)
+	dropped := droppedMetricFunc(c.cfg.RelabelConfigsForDevice(device.Host), c.exporter.metricNaming)
+	descRe := deviceInterfaceRegex(c.cfg, device.Host)
+	unit := logicalSystemKey(device, logicalSystem)
+
+	c.devices[unit] = make([]collector.RPCCollector, 0)
+
+	for _, r := range c.exporter.collectorRegistrations() {
+		if r.New == nil || !r.LogicalSystems {
+			continue
+		}
+
+		p := &collector.Params{
+			LogicalSystem:             logicalSystem,
+			InterfaceDescriptionRegex: descRe,
+			Options:                   opts.ForCollector(r.Feature),
+			DroppedMetric:             dropped,
+		}
+		c.addCollectorIfEnabled(unit, r, c.exporter.collectorEnabled(f, r), func() collector.RPCCollector {
+			return r.New(p)
+		})
+	}
+}
+
+// initCollectorsForRoutingInstance initializes the routing protocol collectors for one routing instance of the device
+func (c *collectors) initCollectorsForRoutingInstance(device *connector.Device, routingInstance string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := c.cfg.FeaturesForDevice(device.Host)
+	opts := c.exporter.deviceCollectorOptions(c.cfg, device.Host)
+	dropped := droppedMetricFunc(c.cfg.RelabelConfigsForDevice(device.Host), c.exporter.metricNaming)
+	descRe := deviceInterfaceRegex(c.cfg, device.Host)
+	unit := routingInstanceKey(device, routingInstance)
+
+	c.devices[unit] = make([]collector.RPCCollector, 0)
+
+	for _, r := range c.exporter.collectorRegistrations() {
+		if r.New == nil || !r.RoutingInstances {
+			continue
+		}
+
+		p := &collector.Params{
+			RoutingInstance:           routingInstance,
+			InterfaceDescriptionRegex: descRe,
+			Options:                   opts.ForCollector(r.Feature),
+			DroppedMetric:             dropped,
+		}
+		c.addCollectorIfEnabled(unit, r, c.exporter.collectorEnabled(f, r), func() collector.RPCCollector {
+			return r.New(p)
+		})
+	}
+}
+
+func (c *collectors) addCollectorIfEnabledForDevice(device *connector.Device, r collector.Registration, enabled bool, newCollector func() collector.RPCCollector) {
+	c.addCollectorIfEnabled(device.Host, r, enabled, newCollector)
+}
+
+func (c *collectors) addCollectorIfEnabled(unit string, r collector.Registration, enabled bool, newCollector func() collector.RPCCollector) {
+	if !enabled {
+		return
+	}
+
+	colKey := r.Key + "_" + unit
+	col, found := c.collectors[colKey]
+	if !found {
+		col = newCollector()
+		c.collectors[colKey] = col
+		c.features[col.Name()] = r.Feature
+		if r.RoutingInstances {
+			c.instanceAware[col.Name()] = true
+		}
+	}
+
+	c.devices[unit] = append(c.devices[unit], col)
+}
+
+func (c *collectors) allEnabledCollectors() []collector.RPCCollector {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	collectors := make([]collector.RPCCollector, 0)
+	seen := make(map[string]bool)
+
+	for _, col := range c.collectors {
+		if !seen[col.Name()] {
+			seen[col.Name()] = true
+			collectors = append(collectors, col)
+		}
+	}
+
+	return collectors
+}
+
+// featureOf returns the feature name of a collector (used as key in the config)
+func (c *collectors) featureOf(col collector.RPCCollector) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.features[col.Name()]
+}
+
+func (c *collectors) collectorsForDevice(device *connector.Device) []collector.RPCCollector {
+	return c.collectorsForUnit(device.Host)
+}
+
+func (c *collectors) collectorsForLogicalSystem(device *connector.Device, logicalSystem string) []collector.RPCCollector {
+	return c.collectorsForUnit(logicalSystemKey(device, logicalSystem))
+}
+
+func (c *collectors) collectorsForRoutingInstance(device *connector.Device, routingInstance string) []collector.RPCCollector {
+	return c.collectorsForUnit(routingInstanceKey(device, routingInstance))
+}
+
+// collectorsForDefaultRoutingInstance returns the collectors of the device. The
+// collectors supporting routing instances are replaced by collectors of the
+// default routing instance, since the output of their commands contains the
+// data of all routing instances otherwise.
+func (c *collectors) collectorsForDefaultRoutingInstance(device *connector.Device) []collector.RPCCollector {
+	c.initCollectorsForRoutingInstance(device, defaultRoutingInstance)
+	instance := c.collectorsForRoutingInstance(device, defaultRoutingInstance)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cols := make([]collector.RPCCollector, 0, len(c.devices[device.Host]))
+	for _, col := range c.devices[device.Host] {
+		if !c.instanceAware[col.Name()] {
+			cols = append(cols, col)
+		}
+	}
+
+	return append(cols, instance...)
+}
+
+func (c *collectors) collectorsForUnit(unit string) []collector.RPCCollector {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cols, found := c.devices[unit]
+	if !found {
+		return []collector.RPCCollector{}
+	}
+
+	return cols
+}
+
+// droppedMetricFunc returns the function telling collectors which metrics are dropped by the relabel rules (nil if there are no rules).
+// The rules are applied after naming, so a metric is dropped if it is dropped with every name it is reported with in mode m.
+func droppedMetricFunc(rules []*relabel.Config, m naming.Mode) func(name string) bool {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	return func(name string) bool {
+		for _, n := range naming.Names(name, m) {
+			if !relabel.Dropped(rules, n) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+func logicalSystemKey(device *connector.Device, logicalSystem string) string {
+	return device.Host + "/ls/" + logicalSystem
+}
+
+func routingInstanceKey(device *connector.Device, routingInstance string) string {
+	return device.Host + "/ri/" + routingInstance
+}
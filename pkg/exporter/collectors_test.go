@@ -1,14 +1,18 @@
 // SPDX-License-Identifier: MIT
 
-package main
+package exporter
 
 import (
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
 	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
 )
 
 func TestCollectorsRegistered(t *testing.T) {
@@ -38,7 +42,7 @@ func TestCollectorsRegistered(t *testing.T) {
 		},
 	}
 
-	cols := collectorsForDevices([]*connector.Device{{
+	cols := (&Exporter{}).collectorsForDevices([]*connector.Device{{
 		Host: "::1",
 	}}, c, "")
 
@@ -89,7 +93,7 @@ func TestCollectorsForDevices(t *testing.T) {
 	d2 := &connector.Device{
 		Host: "2001:678:1e0::2",
 	}
-	cols := collectorsForDevices([]*connector.Device{d1, d2}, c, "")
+	cols := (&Exporter{}).collectorsForDevices([]*connector.Device{d1, d2}, c, "")
 
 	assert.Equal(t, 21, len(cols.collectorsForDevice(d1)), "device 1 collector count")
 
@@ -121,7 +125,7 @@ func TestPerDeviceCollectors(t *testing.T) {
 	d1 := &connector.Device{Host: "device1"}
 	d2 := &connector.Device{Host: "device2"}
 
-	cols := collectorsForDevices([]*connector.Device{d1, d2}, c, "")
+	cols := (&Exporter{}).collectorsForDevices([]*connector.Device{d1, d2}, c, "")
 
 	col1 := cols.collectorsForDevice(d1)
 	assert.Equal(t, 1, len(col1), "device 1 collector count")
@@ -134,3 +138,65 @@ func TestPerDeviceCollectors(t *testing.T) {
 	assert.NotSame(t, col1[0], col2[0], "collectors for different devices should be separate instances")
 }
 
+type privateCollector struct {
+	logicalSystem string
+}
+
+func (*privateCollector) Name() string {
+	return "Private"
+}
+
+func (*privateCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (*privateCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	return nil
+}
+
+func TestRegisteredPrivateCollector(t *testing.T) {
+	collector.Register(collector.Registration{
+		Key:            "private",
+		Feature:        "private",
+		LogicalSystems: true,
+		New: func(p *collector.Params) collector.RPCCollector {
+			return &privateCollector{logicalSystem: p.LogicalSystem}
+		},
+	})
+
+	c := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host: "device1",
+				Features: &config.FeatureConfig{
+					Extra: map[string]bool{"private": true},
+				},
+			},
+			{
+				Host: "device2",
+			},
+		},
+	}
+
+	d1 := &connector.Device{Host: "device1"}
+	d2 := &connector.Device{Host: "device2"}
+	cols := (&Exporter{}).collectorsForDevices([]*connector.Device{d1, d2}, c, "")
+
+	col1 := cols.collectorsForDevice(d1)
+	assert.Equal(t, 1, len(col1), "device 1 collector count")
+	assert.Equal(t, "Private", col1[0].Name())
+	assert.Equal(t, 0, len(cols.collectorsForDevice(d2)), "device 2 collector count")
+
+	cols.initCollectorsForLogicalSystem(d1, "customer-a")
+	ls := cols.collectorsForLogicalSystem(d1, "customer-a")
+	assert.Equal(t, 1, len(ls), "logical system collector count")
+	assert.Equal(t, "customer-a", ls[0].(*privateCollector).logicalSystem)
+}
+
+func TestDroppedMetricFuncMatchesNamesOfMode(t *testing.T) {
+	rules := []*relabel.Config{{SourceLabels: []string{"__name__"}, Regex: "junos_route_engine_temperature_celsius", Action: relabel.Keep}}
+	assert.NoError(t, relabel.Validate(rules))
+
+	assert.True(t, droppedMetricFunc(rules, naming.V1)("junos_route_engine_temp"))
+	assert.False(t, droppedMetricFunc(rules, naming.V2)("junos_route_engine_temp"), "v2 name is kept")
+	assert.False(t, droppedMetricFunc(rules, naming.Both)("junos_route_engine_temp"), "v2 name is kept")
+	assert.True(t, droppedMetricFunc(rules, naming.V2)("junos_up"))
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"bytes"
+	"io"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+
+	// register the collectors of the exporter
+	_ "github.com/czerwonk/junos_exporter/pkg/features/all"
+)
+
+type (
+	// Config is the configuration of the exporter (see the README for the format of the config file)
+	Config = config.Config
+
+	// DeviceConfig is the configuration of a device
+	DeviceConfig = config.DeviceConfig
+
+	// GroupConfig is the configuration of a group of devices
+	GroupConfig = config.GroupConfig
+
+	// FeatureConfig enables or disables the collectors
+	FeatureConfig = config.FeatureConfig
+
+	// CollectorOptions are the options of the collectors
+	CollectorOptions = config.CollectorOptions
+)
+
+// NewConfig creates a config with the default features of the registered collectors enabled
+func NewConfig() *Config {
+	return config.New(collector.DefaultRegistry)
+}
+
+// LoadConfig loads a config in the format of the config file. Features are
+// validated against the collectors registered with collector.Register.
+func LoadConfig(r io.Reader, dynamicIfaceLabels bool) (*Config, error) {
+	return config.Load(r, dynamicIfaceLabels, collector.DefaultRegistry)
+}
+
+// redacted replaces secrets in the effective config
+const redacted = "<secret>"
+
+// EffectiveConfigYAML returns the effective config of a target as YAML, after
+// resolving groups and merging features. Secrets are redacted.
+func (e *Exporter) EffectiveConfigYAML(target string) ([]byte, error) {
+	d := e.Config().EffectiveDeviceConfig(target)
+	if d.Password != "" {
+		d.Password = redacted
+	}
+	if d.KeyPassphrase != "" {
+		d.KeyPassphrase = redacted
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), enc.Close()
+}
@@ -0,0 +1,409 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// debugPlaceholder is used as name of the logical system or routing instance of the collectors replaying a reply
+	debugPlaceholder = "*"
+
+	// debugRPCTimeout is the maximum duration of a command run for troubleshooting
+	debugRPCTimeout = time.Minute
+)
+
+// ErrCommandNotAllowed is returned if a command is neither run by a collector nor allowed in the debug_rpc section of the config
+var ErrCommandNotAllowed = errors.New("command not allowed")
+
+// RPCDebugResult is the result of a command run for troubleshooting
+type RPCDebugResult struct {
+	Target  string
+	Command string
+
+	// XML is the raw reply of the device
+	XML []byte
+
+	// Collectors are the results of the collectors running the command (empty if the command is only allowed by the config)
+	Collectors []*RPCDebugCollectorResult
+}
+
+// RPCDebugCollectorResult is the reply of a command as parsed by a collector
+type RPCDebugCollectorResult struct {
+	Feature string
+	Name    string
+
+	// Parsed is the object the reply was unmarshaled to (nil if the collector uses its own parser)
+	Parsed any
+
+	// ParseError is the error of the parser of the collector (empty on success)
+	ParseError string
+
+	// Metrics are the metrics the collector created from the reply in text exposition format.
+	// Replies of other commands of the collector are empty.
+	Metrics string
+}
+
+// DebugRPC runs a command on the device of a target using the pooled
+// connection. Only commands declared by a collector enabled for the device or
+// allowed in the debug_rpc section of the config are accepted. The reply is
+// replayed to the collectors running the command.
+func (e *Exporter) DebugRPC(ctx context.Context, target, command string) (*RPCDebugResult, error) {
+	cfg, devs := e.snapshot()
+
+	d, err := e.deviceForTarget(target, devs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+
+	command = strings.Join(strings.Fields(command), " ")
+	if command == "" || strings.ContainsAny(command, "|;`") {
+		return nil, fmt.Errorf("%w: %q", ErrCommandNotAllowed, command)
+	}
+
+	matching := make([]*debugCollector, 0)
+	for _, c := range e.debugCollectors(cfg, d) {
+		if c.reg.RunsCommand(command) {
+			matching = append(matching, c)
+		}
+	}
+
+	if len(matching) == 0 && !cfg.DebugRPC.CommandAllowed(command) {
+		return nil, fmt.Errorf("%w: %q", ErrCommandNotAllowed, command)
+	}
+
+	cl, err := e.clientForDevice(d, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %w", target, err)
+	}
+
+	res := &RPCDebugResult{
+		Target:     target,
+		Command:    command,
+		Collectors: make([]*RPCDebugCollectorResult, 0, len(matching)),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, debugRPCTimeout)
+	defer cancel()
+
+	err = cl.RunCommandAndParseWithParserContext(ctx, command, func(b []byte) error {
+		res.XML = b
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range matching {
+		res.Collectors = append(res.Collectors, c.replay(func() *debugClient {
+			return &debugClient{
+				device:    d,
+				ctx:       ctx,
+				satellite: cl.IsSatelliteEnabled(),
+				license:   cl.IsScrapingLicenseEnabled(),
+				command:   command,
+				reply:     res.XML,
+			}
+		}))
+	}
+
+	return res, nil
+}
+
+// DebugRPCCommands returns the commands declared by the collectors enabled for
+// the device of a target. Values (e.g. names of routing instances) are replaced by *.
+func (e *Exporter) DebugRPCCommands(target string) ([]string, error) {
+	cfg, devs := e.snapshot()
+
+	d, err := e.deviceForTarget(target, devs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+
+	commands := make([]string, 0)
+	for _, c := range e.debugCollectors(cfg, d) {
+		commands = append(commands, c.reg.Commands...)
+	}
+
+	slices.Sort(commands)
+	return slices.Compact(commands), nil
+}
+
+// DebugRPCHandler returns the handler running a command on a target (parameters
+// target and command). It shows the raw XML reply and the reply as parsed by the
+// collectors running the command. Without command parameter the commands of the
+// collectors of the target are listed. The handler should only be served with
+// authentication, since commands are run on the devices.
+func (e *Exporter) DebugRPCHandler() http.Handler {
+	return http.HandlerFunc(e.handleDebugRPC)
+}
+
+func (e *Exporter) handleDebugRPC(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	command := r.URL.Query().Get("command")
+	if command == "" {
+		commands, err := e.DebugRPCCommands(target)
+		if err != nil {
+			http.Error(w, err.Error(), debugRPCErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "Commands of the collectors of %s (* stands for any value):\n\n%s\n", target, strings.Join(commands, "\n"))
+		return
+	}
+
+	res, err := e.DebugRPC(r.Context(), target, command)
+	if err != nil {
+		status := debugRPCErrorStatus(err)
+		if status == http.StatusBadGateway {
+			log.Errorf("Debug RPC %q on %s failed: %v", command, target, err)
+		}
+
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "# Reply of %s to: %s\n\n%s\n", res.Target, res.Command, res.XML)
+
+	if len(res.Collectors) == 0 {
+		fmt.Fprintln(w, "\n# The command is not run by any collector enabled for the target")
+	}
+
+	for _, c := range res.Collectors {
+		fmt.Fprintf(w, "\n# Parsed by collector %s (%s)\n\n", c.Feature, c.Name)
+		if c.ParseError != "" {
+			fmt.Fprintf(w, "Parse error: %s\n\n", c.ParseError)
+		}
+
+		if c.Parsed != nil {
+			b, err := json.MarshalIndent(c.Parsed, "", "  ")
+			if err != nil {
+				b = []byte(err.Error())
+			}
+
+			fmt.Fprintf(w, "%s\n\n", b)
+		}
+
+		fmt.Fprintf(w, "# Metrics of collector %s\n\n%s", c.Feature, c.Metrics)
+	}
+}
+
+func debugRPCErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrUnknownTarget):
+		return http.StatusNotFound
+	case errors.Is(err, ErrCommandNotAllowed):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// debugCollector is a collector enabled for a device
+type debugCollector struct {
+	reg collector.Registration
+
+	// variants are the collectors of the device and of a placeholder logical system and routing instance
+	variants []collector.RPCCollector
+}
+
+// debugCollectors returns the collectors enabled for a device
+func (e *Exporter) debugCollectors(cfg *config.Config, d *connector.Device) []*debugCollector {
+	c := e.collectorsForDevices([]*connector.Device{d}, cfg, "")
+	c.initCollectorsForLogicalSystem(d, debugPlaceholder)
+	c.initCollectorsForRoutingInstance(d, debugPlaceholder)
+
+	units := []string{d.Host, logicalSystemKey(d, debugPlaceholder), routingInstanceKey(d, debugPlaceholder)}
+
+	res := make([]*debugCollector, 0)
+	for _, r := range e.collectorRegistrations() {
+		dc := &debugCollector{reg: r}
+		for _, unit := range units {
+			if col, found := c.collectors[r.Key+"_"+unit]; found {
+				dc.variants = append(dc.variants, col)
+			}
+		}
+
+		if len(dc.variants) > 0 {
+			res = append(res, dc)
+		}
+	}
+
+	return res
+}
+
+// replay runs the variants of the collector with the reply of the command of
+// the client until one of them runs the command. It returns the parsed reply
+// and the metrics.
+func (c *debugCollector) replay(newClient func() *debugClient) *RPCDebugCollectorResult {
+	for _, col := range c.variants {
+		cl := newClient()
+		res := c.replayTo(col, cl)
+		if cl.replied {
+			return res
+		}
+	}
+
+	return &RPCDebugCollectorResult{
+		Feature:    c.reg.Feature,
+		Name:       c.variants[0].Name(),
+		ParseError: "the reply could not be replayed, the collector runs the command depending on the replies of other commands",
+	}
+}
+
+func (c *debugCollector) replayTo(col collector.RPCCollector, cl *debugClient) *RPCDebugCollectorResult {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&passCollector{collect: func(ch chan<- prometheus.Metric) {
+		cl.collectTo(col, ch)
+	}})
+
+	res := &RPCDebugCollectorResult{
+		Feature: c.reg.Feature,
+		Name:    col.Name(),
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		res.Metrics = err.Error()
+	}
+
+	b := &bytes.Buffer{}
+	for _, mf := range mfs {
+		expfmt.MetricFamilyToText(b, mf)
+	}
+	res.Metrics += b.String()
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	res.Parsed = cl.parsed
+	if cl.parseErr != nil {
+		res.ParseError = cl.parseErr.Error()
+	}
+
+	return res
+}
+
+// sameCommand returns whether two commands only differ in values (e.g. names of routing instances)
+func sameCommand(a, b string) bool {
+	return rpc.NormalizeCommand(a) == rpc.NormalizeCommand(b)
+}
+
+// debugClient implements collector.Client without a connection. It replies
+// with the reply of command, all other commands get empty replies.
+type debugClient struct {
+	device    *connector.Device
+	ctx       context.Context
+	satellite bool
+	license   bool
+
+	command string
+	reply   []byte
+
+	mu       sync.Mutex
+	replied  bool
+	parsed   any
+	parseErr error
+}
+
+// collectTo runs a collector. Errors of the collector are expected, since the
+// other commands get empty replies. A panic is reported as parse error.
+func (c *debugClient) collectTo(col collector.RPCCollector, ch chan<- prometheus.Metric) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Collector %s panicked replaying %q of %s: %v", col.Name(), c.command, c.device.Host, r)
+
+			c.mu.Lock()
+			c.parseErr = fmt.Errorf("collector panicked: %v", r)
+			c.mu.Unlock()
+		}
+	}()
+
+	col.Collect(c, ch, []string{c.device.Host})
+}
+
+// RunCommandAndParse implements RunCommandAndParse of the collector.Client interface
+func (c *debugClient) RunCommandAndParse(cmd string, obj any) error {
+	return c.RunCommandAndParseWithParser(cmd, func(b []byte) error {
+		err := xml.Unmarshal(b, obj)
+		if err == nil {
+			c.parsed = obj
+		}
+
+		return err
+	})
+}
+
+// RunCommandAndParseWithParser implements RunCommandAndParseWithParser of the collector.Client interface
+func (c *debugClient) RunCommandAndParseWithParser(cmd string, parser rpc.Parser) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.command == "" || !sameCommand(cmd, c.command) {
+		return nil
+	}
+
+	c.replied = true
+
+	err := parser(c.reply)
+	if err != nil {
+		c.parseErr = err
+	}
+
+	return err
+}
+
+// IsSatelliteEnabled implements IsSatelliteEnabled of the collector.Client interface
+func (c *debugClient) IsSatelliteEnabled() bool {
+	return c.satellite
+}
+
+func (c *debugClient) IsScrapingLicenseEnabled() bool {
+	return c.license
+}
+
+// Device implements Device of the collector.Client interface
+func (c *debugClient) Device() *connector.Device {
+	return c.device
+}
+
+// Context implements Context of the collector.Client interface
+func (c *debugClient) Context() context.Context {
+	return c.ctx
+}
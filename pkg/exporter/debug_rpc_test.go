@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+var debugTestDesc = prometheus.NewDesc("junos_debug_test_peers", "Number of peers", []string{"target"}, nil)
+
+type debugTestReply struct {
+	Peers []string `xml:"peer"`
+}
+
+// debugTestCollector runs one command per routing instance
+type debugTestCollector struct {
+	routingInstance string
+}
+
+func (*debugTestCollector) Name() string {
+	return "Debug test"
+}
+
+func (*debugTestCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- debugTestDesc
+}
+
+func (c *debugTestCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	cmd := "show test peers"
+	if c.routingInstance != "" {
+		cmd += " instance " + c.routingInstance
+	}
+
+	var r debugTestReply
+	err := client.RunCommandAndParse(cmd, &r)
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(debugTestDesc, prometheus.GaugeValue, float64(len(r.Peers)), labelValues...)
+	return nil
+}
+
+var debugTestRegistration = collector.Registration{
+	Key:              "debug",
+	Feature:          "debug",
+	RoutingInstances: true,
+	Commands: []string{
+		"show test peers",
+		"show test peers instance *",
+	},
+	New: func(p *collector.Params) collector.RPCCollector {
+		return &debugTestCollector{routingInstance: p.RoutingInstance}
+	},
+}
+
+func newDebugTestExporter(t *testing.T) *Exporter {
+
+	c, err := config.Load(strings.NewReader(`
+password: secret
+debug_rpc:
+  allowed_commands:
+    - 'show version( detail)?'
+devices:
+  - host: router1
+`), true, collector.DefaultRegistry)
+	require.NoError(t, err)
+	c.Features.Set("debug", true)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectors(debugTestRegistration))
+	require.NoError(t, err)
+
+	return e
+}
+
+func TestDebugRPCCommands(t *testing.T) {
+	e := newDebugTestExporter(t)
+
+	commands, err := e.DebugRPCCommands("router1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"show test peers", "show test peers instance *"}, commands)
+
+	_, err = e.DebugRPCCommands("router2")
+	assert.ErrorIs(t, err, ErrUnknownTarget)
+}
+
+func TestDebugRPC(t *testing.T) {
+	e := newDebugTestExporter(t)
+
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{command: "show test peers", allowed: true},
+		{command: "show test peers instance blue", allowed: true},
+		{command: "show  version   detail", allowed: true},
+		{command: "show test"},
+		{command: "request system reboot"},
+		{command: "show version | save /tmp/x"},
+		{command: "show version; request system reboot"},
+		{command: " "},
+	}
+
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			_, err := e.DebugRPC(context.Background(), "router1", test.command)
+			if !test.allowed {
+				assert.ErrorIs(t, err, ErrCommandNotAllowed)
+				return
+			}
+
+			// allowed commands are run on the device, which is unreachable
+			assert.ErrorContains(t, err, "could not connect to router1")
+		})
+	}
+
+	_, err := e.DebugRPC(context.Background(), "router2", "show test peers")
+	assert.ErrorIs(t, err, ErrUnknownTarget)
+}
+
+func TestDebugRPCReplay(t *testing.T) {
+	d := &connector.Device{Host: "router1"}
+	c := &debugCollector{
+		reg:      debugTestRegistration,
+		variants: []collector.RPCCollector{&debugTestCollector{}, &debugTestCollector{routingInstance: debugPlaceholder}},
+	}
+
+	newClient := func(reply string) func() *debugClient {
+		return func() *debugClient {
+			return &debugClient{
+				device:  d,
+				ctx:     context.Background(),
+				command: "show test peers instance blue",
+				reply:   []byte(reply),
+			}
+		}
+	}
+
+	res := c.replay(newClient("<rpc-reply><peer>a</peer><peer>b</peer></rpc-reply>"))
+	assert.Equal(t, "debug", res.Feature)
+	assert.Empty(t, res.ParseError)
+	assert.Equal(t, &debugTestReply{Peers: []string{"a", "b"}}, res.Parsed)
+	assert.Contains(t, res.Metrics, `junos_debug_test_peers{target="router1"} 2`)
+
+	res = c.replay(newClient("<rpc-reply><peer>"))
+	assert.NotEmpty(t, res.ParseError)
+	assert.Nil(t, res.Parsed)
+	assert.Empty(t, res.Metrics)
+
+	c.variants = c.variants[:1]
+	res = c.replay(newClient("<rpc-reply/>"))
+	assert.Contains(t, res.ParseError, "could not be replayed", "no variant runs the command")
+}
+
+// panicCollector panics on the reply of its command
+type panicCollector struct{}
+
+func (*panicCollector) Name() string {
+	return "Panic"
+}
+
+func (*panicCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- debugTestDesc
+}
+
+func (*panicCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	var r debugTestReply
+	err := client.RunCommandAndParse("show test peers", &r)
+	if err != nil {
+		return err
+	}
+
+	_ = r.Peers[len(r.Peers)]
+	return nil
+}
+
+func TestDebugRPCReplayPanic(t *testing.T) {
+	c := &debugCollector{reg: debugTestRegistration, variants: []collector.RPCCollector{&panicCollector{}}}
+
+	res := c.replay(func() *debugClient {
+		return &debugClient{
+			device:  &connector.Device{Host: "router1"},
+			ctx:     context.Background(),
+			command: "show test peers",
+			reply:   []byte("<rpc-reply/>"),
+		}
+	})
+	assert.Contains(t, res.ParseError, "collector panicked")
+}
+
+// commandRecorder implements collector.Client recording the commands with empty replies
+type commandRecorder struct {
+	debugClient
+	commands []string
+}
+
+func (c *commandRecorder) RunCommandAndParse(cmd string, obj any) error {
+	return c.RunCommandAndParseWithParser(cmd, nil)
+}
+
+func (c *commandRecorder) RunCommandAndParseWithParser(cmd string, parser rpc.Parser) error {
+	c.commands = append(c.commands, cmd)
+	return nil
+}
+
+func TestRegisteredCollectorsDeclareCommands(t *testing.T) {
+	for _, r := range collector.Registrations() {
+		if r.New == nil {
+			continue
+		}
+
+		params := []*collector.Params{{}}
+		if r.LogicalSystems {
+			params = append(params, &collector.Params{LogicalSystem: "ls1"})
+		}
+		if r.RoutingInstances {
+			params = append(params, &collector.Params{RoutingInstance: "CUSTOMER-A"}, &collector.Params{RoutingInstance: defaultRoutingInstance})
+		}
+
+		for _, p := range params {
+			cl := &commandRecorder{debugClient: debugClient{device: &connector.Device{Host: "router1"}, ctx: context.Background(), satellite: true, license: true}}
+			ch := make(chan prometheus.Metric, 1000)
+			func() {
+				// some collectors do not handle empty replies
+				defer func() {
+					recover()
+				}()
+
+				r.New(p).Collect(cl, ch, []string{"router1"})
+			}()
+
+			for _, cmd := range cl.commands {
+				assert.True(t, r.RunsCommand(cmd), "command %q of %s is not declared", cmd, r.Feature)
+			}
+		}
+	}
+}
+
+func TestDebugRPCHandler(t *testing.T) {
+	h := newDebugTestExporter(t).DebugRPCHandler()
+
+	tests := []struct {
+		name           string
+		query          url.Values
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "target missing",
+			query:          url.Values{"command": {"show test peers"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown target",
+			query:          url.Values{"target": {"router2"}},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "commands of the collectors",
+			query:          url.Values{"target": {"router1"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "show test peers\nshow test peers instance *\n",
+		},
+		{
+			name:           "command not allowed",
+			query:          url.Values{"target": {"router1"}, "command": {"request system reboot"}},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "command not allowed",
+		},
+		{
+			name:           "command of a collector",
+			query:          url.Values{"target": {"router1"}, "command": {"show test peers"}},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   "could not connect to router1",
+		},
+		{
+			name:           "command of the config",
+			query:          url.Values{"target": {"router1"}, "command": {"show version"}},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   "could not connect to router1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/rpc?"+test.query.Encode(), nil))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), test.expectedBody)
+		})
+	}
+}
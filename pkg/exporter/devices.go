@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+)
+
+func (e *Exporter) devicesForConfig(cfg *config.Config) ([]*connector.Device, error) {
+	cfg.InitTargetDevices()
+
+	devs := make([]*connector.Device, 0)
+	for _, d := range cfg.Devices {
+		if d.IsHostPattern {
+			continue
+		}
+
+		dev, err := e.deviceFromDeviceConfig(d, d.TargetName(), cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		devs = append(devs, dev)
+	}
+
+	return devs, nil
+}
+
+// deviceForTarget returns the device for a target, either a configured device
+// (by name or discovered hostname) or a device matching a host pattern. nil is
+// returned if the target is unknown.
+func (e *Exporter) deviceForTarget(target string, devices []*connector.Device, cfg *config.Config) (*connector.Device, error) {
+	for _, d := range devices {
+		if d.Host == target {
+			return d, nil
+		}
+	}
+
+	for _, d := range devices {
+		if name, found := e.discoveredNames.Load(d.Host); found && name == target {
+			return d, nil
+		}
+	}
+
+	for _, dc := range cfg.Devices {
+		if !dc.IsHostPattern {
+			continue
+		}
+
+		if dc.HostPattern.MatchString(target) {
+			return e.deviceFromDeviceConfig(dc, target, cfg)
+		}
+	}
+
+	return nil, nil
+}
+
+func (e *Exporter) deviceFromDeviceConfig(device *config.DeviceConfig, hostname string, cfg *config.Config) (*connector.Device, error) {
+	params, err := e.authParamsForDevice(device, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize config for device %s: %w", device.TargetName(), err)
+	}
+
+	auth, err := params.authMethod()
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize config for device %s: %w", device.TargetName(), err)
+	}
+
+	// check whether there is a device specific regex otherwise fallback to global regex
+	if len(device.IfDescRegStr) == 0 {
+		device.IfDescReg = cfg.IfDescReg
+	} else {
+		re, err := regexp.Compile(device.IfDescRegStr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile device description regex for %q: %q: %w", hostname, device.IfDescRegStr, err)
+		}
+
+		device.IfDescReg = re
+	}
+
+	dev := &connector.Device{
+		Host:    hostname,
+		Address: device.AddressForTarget(hostname),
+		Auth:    auth,
+		AuthID:  params.id(),
+	}
+
+	if device.SSH != nil {
+		dev.Port = device.SSH.Port
+		dev.KeepAliveInterval = device.SSH.KeepAliveInterval
+		dev.KeepAliveTimeout = device.SSH.KeepAliveTimeout
+	}
+
+	return dev, nil
+}
+
+// authParams are the parameters used to authenticate against a device
+type authParams struct {
+	username      string
+	keyFile       string
+	key           string
+	keyPassphrase string
+	password      string
+}
+
+func (e *Exporter) authParamsForDevice(device *config.DeviceConfig, cfg *config.Config) (*authParams, error) {
+	profile, err := cfg.CredentialsForDevice(device)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile != nil {
+		return e.authParamsForProfile(profile, device.Credentials)
+	}
+
+	p := &authParams{username: e.username}
+	if device.Username != "" {
+		p.username = device.Username
+	}
+
+	switch {
+	case device.KeyFile != "":
+		p.keyFile = device.KeyFile
+		p.keyPassphrase = device.KeyPassphrase
+	case e.keyFile != "":
+		p.keyFile = e.keyFile
+		p.keyPassphrase = e.keyPassphrase
+	case device.Password != "":
+		p.password = device.Password
+	case cfg.Password != "":
+		p.password = cfg.Password
+	case e.password != "":
+		p.password = e.password
+	default:
+		return nil, fmt.Errorf("no valid authentication method available")
+	}
+
+	return p, nil
+}
+
+// authParamsForProfile reads the secrets of a credential profile
+func (e *Exporter) authParamsForProfile(profile *config.CredentialsConfig, name string) (*authParams, error) {
+	p := &authParams{username: e.username}
+	if profile.Username != "" {
+		p.username = profile.Username
+	}
+
+	secrets := []struct {
+		source *config.SecretSource
+		dst    *string
+		desc   string
+	}{
+		{profile.Password, &p.password, "password"},
+		{profile.Key, &p.key, "key"},
+		{profile.KeyPassphrase, &p.keyPassphrase, "key passphrase"},
+	}
+
+	if e.withoutSecrets {
+		if profile.Password == nil && profile.Key == nil {
+			return nil, fmt.Errorf("credentials %q: password or key must be set", name)
+		}
+
+		return p, nil
+	}
+
+	for _, s := range secrets {
+		if s.source == nil {
+			continue
+		}
+
+		v, err := e.secrets.Resolve(s.source)
+		if err != nil {
+			return nil, fmt.Errorf("credentials %q: could not read %s from %s: %w", name, s.desc, s.source, err)
+		}
+
+		*s.dst = v
+	}
+
+	if p.password == "" && p.key == "" {
+		return nil, fmt.Errorf("credentials %q: password or key must be set", name)
+	}
+
+	return p, nil
+}
+
+func (p *authParams) authMethod() (connector.AuthMethod, error) {
+	if p.key != "" {
+		auth, err := connector.AuthByKey(p.username, strings.NewReader(p.key), p.keyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("could not load ssh private key: %w", err)
+		}
+
+		return auth, nil
+	}
+
+	if p.keyFile != "" {
+		return authForKeyFile(p.username, p.keyFile, p.keyPassphrase)
+	}
+
+	return connector.AuthByPassword(p.username, p.password), nil
+}
+
+// id returns a hash over the parameters (and the content of the key file),
+// so it changes whenever the credentials are changed
+func (p *authParams) id() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q %q %q %q", p.username, p.keyFile, p.key, p.keyPassphrase, p.password)
+
+	if p.keyFile != "" {
+		if b, err := os.ReadFile(p.keyFile); err == nil {
+			h.Write(b)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func authForKeyFile(username, keyFile, keyPassphrase string) (connector.AuthMethod, error) {
+	f, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open ssh key file: %w", err)
+	}
+	defer f.Close()
+
+	auth, err := connector.AuthByKey(username, f, keyPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("could not load ssh private key file: %w", err)
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+func TestDeviceForTarget(t *testing.T) {
+	c, err := config.Load(bytes.NewReader([]byte(`
+password: secret
+devices:
+  - name: fra1-pe1
+    address: 10.12.0.5
+  - host: 10.13.0.1
+    discover_name: true
+  - host: (\w+)-sw(\d+)
+    host_pattern: true
+    address: 'sw${2}.${1}.mgmt.example.com'
+`)), false, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := New(c)
+	assert.NoError(t, err)
+
+	devs := e.Devices()
+	e.discoveredNames.Store("10.13.0.1", "ber1-pe1")
+
+	tests := []struct {
+		target  string
+		host    string
+		address string
+	}{
+		{target: "fra1-pe1", host: "fra1-pe1", address: "10.12.0.5"},
+		{target: "ber1-pe1", host: "10.13.0.1"},
+		{target: "10.13.0.1", host: "10.13.0.1"},
+		{target: "fra1-sw12", host: "fra1-sw12", address: "sw12.fra1.mgmt.example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			d, err := e.DeviceForTarget(test.target)
+			assert.NoError(t, err)
+			if !assert.NotNil(t, d) {
+				return
+			}
+
+			assert.Equal(t, test.host, d.Host)
+			assert.Equal(t, test.address, d.Address)
+		})
+	}
+
+	d, err := e.deviceForTarget("10.12.0.5", devs, c)
+	assert.NoError(t, err)
+	assert.Nil(t, d, "address should not match")
+}
+
+func TestCredentialProfiles(t *testing.T) {
+	_, pk, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(pk, "")
+	assert.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(block), 0600))
+
+	t.Setenv("JUNOS_TEST_PASSWORD", "secret1")
+
+	c, err := config.Load(bytes.NewReader([]byte(`
+credentials:
+  netops:
+    username: netops
+    password:
+      env: JUNOS_TEST_PASSWORD
+  automation:
+    key:
+      file: `+keyFile+`
+devices:
+  - host: router1
+    credentials: netops
+  - host: router2
+    credentials: automation
+`)), false, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := New(c)
+	assert.NoError(t, err)
+
+	devs := e.Devices()
+	assert.Equal(t, 2, len(devs))
+
+	p, err := e.authParamsForDevice(c.FindDeviceConfig("router1"), c)
+	assert.NoError(t, err)
+	assert.Equal(t, "netops", p.username)
+	assert.Equal(t, "secret1", p.password)
+
+	p, err = e.authParamsForDevice(c.FindDeviceConfig("router2"), c)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultUsername, p.username)
+	assert.NotEmpty(t, p.key)
+
+	t.Setenv("JUNOS_TEST_PASSWORD", "secret2")
+	e.secrets.Reset()
+
+	rotated, err := e.devicesForConfig(c)
+	assert.NoError(t, err)
+	assert.NotEqual(t, devs[0].AuthID, rotated[0].AuthID, "rotated password")
+	assert.Equal(t, devs[1].AuthID, rotated[1].AuthID, "unchanged key")
+
+	t.Setenv("JUNOS_TEST_PASSWORD", "")
+	e.secrets.Reset()
+	_, err = e.devicesForConfig(c)
+	assert.EqualError(t, err, `could not initialize config for device router1: credentials "netops": could not read password from env JUNOS_TEST_PASSWORD: environment variable "JUNOS_TEST_PASSWORD" is empty or unset`)
+}
+
+func TestCredentialProfilesWithoutSecrets(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "executed")
+
+	c, err := config.Load(bytes.NewReader([]byte(`
+credentials:
+  netops:
+    password:
+      exec: [touch, `+marker+`]
+  automation:
+    key:
+      file: /nonexistent/key
+devices:
+  - host: router1
+    credentials: netops
+  - host: router2
+    credentials: automation
+`)), false, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	_, err = New(c, WithoutSecrets())
+	assert.NoError(t, err)
+	assert.NoFileExists(t, marker, "command should not be executed")
+
+	c.Devices[0].Credentials = "unknown"
+	_, err = New(c, WithoutSecrets())
+	assert.EqualError(t, err, `could not initialize config for device router1: device router1 references unknown credentials "unknown"`)
+}
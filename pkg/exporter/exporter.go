@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: MIT
+
+// Package exporter provides the core of the junos_exporter to be embedded in
+// other programs. An Exporter scrapes the devices of a config and can be used
+// as prometheus.Collector or as http.Handler (Prometheus multi-target pattern).
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/internal/secrets"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultUsername            = "junos_exporter"
+	defaultScrapeTimeoutOffset = 500 * time.Millisecond
+)
+
+// ConnectionManager manages the SSH connections to the devices
+type ConnectionManager interface {
+	// GetSSHConnection gets an established connection to a device or connects to it
+	GetSSHConnection(device *connector.Device) (*connector.SSHConnection, error)
+
+	// Devices returns the devices with an open connection
+	Devices() []*connector.Device
+
+	// Close closes the connection to a device
+	Close(host string)
+
+	// CloseAll closes all connections
+	CloseAll()
+}
+
+// Exporter scrapes the devices of a config
+type Exporter struct {
+	mu              sync.RWMutex
+	cfg             *config.Config
+	devices         []*connector.Device
+	discoveredNames sync.Map // hostnames of devices with discover_name enabled (key: host of the device)
+	discovered      sync.Map // logical systems and routing instances discovered on devices (key: host of the device and kind)
+	abandoned       sync.Map // collectors still running after the scrape deadline (key: host of the device)
+
+	connManager         ConnectionManager
+	registrations       []collector.Registration
+	features            map[string]bool
+	secrets             *secrets.Resolver
+	withoutSecrets      bool
+	username            string
+	password            string
+	keyFile             string
+	keyPassphrase       string
+	collectorDefaults   *config.CollectorOptions
+	rpcDebug            bool
+	rpcMetrics          *rpc.Metrics
+	scrapeTimeoutOffset time.Duration
+	collectTimeout      time.Duration
+	metricNaming        naming.Mode
+	status              *statusStore
+}
+
+// New creates an exporter for the devices of the config
+func New(c *Config, opts ...Option) (*Exporter, error) {
+	e := &Exporter{
+		username:            defaultUsername,
+		secrets:             secrets.NewResolver(),
+		scrapeTimeoutOffset: defaultScrapeTimeoutOffset,
+		status:              newStatusStore(),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.connManager == nil {
+		e.connManager = connector.NewConnectionManager()
+	}
+
+	devs, err := e.load(c)
+	if err != nil {
+		return nil, err
+	}
+
+	e.cfg = c
+	e.devices = devs
+	e.status.reset(devs, func(host string) bool {
+		return matchesHostPattern(c, host)
+	})
+
+	return e, nil
+}
+
+// Reload replaces the config of the exporter. In contrast to New, the
+// connection manager is kept: connections to devices which are still
+// configured with unchanged credentials are reused, connections to removed
+// devices or devices with changed credentials are closed. New devices are
+// connected on their first scrape. The running config is kept on error.
+func (e *Exporter) Reload(c *Config) error {
+	// read secrets of credential profiles again, so rotated secrets are used
+	e.secrets.Reset()
+
+	devs, err := e.load(c)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.cfg = c
+	e.devices = devs
+	e.discoveredNames.Clear()
+	e.discovered.Clear()
+	e.status.reset(devs, func(host string) bool {
+		return matchesHostPattern(c, host)
+	})
+
+	for _, d := range e.staleDevices(e.connManager.Devices(), devs, c) {
+		e.connManager.Close(d.Host)
+	}
+
+	return nil
+}
+
+func (e *Exporter) load(c *Config) ([]*connector.Device, error) {
+	err := e.checkStaticLabels(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.devicesForConfig(c)
+}
+
+// Close closes the connections to all devices
+func (e *Exporter) Close() {
+	e.connManager.CloseAll()
+}
+
+// Config returns the running config
+func (e *Exporter) Config() *Config {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.cfg
+}
+
+// Devices returns the devices of the running config (devices matching a host pattern are not included)
+func (e *Exporter) Devices() []*connector.Device {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.devices
+}
+
+// Snapshot returns the running config together with its devices, so both are
+// from the same reload (devices matching a host pattern are not included)
+func (e *Exporter) Snapshot() (*Config, []*connector.Device) {
+	return e.snapshot()
+}
+
+// DeviceForTarget returns the device for a target, either a configured device
+// (by name or discovered hostname) or a device matching a host pattern. nil is
+// returned if the target is unknown.
+func (e *Exporter) DeviceForTarget(target string) (*connector.Device, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.deviceForTarget(target, e.devices, e.cfg)
+}
+
+// CheckDevice checks whether a device not loaded with the config (e.g. from
+// an inventory) can be scraped with the settings of the config c
+func (e *Exporter) CheckDevice(c *Config, d *DeviceConfig) error {
+	if !d.IsHostPattern {
+		_, err := e.deviceFromDeviceConfig(d, d.TargetName(), c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.checkStaticLabels(&config.Config{Features: c.Features, Devices: []*config.DeviceConfig{d}})
+}
+
+// Describe implements prometheus.Collector interface. The exporter is an
+// unchecked collector since the metrics depend on the config and the devices.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+}
+
+// Collect implements prometheus.Collector interface. All devices of the config
+// are scraped, the deadline of the scrape is set by WithCollectTimeout.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	cfg, devs := e.snapshot()
+
+	ctx, cancel := e.collectContext()
+	defer cancel()
+
+	e.newJunosCollector(ctx, cfg, devs, "").Collect(ch)
+}
+
+// collectContext returns the context of a scrape started by Collect
+func (e *Exporter) collectContext() (context.Context, context.CancelFunc) {
+	if e.collectTimeout > 0 {
+		return context.WithTimeout(context.Background(), e.collectTimeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+// snapshot returns the config and the devices of the exporter. Scrapes use a
+// snapshot instead of holding the lock, so a reload does not wait for them.
+func (e *Exporter) snapshot() (*config.Config, []*connector.Device) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.cfg, e.devices
+}
+
+// staleDevices returns the connected devices which were removed from the
+// config or whose credentials, address or SSH options changed
+func (e *Exporter) staleDevices(connected, devices []*connector.Device, cfg *config.Config) []*connector.Device {
+	stale := make([]*connector.Device, 0)
+	for _, d := range connected {
+		nd, err := e.deviceForTarget(d.Host, devices, cfg)
+		if err != nil {
+			log.Errorf("Could not get config for %s: %s", d.Host, err)
+		}
+
+		switch {
+		case nd == nil:
+			log.Infof("Closing connection to %s: device was removed from config", d.Host)
+		case nd.AuthID != d.AuthID:
+			log.Infof("Closing connection to %s: credentials changed", d.Host)
+		case nd.Address != d.Address || nd.Port != d.Port || nd.KeepAliveInterval != d.KeepAliveInterval || nd.KeepAliveTimeout != d.KeepAliveTimeout:
+			log.Infof("Closing connection to %s: connection settings changed", d.Host)
+		default:
+			continue
+		}
+
+		stale = append(stale, d)
+	}
+
+	return stale
+}
+
+// collectorEnabled returns whether the collector is used for a device with the feature set f
+func (e *Exporter) collectorEnabled(f *config.FeatureConfig, r collector.Registration) bool {
+	if e.features != nil {
+		return e.features[r.Feature]
+	}
+
+	return f.CollectorEnabled(r)
+}
+
+// collectorRegistrations returns the collectors the exporter was created with
+// (all registered collectors by default)
+func (e *Exporter) collectorRegistrations() []collector.Registration {
+	if e.registrations != nil {
+		return e.registrations
+	}
+
+	return collector.Registrations()
+}
+
+func (e *Exporter) devicesForRequest(target string, cfg *config.Config, devices []*connector.Device) ([]*connector.Device, error) {
+	if target == "" {
+		return devices, nil
+	}
+
+	d, err := e.deviceForTarget(target, devices, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return nil, fmt.Errorf("the target '%s' is not defined in the configuration file", target)
+	}
+
+	return []*connector.Device{d}, nil
+}
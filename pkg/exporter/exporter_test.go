@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+)
+
+type fakeConnectionManager struct {
+	connected []*connector.Device
+	closed    []string
+}
+
+func (m *fakeConnectionManager) GetSSHConnection(device *connector.Device) (*connector.SSHConnection, error) {
+	return nil, errors.New("unreachable")
+}
+
+func (m *fakeConnectionManager) Devices() []*connector.Device {
+	return m.connected
+}
+
+func (m *fakeConnectionManager) Close(host string) {
+	m.closed = append(m.closed, host)
+}
+
+func (m *fakeConnectionManager) CloseAll() {
+}
+
+// blockingConnectionManager blocks connection attempts until release is closed
+type blockingConnectionManager struct {
+	fakeConnectionManager
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (m *blockingConnectionManager) GetSSHConnection(device *connector.Device) (*connector.SSHConnection, error) {
+	m.once.Do(func() {
+		close(m.started)
+	})
+	<-m.release
+
+	return nil, errors.New("unreachable")
+}
+
+func TestStaleDevices(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "unchanged"},
+			{Host: "changed", Password: "new-secret"},
+		},
+	}
+
+	e, err := New(c)
+	assert.NoError(t, err)
+
+	unchanged, err := e.DeviceForTarget("unchanged")
+	assert.NoError(t, err)
+
+	connected := []*connector.Device{
+		{Host: "unchanged", AuthID: unchanged.AuthID},
+		{Host: "changed", AuthID: unchanged.AuthID},
+		{Host: "removed", AuthID: unchanged.AuthID},
+	}
+
+	stale := e.staleDevices(connected, e.Devices(), c)
+
+	hosts := make([]string, len(stale))
+	for i, d := range stale {
+		hosts[i] = d.Host
+	}
+	assert.Equal(t, []string{"changed", "removed"}, hosts)
+}
+
+func TestReload(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+			{Host: "router2"},
+		},
+	}
+
+	m := &fakeConnectionManager{}
+	e, err := New(c, WithConnectionManager(m))
+	assert.NoError(t, err)
+
+	m.connected = e.Devices()
+
+	err = e.Reload(&config.Config{
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	})
+	assert.EqualError(t, err, "could not initialize config for device router1: no valid authentication method available")
+	assert.Same(t, c, e.Config(), "config should be kept on error")
+
+	err = e.Reload(&config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(e.Devices()))
+	assert.Equal(t, []string{"router2"}, m.closed)
+}
+
+func TestServeHTTP(t *testing.T) {
+	reg := collector.Registration{
+		Key:            "test",
+		Feature:        "test",
+		DefaultEnabled: true,
+		New: func(p *collector.Params) collector.RPCCollector {
+			return &slowCollector{}
+		},
+	}
+
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+			{Host: "router2"},
+		},
+	}
+	c.Features.Set("test", true)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectors(reg))
+	assert.NoError(t, err)
+
+	cols := e.collectorsForDevices(e.Devices(), c, "")
+	assert.Equal(t, 2, len(cols.collectors), "only the collectors passed to the exporter should be used")
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics?target=router1", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `junos_up{target="router1"} 0`)
+	assert.NotContains(t, w.Body.String(), `router2`)
+
+	// an offset exceeding the timeout of Prometheus must not fail the scrape
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/metrics?target=router1", nil)
+	r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "0.1")
+	e.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `junos_up{target="router1"} 0`)
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics?target=router3", nil))
+	assert.Equal(t, 400, w.Code)
+
+	assert.NoError(t, testutil.CollectAndCompare(e, strings.NewReader(`
+# HELP junos_up Scrape of target was successful
+# TYPE junos_up gauge
+junos_up{target="router1"} 0
+junos_up{target="router2"} 0
+`), "junos_up"))
+}
+
+func TestServeHTTPMetricNaming(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	}
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}), WithMetricNaming(naming.V2))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `junos_up{target="router1"} 0`)
+	assert.Contains(t, w.Body.String(), `junos_collector_duration_seconds{target="router1"}`)
+}
+
+func TestServeHTTPMetricRelabelConfigs(t *testing.T) {
+	c, err := config.Load(strings.NewReader(`
+password: secret
+metric_relabel_configs:
+  - source_labels: [__name__]
+    regex: junos_collector_duration_seconds
+    action: drop
+devices:
+  - host: router1
+    metric_relabel_configs:
+      - source_labels: [target]
+        regex: router(\d+)
+        target_label: router_id
+  - host: router2
+`), true, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `junos_up{router_id="1",target="router1"} 0`)
+	assert.Contains(t, w.Body.String(), `junos_up{target="router2"} 0`)
+	assert.NotContains(t, w.Body.String(), "junos_collector_duration_seconds")
+}
+
+func TestReloadDuringScrape(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices:  []*config.DeviceConfig{{Host: "router1"}},
+	}
+
+	m := &blockingConnectionManager{started: make(chan struct{}), release: make(chan struct{})}
+	e, err := New(c, WithConnectionManager(m))
+	assert.NoError(t, err)
+
+	scraped := make(chan struct{})
+	go func() {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics", nil))
+		close(scraped)
+	}()
+	<-m.started
+
+	reloaded := make(chan error)
+	go func() {
+		reloaded <- e.Reload(&config.Config{
+			Password: "secret",
+			Devices:  []*config.DeviceConfig{{Host: "router2"}},
+		})
+	}()
+
+	select {
+	case err := <-reloaded:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("reload waits for the running scrape")
+	}
+
+	close(m.release)
+	<-scraped
+
+	assert.Equal(t, "router2", e.Devices()[0].Host)
+}
+
+func TestCollectTimeout(t *testing.T) {
+	c := &config.Config{Password: "secret"}
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}))
+	assert.NoError(t, err)
+
+	ctx, cancel := e.collectContext()
+	_, found := ctx.Deadline()
+	cancel()
+	assert.False(t, found, "no deadline by default")
+
+	e, err = New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectTimeout(time.Minute))
+	assert.NoError(t, err)
+
+	ctx, cancel = e.collectContext()
+	defer cancel()
+
+	deadline, found := ctx.Deadline()
+	assert.True(t, found)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
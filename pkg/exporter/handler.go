@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ServeHTTP implements http.Handler interface. The devices to scrape can be
+// selected by the parameter target (default: all devices), a logical system by
+// the parameter ls.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cfg, devices := e.snapshot()
+
+	ctx, span := tracer.Start(r.Context(), "HandleMetricsRequest")
+	defer span.End()
+
+	timeout, err := scrapeTimeout(r, e.scrapeTimeoutOffset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	devs, err := e.devicesForRequest(r.URL.Query().Get("target"), cfg, devices)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	logicalSystem := r.URL.Query().Get("ls")
+	if !cfg.LSEnabled && logicalSystem != "" {
+		err := fmt.Errorf("logical systems not enabled but the logical system '%s' in parameters", logicalSystem)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	l := log.New()
+	l.Level = log.ErrorLevel
+
+	promhttp.HandlerFor(e.gatherer(ctx, cfg, devs, logicalSystem), promhttp.HandlerOpts{
+		ErrorLog:      l,
+		ErrorHandling: promhttp.ContinueOnError,
+	}).ServeHTTP(w, r)
+}
+
+// Gather scrapes all devices of the config and returns their metrics.
+// Collectors which have not finished when ctx is done are abandoned, the
+// metrics gathered until then are returned.
+func (e *Exporter) Gather(ctx context.Context) ([]*dto.MetricFamily, error) {
+	cfg, devs := e.snapshot()
+
+	return e.gatherer(ctx, cfg, devs, "").Gather()
+}
+
+// gatherer returns a gatherer scraping the devices. The metrics of the devices
+// are named according to the naming scheme of the exporter by the collector.
+func (e *Exporter) gatherer(ctx context.Context, cfg *config.Config, devs []*connector.Device, logicalSystem string) prometheus.Gatherer {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(e.newJunosCollector(ctx, cfg, devs, logicalSystem))
+
+	return reg
+}
+
+// scrapeTimeout returns the time budget for a scrape derived from the timeout
+// Prometheus sends in the X-Prometheus-Scrape-Timeout-Seconds header minus the
+// configured offset. If the offset is not lower than the timeout, the timeout
+// of Prometheus is used. A zero duration means there is no deadline.
+func scrapeTimeout(r *http.Request, offset time.Duration) (time.Duration, error) {
+	v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds")
+	if v == "" {
+		return 0, nil
+	}
+
+	seconds, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse timeout from Prometheus header: %w", err)
+	}
+
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout <= offset {
+		log.Warnf("Timeout offset (%s) should be lower than Prometheus scrape timeout (%ss), using the scrape timeout", offset, v)
+		return timeout, nil
+	}
+
+	return timeout - offset, nil
+}
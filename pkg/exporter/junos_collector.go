@@ -0,0 +1,604 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/discovery"
+	"github.com/czerwonk/junos_exporter/pkg/dynamiclabels"
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+	"github.com/czerwonk/junos_exporter/pkg/relabel"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const prefix = "junos_"
+
+const (
+	// defaultLogicalSystem is the logical_system label value for the main system of a device
+	defaultLogicalSystem = "default"
+
+	// defaultRoutingInstance is the routing_instance label value for the default routing instance of a device
+	defaultRoutingInstance = "master"
+
+	// discoveryTTL is the time logical systems and routing instances discovered on a device are cached
+	discoveryTTL = 10 * time.Minute
+
+	// maxAbandonedCollectors is the maximum number of collectors of a device still running after
+	// the scrape deadline. No collectors are run for the device until one of them has finished.
+	maxAbandonedCollectors = 4
+)
+
+var errTooManyAbandonedCollectors = errors.New("too many collectors of previous scrapes still running")
+
+var (
+	scrapeCollectorDurationDesc *prometheus.Desc
+	scrapeCollectorTimeoutDesc  *prometheus.Desc
+	seriesLimitExceededDesc     *prometheus.Desc
+	scrapeDurationDesc          *prometheus.Desc
+	upDesc                      *prometheus.Desc
+)
+
+func init() {
+	upDesc = prometheus.NewDesc(prefix+"up", "Scrape of target was successful", []string{"target"}, nil)
+	scrapeDurationDesc = prometheus.NewDesc(prefix+"collector_duration_seconds", "Duration of a collector scrape for one target", []string{"target"}, nil)
+	scrapeCollectorDurationDesc = prometheus.NewDesc(prefix+"collect_duration_seconds", "Duration of a scrape by collector and target", []string{"target", "collector"}, nil)
+	scrapeCollectorTimeoutDesc = prometheus.NewDesc(prefix+"collect_timeout", "Collector did not finish before the scrape deadline (1 = timed out)", []string{"target", "collector"}, nil)
+	seriesLimitExceededDesc = prometheus.NewDesc(prefix+"collector_series_limit_exceeded", "Series of the collector were dropped or truncated because a series limit was exceeded (1 = exceeded)", []string{"target", "collector"}, nil)
+}
+
+type junosCollector struct {
+	cfg           *config.Config
+	devices       []*connector.Device
+	clients       map[*connector.Device]*rpc.Client
+	collectors    *collectors
+	logicalSystem string
+	ctx           context.Context
+	status        *statusStore
+	metricNaming  naming.Mode
+
+	// discovered caches the names of logical systems and routing instances discovered on the devices
+	discovered *sync.Map
+
+	// discoveredNames caches the hostnames of devices with discover_name enabled (key: host of the device)
+	discoveredNames *sync.Map
+
+	// abandoned counts the collectors still running after the scrape deadline (key: host of the device)
+	abandoned *sync.Map
+}
+
+// discoveredNames are names of logical systems or routing instances discovered on a device
+type discoveredNames struct {
+	names   []string
+	expires time.Time
+}
+
+// abandonedCollectors counts the collectors of a device still running after the scrape deadline
+type abandonedCollectors struct {
+	count atomic.Int32
+}
+
+// full returns whether the maximum of abandoned collectors is reached
+func (a *abandonedCollectors) full() bool {
+	return a != nil && a.count.Load() >= maxAbandonedCollectors
+}
+
+// add counts an abandoned collector until it has finished
+func (a *abandonedCollectors) add() {
+	if a != nil {
+		a.count.Add(1)
+	}
+}
+
+// done is called when an abandoned collector has finished
+func (a *abandonedCollectors) done() {
+	if a != nil {
+		a.count.Add(-1)
+	}
+}
+
+// scrapePass is a run of collectors against a device. Metrics of a pass are
+// extended by the labels of the pass (e.g. the logical system).
+type scrapePass struct {
+	labels     prometheus.Labels
+	collectors []collector.RPCCollector
+}
+
+// passCollector adapts a scrape pass to the prometheus.Collector interface
+// so it can be wrapped with the labels of the pass
+type passCollector struct {
+	collect func(ch chan<- prometheus.Metric)
+}
+
+// Describe implements prometheus.Collector interface
+func (p *passCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+// Collect implements prometheus.Collector interface
+func (p *passCollector) Collect(ch chan<- prometheus.Metric) {
+	p.collect(ch)
+}
+
+func (e *Exporter) newJunosCollector(ctx context.Context, cfg *config.Config, devices []*connector.Device, logicalSystem string) *junosCollector {
+	clients := make(map[*connector.Device]*rpc.Client)
+
+	for _, d := range devices {
+		cl, err := e.clientForDevice(d, cfg)
+		if err != nil {
+			log.Errorf("Could not connect to %s: %s", d, err)
+			continue
+		}
+
+		clients[d] = cl
+	}
+
+	return &junosCollector{
+		cfg:             cfg,
+		devices:         devices,
+		collectors:      e.collectorsForDevices(devices, cfg, logicalSystem),
+		clients:         clients,
+		logicalSystem:   logicalSystem,
+		ctx:             ctx,
+		status:          e.status,
+		metricNaming:    e.metricNaming,
+		discovered:      &e.discovered,
+		discoveredNames: &e.discoveredNames,
+		abandoned:       &e.abandoned,
+	}
+}
+
+// passesForDevice returns the scrape passes for a device. The static labels
+// configured for the device are added to every pass.
+func (c *junosCollector) passesForDevice(device *connector.Device, logicalSystem string) []*scrapePass {
+	passes := c.systemPassesForDevice(device, logicalSystem)
+
+	static := c.staticLabels(device.Host)
+	if len(static) == 0 {
+		return passes
+	}
+
+	for _, p := range passes {
+		l := maps.Clone(static)
+		maps.Copy(l, p.labels)
+		p.labels = l
+	}
+
+	return passes
+}
+
+// systemPassesForDevice returns one pass for the main system and one pass for
+// each logical system and routing instance configured for the device, unless
+// a logical system was selected in the request.
+func (c *junosCollector) systemPassesForDevice(device *connector.Device, logicalSystem string) []*scrapePass {
+	main := &scrapePass{
+		collectors: c.collectors.collectorsForDevice(device),
+	}
+
+	if logicalSystem != "" {
+		return []*scrapePass{main}
+	}
+
+	dc := c.cfg.FindDeviceConfig(device.Host)
+	if dc == nil {
+		return []*scrapePass{main}
+	}
+
+	logicalSystems := c.namesForDevice(device, "ls", dc.LogicalSystems, config.AllLogicalSystems, discovery.LogicalSystems)
+	routingInstances := c.namesForDevice(device, "ri", dc.RoutingInstances, config.AllRoutingInstances, discovery.RoutingInstances)
+
+	main.labels = passLabels(len(logicalSystems) > 0, defaultLogicalSystem, len(routingInstances) > 0, defaultRoutingInstance)
+	if len(routingInstances) > 0 {
+		main.collectors = c.collectors.collectorsForDefaultRoutingInstance(device)
+	}
+	passes := []*scrapePass{main}
+
+	for _, ls := range logicalSystems {
+		c.collectors.initCollectorsForLogicalSystem(device, ls)
+		passes = append(passes, &scrapePass{
+			labels:     passLabels(true, ls, len(routingInstances) > 0, defaultRoutingInstance),
+			collectors: c.collectors.collectorsForLogicalSystem(device, ls),
+		})
+	}
+
+	for _, ri := range routingInstances {
+		c.collectors.initCollectorsForRoutingInstance(device, ri)
+		passes = append(passes, &scrapePass{
+			labels:     passLabels(len(logicalSystems) > 0, defaultLogicalSystem, true, ri),
+			collectors: c.collectors.collectorsForRoutingInstance(device, ri),
+		})
+	}
+
+	return passes
+}
+
+func passLabels(withLogicalSystem bool, logicalSystem string, withRoutingInstance bool, routingInstance string) prometheus.Labels {
+	l := prometheus.Labels{}
+
+	if withLogicalSystem {
+		l["logical_system"] = logicalSystem
+	}
+
+	if withRoutingInstance {
+		l["routing_instance"] = routingInstance
+	}
+
+	return l
+}
+
+// namesForDevice returns the configured names of logical systems or routing
+// instances. If the list contains the keyword for all, the names are
+// discovered on the device instead. Discovered names are cached for
+// discoveryTTL (key: host of the device and kind of the names).
+func (c *junosCollector) namesForDevice(device *connector.Device, kind string, configured []string, all string, discover func(collector.Client) ([]string, error)) []string {
+	if !slices.Contains(configured, all) {
+		return configured
+	}
+
+	key := device.Host + "/" + kind
+	if c.discovered != nil {
+		if d, found := c.discovered.Load(key); found && time.Now().Before(d.(*discoveredNames).expires) {
+			return d.(*discoveredNames).names
+		}
+	}
+
+	cl, found := c.clients[device]
+	if !found {
+		return nil
+	}
+
+	names, err := discover(&clientTracingAdapter{cl: cl, ctx: c.ctx})
+	if err != nil {
+		log.Errorf("Discovery on %s failed: %s", device, err)
+		return nil
+	}
+
+	if c.discovered != nil {
+		c.discovered.Store(key, &discoveredNames{names: names, expires: time.Now().Add(discoveryTTL)})
+	}
+
+	return names
+}
+
+func deviceInterfaceRegex(cfg *config.Config, host string) *regexp.Regexp {
+	dc := cfg.FindDeviceConfig(host)
+
+	if dc != nil {
+		return dc.IfDescReg
+	}
+
+	if cfg.IfDescReg != nil {
+		return cfg.IfDescReg
+	}
+
+	return dynamiclabels.DefaultInterfaceDescRegex()
+}
+
+// deviceCollectorOptions returns the collector options of a device.
+// Options not set in the config fall back to the defaults of the exporter.
+func (e *Exporter) deviceCollectorOptions(cfg *config.Config, host string) *config.CollectorOptions {
+	opts := *cfg.CollectorOptionsForDevice(cfg.FindDeviceConfig(host))
+	if e.collectorDefaults == nil {
+		return &opts
+	}
+
+	if d := e.collectorDefaults.Alarm; d != nil && opts.Alarm.Filter == "" {
+		alarmOpts := *opts.Alarm
+		alarmOpts.Filter = d.Filter
+		opts.Alarm = &alarmOpts
+	}
+
+	if d := e.collectorDefaults.MNHA; d != nil && len(opts.MNHA.SRGIDs) == 0 {
+		mnhaOpts := *opts.MNHA
+		mnhaOpts.SRGIDs = d.SRGIDs
+		opts.MNHA = &mnhaOpts
+	}
+
+	return &opts
+}
+
+func (e *Exporter) clientForDevice(device *connector.Device, cfg *config.Config) (*rpc.Client, error) {
+	conn, err := e.connManager.GetSSHConnection(device)
+	if err != nil {
+		e.status.connectFailed(device.Host, err)
+		return nil, err
+	}
+
+	opts := []rpc.ClientOption{rpc.WithMetrics(e.rpcMetrics)}
+	if e.rpcDebug {
+		opts = append(opts, rpc.WithDebug())
+	}
+
+	if cfg.Features.Satellite {
+		opts = append(opts, rpc.WithSatellite())
+	}
+
+	if cfg.Features.License {
+		opts = append(opts, rpc.WithLicenseInformation())
+	}
+
+	c := rpc.NewClient(conn, opts...)
+	return c, nil
+}
+
+// Describe implements prometheus.Collector interface
+func (c *junosCollector) Describe(ch chan<- *prometheus.Desc) {
+	// metrics of passes with additional labels differ in their label names
+	// from metrics of other passes and relabel rules can change the metrics,
+	// so the collector is unchecked in these cases
+	if c.hasLabeledPasses() || c.hasRelabelRules() {
+		return
+	}
+
+	c.describe(ch)
+}
+
+// hasLabeledPasses returns whether passes of a device can have additional labels. The passes are
+// determined in Collect, so it is derived from the logical systems, routing instances and static labels
+// configured for the devices.
+func (c *junosCollector) hasLabeledPasses() bool {
+	for _, d := range c.devices {
+		dc := c.cfg.FindDeviceConfig(d.Host)
+		if dc == nil {
+			continue
+		}
+
+		if len(dc.Labels) > 0 {
+			return true
+		}
+
+		if c.logicalSystem == "" && (len(dc.LogicalSystems) > 0 || len(dc.RoutingInstances) > 0) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *junosCollector) hasRelabelRules() bool {
+	for _, d := range c.devices {
+		if len(c.cfg.RelabelConfigsForDevice(d.Host)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *junosCollector) describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- scrapeDurationDesc
+	ch <- scrapeCollectorDurationDesc
+	ch <- scrapeCollectorTimeoutDesc
+	ch <- seriesLimitExceededDesc
+
+	for _, col := range c.collectors.allEnabledCollectors() {
+		col.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector interface
+func (c *junosCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, span := tracer.Start(c.ctx, "Collect")
+	defer span.End()
+
+	var wg sync.WaitGroup
+	for _, d := range c.devices {
+		wg.Go(func() {
+			c.collectForHostWithRules(ctx, d, ch)
+		})
+	}
+	wg.Wait()
+}
+
+// collectForHostWithRules collects the metrics of a device, names them according
+// to the naming scheme and applies the relabel rules of the device to them. The
+// rules are applied after naming, so they match the names which are reported.
+func (c *junosCollector) collectForHostWithRules(ctx context.Context, device *connector.Device, ch chan<- prometheus.Metric) {
+	var col prometheus.Collector = &passCollector{
+		collect: func(ch chan<- prometheus.Metric) {
+			c.collectForHost(ctx, device, ch)
+		},
+	}
+	col = naming.Collector(col, c.metricNaming)
+
+	rules := c.cfg.RelabelConfigsForDevice(device.Host)
+	if len(rules) > 0 {
+		col = relabel.Collector(col, rules)
+	}
+
+	col.Collect(ch)
+}
+
+func (c *junosCollector) collectForHost(ctx context.Context, device *connector.Device, ch chan<- prometheus.Metric) {
+	ctx, span := tracer.Start(ctx, "CollectForHost", trace.WithAttributes(
+		attribute.String("host", device.Host),
+	))
+	defer span.End()
+
+	t := time.Now()
+	defer func() {
+		c.status.scraped(device.Host, time.Since(t))
+	}()
+
+	target := c.targetForDevice(device)
+	limiter := newSeriesLimiter(c.cfg.SeriesLimitsForDevice(device.Host))
+
+	for _, p := range c.passesForDevice(device, c.logicalSystem) {
+		if len(p.labels) == 0 {
+			c.collectPass(ctx, device, target, p, limiter, ch)
+			continue
+		}
+
+		prometheus.WrapCollectorWith(p.labels, &passCollector{
+			collect: func(ch chan<- prometheus.Metric) {
+				c.collectPass(ctx, device, target, p, limiter, ch)
+			},
+		}).Collect(ch)
+	}
+}
+
+// targetForDevice returns the value of the target label of a device. If
+// discover_name is enabled for the device, the hostname configured on the
+// device is used instead of the name in the config.
+func (c *junosCollector) targetForDevice(device *connector.Device) string {
+	dc := c.cfg.FindDeviceConfig(device.Host)
+	if dc == nil || !dc.DiscoverName {
+		return device.Host
+	}
+
+	if name, found := c.discoveredNames.Load(device.Host); found {
+		return name.(string)
+	}
+
+	cl, found := c.clients[device]
+	if !found {
+		return device.Host
+	}
+
+	name, err := discovery.Hostname(&clientTracingAdapter{cl: cl, ctx: c.ctx})
+	if err != nil {
+		log.Errorf("Hostname discovery on %s failed: %s", device, err)
+		return device.Host
+	}
+
+	c.discoveredNames.Store(device.Host, name)
+	return name
+}
+
+func (c *junosCollector) collectPass(ctx context.Context, device *connector.Device, target string, pass *scrapePass, limiter *seriesLimiter, ch chan<- prometheus.Metric) {
+	l := []string{target}
+
+	t := time.Now()
+	defer func() {
+		ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, time.Since(t).Seconds(), l...)
+	}()
+
+	cl, found := c.clients[device]
+	if !found {
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0, l...)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1, l...)
+
+	for _, col := range pass.collectors {
+		ctx, sp := tracer.Start(ctx, "CollectForHostWithCollector", trace.WithAttributes(
+			attribute.String("collector", col.Name()),
+		))
+
+		cta := &clientTracingAdapter{
+			cl:  cl,
+			ctx: ctx,
+		}
+
+		ct := time.Now()
+		limit := limiter.forCollector(c.collectors.featureOf(col))
+		err := runCollectorWithLimit(ctx, col, cta, ch, l, limit, c.abandonedFor(device.Host))
+
+		status := CollectorStatus{
+			Feature:      c.collectors.featureOf(col),
+			Name:         col.Name(),
+			LastDuration: time.Since(ct),
+		}
+
+		timedOut := 0.0
+		if ctx.Err() != nil {
+			timedOut = 1
+			status.TimedOut = true
+			log.Warnf("%s: collector for %s did not finish before the scrape deadline", col.Name(), device.Host)
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			sp.RecordError(err)
+			sp.SetStatus(codes.Error, err.Error())
+			status.LastError = err.Error()
+			log.Errorln(col.Name() + ": " + err.Error())
+		}
+
+		ch <- prometheus.MustNewConstMetric(scrapeCollectorDurationDesc, prometheus.GaugeValue, time.Since(ct).Seconds(), append(l, col.Name())...)
+		ch <- prometheus.MustNewConstMetric(scrapeCollectorTimeoutDesc, prometheus.GaugeValue, timedOut, append(l, col.Name())...)
+
+		if limit != nil {
+			exceeded := 0.0
+			if limit.exceeded {
+				exceeded = 1
+				log.Warnf("%s: series limit for %s exceeded (%d series, limit %d), %s", col.Name(), device.Host, limit.count, max(limit.max, 0), limit.actionDescription())
+			}
+
+			ch <- prometheus.MustNewConstMetric(seriesLimitExceededDesc, prometheus.GaugeValue, exceeded, append(l, col.Name())...)
+			status.SeriesLimitExceeded = limit.exceeded
+		}
+
+		c.status.collected(device.Host, status)
+		sp.End()
+	}
+}
+
+// abandonedFor returns the counter of abandoned collectors of a device
+func (c *junosCollector) abandonedFor(host string) *abandonedCollectors {
+	if c.abandoned == nil {
+		return nil
+	}
+
+	a, _ := c.abandoned.LoadOrStore(host, &abandonedCollectors{})
+	return a.(*abandonedCollectors)
+}
+
+// runCollector runs a collector and forwards its metrics to ch until the
+// collector finishes or the context is done. A collector still running at that
+// point is abandoned: metrics it emits afterwards are discarded and the client
+// refuses further commands, since it is bound to the same context. Abandoned
+// collectors are counted in abandoned (nil: not counted) and the collector is
+// not run if the maximum is reached.
+func runCollector(ctx context.Context, col collector.RPCCollector, cl collector.Client, ch chan<- prometheus.Metric, labelValues []string, abandoned *abandonedCollectors) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if abandoned.full() {
+		return errTooManyAbandonedCollectors
+	}
+
+	metrics := make(chan prometheus.Metric)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- col.Collect(cl, metrics, labelValues)
+		close(metrics)
+	}()
+
+	for {
+		select {
+		case m, ok := <-metrics:
+			if !ok {
+				return <-done
+			}
+
+			ch <- m
+		case <-ctx.Done():
+			abandoned.add()
+			go func() {
+				for range metrics {
+				}
+				abandoned.done()
+			}()
+
+			return ctx.Err()
+		}
+	}
+}
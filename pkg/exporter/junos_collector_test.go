@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+var testDesc = prometheus.NewDesc("junos_test_value", "Test value", []string{"target"}, nil)
+
+type slowCollector struct {
+	delay time.Duration
+}
+
+func (*slowCollector) Name() string {
+	return "Slow"
+}
+
+func (*slowCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- testDesc
+}
+
+func (c *slowCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	ch <- prometheus.MustNewConstMetric(testDesc, prometheus.GaugeValue, 1, labelValues...)
+	time.Sleep(c.delay)
+	ch <- prometheus.MustNewConstMetric(testDesc, prometheus.GaugeValue, 2, labelValues...)
+
+	return nil
+}
+
+func TestScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		offset   time.Duration
+		expected time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "no header",
+			offset:   500 * time.Millisecond,
+			expected: 0,
+		},
+		{
+			name:     "header with offset",
+			header:   "10",
+			offset:   500 * time.Millisecond,
+			expected: 9500 * time.Millisecond,
+		},
+		{
+			name:     "fractional header",
+			header:   "2.5",
+			expected: 2500 * time.Millisecond,
+		},
+		{
+			name:     "offset exceeds timeout",
+			header:   "1",
+			offset:   2 * time.Second,
+			expected: time.Second,
+		},
+		{
+			name:    "invalid header",
+			header:  "abc",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/metrics", nil)
+			if test.header != "" {
+				r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", test.header)
+			}
+
+			timeout, err := scrapeTimeout(r, test.offset)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, timeout)
+		})
+	}
+}
+
+func TestRunCollectorFinishes(t *testing.T) {
+	ch := make(chan prometheus.Metric, 10)
+
+	err := runCollector(context.Background(), &slowCollector{}, nil, ch, []string{"router1"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ch), "metric count")
+}
+
+func TestRunCollectorAbandonedAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	ch := make(chan prometheus.Metric, 10)
+
+	err := runCollector(ctx, &slowCollector{delay: time.Second}, nil, ch, []string{"router1"}, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, len(ch), "metrics gathered before the deadline should be kept")
+}
+
+// commandCollector runs a command, waits until it is released and runs another command
+type commandCollector struct {
+	release  chan struct{}
+	finished chan struct{}
+}
+
+func (*commandCollector) Name() string {
+	return "Command"
+}
+
+func (*commandCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- testDesc
+}
+
+func (c *commandCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	defer close(c.finished)
+
+	client.RunCommandAndParse("show version", &struct{}{})
+	<-c.release
+
+	return client.RunCommandAndParse("show chassis alarms", &struct{}{})
+}
+
+func TestRunCollectorNoCommandAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	m := rpc.NewMetrics()
+	cl := rpc.NewClient(connector.NewSSHConnection(&connector.Device{Host: "router1"}, time.Second, time.Second), rpc.WithMetrics(m))
+	col := &commandCollector{release: make(chan struct{}), finished: make(chan struct{})}
+	a := &abandonedCollectors{}
+
+	err := runCollector(ctx, col, &clientTracingAdapter{cl: cl, ctx: ctx}, make(chan prometheus.Metric, 10), []string{"router1"}, a)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), a.count.Load(), "abandoned collectors")
+
+	close(col.release)
+	<-col.finished
+
+	assert.Eventually(t, func() bool {
+		return a.count.Load() == 0
+	}, time.Second, 10*time.Millisecond, "abandoned collector finished")
+	assert.Equal(t, 1, testutil.CollectAndCount(m, "junos_exporter_rpc_errors_total"), "only the command before the deadline is run")
+}
+
+func TestRunCollectorTooManyAbandoned(t *testing.T) {
+	a := &abandonedCollectors{}
+	a.count.Store(maxAbandonedCollectors)
+
+	ch := make(chan prometheus.Metric, 10)
+
+	err := runCollector(context.Background(), &slowCollector{}, nil, ch, []string{"router1"}, a)
+	assert.ErrorIs(t, err, errTooManyAbandonedCollectors)
+	assert.Equal(t, 0, len(ch), "collector is not run")
+
+	a.done()
+	err = runCollector(context.Background(), &slowCollector{}, nil, ch, []string{"router1"}, a)
+	assert.NoError(t, err)
+}
+
+func TestLogicalSystemPasses(t *testing.T) {
+	cfg := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host:           "router1",
+				LogicalSystems: []string{"ls1", "ls2"},
+				Features: &config.FeatureConfig{
+					Alarm: true,
+					BGP:   true,
+				},
+			},
+		},
+	}
+
+	d := &connector.Device{Host: "router1"}
+	c := &junosCollector{
+		cfg:        cfg,
+		devices:    []*connector.Device{d},
+		clients:    make(map[*connector.Device]*rpc.Client),
+		collectors: (&Exporter{}).collectorsForDevices([]*connector.Device{d}, cfg, ""),
+		ctx:        context.Background(),
+	}
+	passes := c.passesForDevice(d, "")
+	assert.Equal(t, 3, len(passes), "pass count")
+	assert.Equal(t, 2, len(passes[0].collectors), "main system collector count")
+	assert.Equal(t, 1, len(passes[1].collectors), "logical system collector count")
+	assert.Equal(t, "BGP", passes[1].collectors[0].Name())
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	mfs, err := reg.Gather()
+	assert.NoError(t, err)
+
+	logicalSystems := make([]string, 0)
+	for _, mf := range mfs {
+		if mf.GetName() != "junos_up" {
+			continue
+		}
+
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "logical_system" {
+					logicalSystems = append(logicalSystems, lp.GetValue())
+				}
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"default", "ls1", "ls2"}, logicalSystems)
+}
+
+func TestRequestedLogicalSystemHasSinglePass(t *testing.T) {
+	cfg := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host:           "router1",
+				LogicalSystems: []string{"ls1"},
+				Features: &config.FeatureConfig{
+					BGP: true,
+				},
+			},
+		},
+	}
+
+	d := &connector.Device{Host: "router1"}
+	c := &junosCollector{
+		cfg:        cfg,
+		collectors: (&Exporter{}).collectorsForDevices([]*connector.Device{d}, cfg, "ls2"),
+		clients:    make(map[*connector.Device]*rpc.Client),
+	}
+
+	passes := c.passesForDevice(d, "ls2")
+	assert.Equal(t, 1, len(passes), "pass count")
+	assert.Empty(t, passes[0].labels)
+}
+
+func TestRoutingInstancePasses(t *testing.T) {
+	cfg := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host:             "router1",
+				LogicalSystems:   []string{"ls1"},
+				RoutingInstances: []string{"CUSTOMER-A"},
+				Features: &config.FeatureConfig{
+					BGP:        true,
+					Interfaces: true,
+					OSPF:       true,
+				},
+			},
+		},
+	}
+
+	d := &connector.Device{Host: "router1"}
+	c := &junosCollector{
+		cfg:        cfg,
+		collectors: (&Exporter{}).collectorsForDevices([]*connector.Device{d}, cfg, ""),
+		clients:    make(map[*connector.Device]*rpc.Client),
+	}
+
+	passes := c.passesForDevice(d, "")
+	assert.Equal(t, 3, len(passes), "pass count")
+	assert.Equal(t, prometheus.Labels{"logical_system": "default", "routing_instance": "master"}, passes[0].labels)
+	assert.Equal(t, prometheus.Labels{"logical_system": "ls1", "routing_instance": "master"}, passes[1].labels)
+	assert.Equal(t, prometheus.Labels{"logical_system": "default", "routing_instance": "CUSTOMER-A"}, passes[2].labels)
+	assert.Equal(t, 3, len(passes[1].collectors), "logical system collector count")
+	assert.Equal(t, 2, len(passes[2].collectors), "routing instance collector count")
+}
+
+func TestDefaultRoutingInstancePass(t *testing.T) {
+	reg := collector.Registration{
+		Key:              "debug",
+		Feature:          "debug",
+		RoutingInstances: true,
+		New: func(p *collector.Params) collector.RPCCollector {
+			return &debugTestCollector{routingInstance: p.RoutingInstance}
+		},
+	}
+
+	dc := &config.DeviceConfig{
+		Host:     "router1",
+		Features: &config.FeatureConfig{},
+	}
+	dc.Features.Set("debug", true)
+	cfg := &config.Config{Devices: []*config.DeviceConfig{dc}}
+
+	d := &connector.Device{Host: "router1"}
+	c := &junosCollector{
+		cfg:        cfg,
+		collectors: (&Exporter{registrations: []collector.Registration{reg}}).collectorsForDevices([]*connector.Device{d}, cfg, ""),
+		clients:    make(map[*connector.Device]*rpc.Client),
+	}
+
+	passes := c.passesForDevice(d, "")
+	assert.Equal(t, []collector.RPCCollector{&debugTestCollector{}}, passes[0].collectors, "main system without routing instances")
+
+	dc.RoutingInstances = []string{"CUSTOMER-A"}
+	passes = c.passesForDevice(d, "")
+	assert.Equal(t, 2, len(passes), "pass count")
+	assert.Equal(t, []collector.RPCCollector{&debugTestCollector{routingInstance: "master"}}, passes[0].collectors, "main system is restricted to the default instance")
+	assert.Equal(t, []collector.RPCCollector{&debugTestCollector{routingInstance: "CUSTOMER-A"}}, passes[1].collectors)
+}
+
+func TestDiscoveredNamesCached(t *testing.T) {
+	d := &connector.Device{Host: "router1"}
+	c := &junosCollector{
+		clients:    map[*connector.Device]*rpc.Client{d: rpc.NewClient(connector.NewSSHConnection(d, time.Second, time.Second))},
+		ctx:        context.Background(),
+		discovered: &sync.Map{},
+	}
+
+	calls := 0
+	discover := func(collector.Client) ([]string, error) {
+		calls++
+		return []string{"ls1"}, nil
+	}
+
+	assert.Equal(t, []string{"ls1"}, c.namesForDevice(d, "ls", []string{"all"}, "all", discover))
+	assert.Equal(t, []string{"ls1"}, c.namesForDevice(d, "ls", []string{"all"}, "all", discover))
+	assert.Equal(t, 1, calls, "names are discovered once")
+
+	assert.Equal(t, []string{"ls2"}, c.namesForDevice(d, "ls", []string{"ls2"}, "all", discover))
+	assert.Equal(t, 1, calls, "configured names are not discovered")
+
+	c.discovered.Store("router1/ls", &discoveredNames{names: []string{"ls1"}, expires: time.Now().Add(-time.Second)})
+	c.namesForDevice(d, "ls", []string{"all"}, "all", discover)
+	assert.Equal(t, 2, calls, "names are discovered again after the TTL")
+}
+
+func TestStaticLabels(t *testing.T) {
+	cfg := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host:           "router1",
+				LogicalSystems: []string{"ls1"},
+				Labels:         map[string]string{"site": "fra1", "role": "pe"},
+				Features: &config.FeatureConfig{
+					BGP: true,
+				},
+			},
+			{
+				Host: "router2",
+				Features: &config.FeatureConfig{
+					BGP: true,
+				},
+			},
+		},
+	}
+
+	d1 := &connector.Device{Host: "router1"}
+	d2 := &connector.Device{Host: "router2"}
+	devs := []*connector.Device{d1, d2}
+	c := &junosCollector{
+		cfg:        cfg,
+		devices:    devs,
+		clients:    make(map[*connector.Device]*rpc.Client),
+		collectors: (&Exporter{}).collectorsForDevices(devs, cfg, ""),
+		ctx:        context.Background(),
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	mfs, err := reg.Gather()
+	assert.NoError(t, err)
+
+	labels := make(map[string]map[string]string)
+	for _, mf := range mfs {
+		if mf.GetName() != "junos_up" {
+			continue
+		}
+
+		for _, m := range mf.GetMetric() {
+			l := make(map[string]string)
+			for _, lp := range m.GetLabel() {
+				l[lp.GetName()] = lp.GetValue()
+			}
+			labels[l["target"]+"/"+l["logical_system"]] = l
+		}
+	}
+
+	assert.Equal(t, map[string]map[string]string{
+		"router1/default": {"target": "router1", "logical_system": "default", "site": "fra1", "role": "pe"},
+		"router1/ls1":     {"target": "router1", "logical_system": "ls1", "site": "fra1", "role": "pe"},
+		"router2/":        {"target": "router2"},
+	}, labels)
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+)
+
+// checkStaticLabels makes sure the static labels of the devices do not clash
+// with the labels of the exporter or the collectors enabled for the device
+func (e *Exporter) checkStaticLabels(c *config.Config) error {
+	for _, dc := range c.Devices {
+		if len(dc.Labels) == 0 {
+			continue
+		}
+
+		cols := e.collectorsForDevices([]*connector.Device{{Host: dc.Host}}, &config.Config{
+			Features: *c.FeaturesForDeviceConfig(dc),
+		}, "")
+
+		// errors of the collectors themselves are not caused by the static labels
+		err := prometheus.NewRegistry().Register(&junosCollector{collectors: cols})
+		if err != nil {
+			return fmt.Errorf("device %s: %w", dc.Host, err)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(dc.Labels)) {
+			if config.ReservedLabelName(name) {
+				// logical_system and routing_instance would be overwritten by the labels of the scrape passes
+				return fmt.Errorf("device %s: label name %q is used by the exporter", dc.Host, name)
+			}
+
+			reg := prometheus.WrapRegistererWith(prometheus.Labels{name: dc.Labels[name]}, prometheus.NewRegistry())
+			err := reg.Register(&junosCollector{collectors: cols})
+
+			var are prometheus.AlreadyRegisteredError
+			switch {
+			case err == nil, errors.As(err, &are):
+			case model.LabelName(name).IsValid():
+				// the collectors register without the label, so the label collides with a label of a collector
+				return fmt.Errorf("device %s: label name %q is used by a collector", dc.Host, name)
+			default:
+				return fmt.Errorf("device %s: label %q: %w", dc.Host, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// staticLabels returns the labels configured for a device
+func (c *junosCollector) staticLabels(host string) prometheus.Labels {
+	dc := c.cfg.FindDeviceConfig(host)
+	if dc == nil {
+		return nil
+	}
+
+	return prometheus.Labels(dc.Labels)
+}
+
+// StaticLabels returns the labels configured for the device of a target
+func (e *Exporter) StaticLabels(target string) map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	d, err := e.deviceForTarget(target, e.devices, e.cfg)
+	if err != nil || d == nil {
+		return nil
+	}
+
+	dc := e.cfg.FindDeviceConfig(d.Host)
+	if dc == nil {
+		return nil
+	}
+
+	return maps.Clone(dc.Labels)
+}
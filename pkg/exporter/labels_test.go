@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+func TestCheckStaticLabels(t *testing.T) {
+	c := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host:   "router1",
+				Labels: map[string]string{"site": "fra1", "role": "pe"},
+				Features: &config.FeatureConfig{
+					BGP: true,
+				},
+			},
+		},
+	}
+	e := &Exporter{}
+	assert.NoError(t, e.checkStaticLabels(c))
+
+	c.Devices[0].Features.VirtualChassis = true
+	assert.EqualError(t, e.checkStaticLabels(c), `device router1: label name "role" is used by a collector`)
+}
+
+// descCollector describes a metric without collecting it
+type descCollector struct {
+	name string
+	desc *prometheus.Desc
+}
+
+func (c *descCollector) Name() string {
+	return c.name
+}
+
+func (c *descCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *descCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	return nil
+}
+
+func TestCheckStaticLabelsErrors(t *testing.T) {
+	registration := func(feature string, labels ...string) collector.Registration {
+		return collector.Registration{
+			Key:     feature,
+			Feature: feature,
+			New: func(p *collector.Params) collector.RPCCollector {
+				return &descCollector{name: feature, desc: prometheus.NewDesc("junos_test_info", "Test", labels, nil)}
+			},
+		}
+	}
+
+	c := &config.Config{
+		Devices: []*config.DeviceConfig{
+			{
+				Host:     "router1",
+				Labels:   map[string]string{"": "fra1"},
+				Features: &config.FeatureConfig{},
+			},
+		},
+	}
+	c.Devices[0].Features.Set("a", true)
+
+	e := &Exporter{registrations: []collector.Registration{registration("a", "target")}}
+	err := e.checkStaticLabels(c)
+	assert.ErrorContains(t, err, `device router1: label "": `)
+	assert.NotContains(t, err.Error(), "is used by a collector", "invalid label names are no collisions")
+
+	c.Devices[0].Labels = map[string]string{"site": "fra1"}
+	c.Devices[0].Features.Set("b", true)
+	e = &Exporter{registrations: []collector.Registration{registration("a", "target"), registration("b", "target", "name")}}
+	err = e.checkStaticLabels(c)
+	assert.ErrorContains(t, err, "device router1: ")
+	assert.NotContains(t, err.Error(), "is used by a collector", "conflicts of the collectors are passed through")
+
+	e = &Exporter{registrations: []collector.Registration{registration("a", "target")}}
+	for _, name := range []string{"logical_system", "routing_instance"} {
+		c.Devices[0].Labels = map[string]string{name: "x"}
+		err = e.checkStaticLabels(c)
+		assert.EqualError(t, err, fmt.Sprintf("device router1: label name %q is used by the exporter", name))
+	}
+}
+
+func TestExporterStaticLabels(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1", Labels: map[string]string{"site": "fra1"}},
+			{Host: "router2"},
+		},
+	}
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"site": "fra1"}, e.StaticLabels("router1"))
+	assert.Empty(t, e.StaticLabels("router2"))
+	assert.Empty(t, e.StaticLabels("unknown"))
+}
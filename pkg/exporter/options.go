@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"time"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/naming"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+// Option configures an Exporter
+type Option func(*Exporter)
+
+// WithConnectionManager sets the connection manager used to connect to the devices
+// (default: a connector.SSHConnectionManager with default settings)
+func WithConnectionManager(m ConnectionManager) Option {
+	return func(e *Exporter) {
+		e.connManager = m
+	}
+}
+
+// WithCollectors sets the collectors of the exporter (default: all registered collectors).
+// A collector is used for a device if its feature is enabled for the device.
+func WithCollectors(regs ...collector.Registration) Option {
+	return func(e *Exporter) {
+		e.registrations = regs
+	}
+}
+
+// WithFeatures restricts the collectors of the exporter to the collectors of
+// the features. They are used for all devices, regardless of the features
+// enabled in the config.
+func WithFeatures(features ...string) Option {
+	return func(e *Exporter) {
+		e.features = make(map[string]bool)
+		for _, f := range features {
+			e.features[f] = true
+		}
+	}
+}
+
+// WithDefaultCredentials sets the credentials used for devices without credentials in the config.
+// A key file takes precedence over passwords set in the config.
+func WithDefaultCredentials(username, password, keyFile, keyPassphrase string) Option {
+	return func(e *Exporter) {
+		e.username = username
+		e.password = password
+		e.keyFile = keyFile
+		e.keyPassphrase = keyPassphrase
+	}
+}
+
+// WithCollectorDefaults sets the options of collectors used if an option is not set in the config
+func WithCollectorDefaults(o *CollectorOptions) Option {
+	return func(e *Exporter) {
+		e.collectorDefaults = o
+	}
+}
+
+// WithoutSecrets creates an exporter to check a config: the secrets of
+// credential profiles are neither read nor executed, only their references are
+// checked. Such an exporter can not authenticate against devices.
+func WithoutSecrets() Option {
+	return func(e *Exporter) {
+		e.withoutSecrets = true
+	}
+}
+
+// WithRPCDebug enables logging of the RPCs sent to the devices and their responses
+func WithRPCDebug() Option {
+	return func(e *Exporter) {
+		e.rpcDebug = true
+	}
+}
+
+// WithScrapeTimeoutOffset sets the offset subtracted from the timeout announced
+// by Prometheus (X-Prometheus-Scrape-Timeout-Seconds) to get the scrape deadline
+func WithScrapeTimeoutOffset(d time.Duration) Option {
+	return func(e *Exporter) {
+		e.scrapeTimeoutOffset = d
+	}
+}
+
+// WithCollectTimeout sets the deadline of the scrapes started by Collect
+// (default: no deadline). Collectors which have not finished when the deadline
+// is exceeded are abandoned like the ones of a request to the handler.
+func WithCollectTimeout(d time.Duration) Option {
+	return func(e *Exporter) {
+		e.collectTimeout = d
+	}
+}
+
+// WithMetricNaming sets the naming scheme of the metrics of the devices (default: naming.V1)
+func WithMetricNaming(m naming.Mode) Option {
+	return func(e *Exporter) {
+		e.metricNaming = m
+	}
+}
+
+// WithRPCMetrics records the duration, reply size and errors of the RPCs sent to the devices in m
+func WithRPCMetrics(m *rpc.Metrics) Option {
+	return func(e *Exporter) {
+		e.rpcMetrics = m
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+)
+
+// ProbeResult is the result of a single scrape of a target
+type ProbeResult struct {
+	// Metrics are the metrics of the device
+	Metrics []*dto.MetricFamily
+
+	// Status is the state of the device after the scrape (connection, duration and errors of the collectors)
+	Status *DeviceStatus
+}
+
+// Failed returns whether the device could not be scraped or a collector failed or timed out
+func (r *ProbeResult) Failed() bool {
+	if !r.Status.Connected {
+		return true
+	}
+
+	return slices.ContainsFunc(r.Status.Collectors, func(c *CollectorStatus) bool {
+		return c.LastError != "" || c.TimedOut
+	})
+}
+
+// ProbeSummary is the JSON summary of a probe
+type ProbeSummary struct {
+	Target          string                  `json:"target"`
+	Connected       bool                    `json:"connected"`
+	ConnectError    string                  `json:"connect_error,omitempty"`
+	DurationSeconds float64                 `json:"duration_seconds"`
+	Series          int                     `json:"series"`
+	Failed          bool                    `json:"failed"`
+	Collectors      []ProbeCollectorSummary `json:"collectors"`
+}
+
+// ProbeCollectorSummary is the JSON summary of a collector run by a probe
+type ProbeCollectorSummary struct {
+	Feature             string  `json:"feature"`
+	Name                string  `json:"name"`
+	DurationSeconds     float64 `json:"duration_seconds"`
+	Error               string  `json:"error,omitempty"`
+	TimedOut            bool    `json:"timed_out,omitempty"`
+	SeriesLimitExceeded bool    `json:"series_limit_exceeded,omitempty"`
+}
+
+// Summary returns the summary of the probe. Collectors which did not run are omitted.
+func (r *ProbeResult) Summary() *ProbeSummary {
+	s := r.Status
+
+	summary := &ProbeSummary{
+		Target:          s.Target,
+		Connected:       s.Connected,
+		DurationSeconds: s.LastScrapeDuration.Seconds(),
+		Failed:          r.Failed(),
+		Collectors:      make([]ProbeCollectorSummary, 0, len(s.Collectors)),
+	}
+
+	if !s.Connected {
+		summary.ConnectError = s.LastConnectError
+	}
+
+	for _, mf := range r.Metrics {
+		summary.Series += len(mf.GetMetric())
+	}
+
+	for _, c := range s.Collectors {
+		if c.LastRun.IsZero() {
+			continue
+		}
+
+		summary.Collectors = append(summary.Collectors, ProbeCollectorSummary{
+			Feature:             c.Feature,
+			Name:                c.Name,
+			DurationSeconds:     c.LastDuration.Seconds(),
+			Error:               c.LastError,
+			TimedOut:            c.TimedOut,
+			SeriesLimitExceeded: c.SeriesLimitExceeded,
+		})
+	}
+
+	return summary
+}
+
+// WriteJSON writes the summary of the probe as indented JSON to w
+func (r *ProbeResult) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.Summary())
+}
+
+// WriteText writes the metrics in text exposition format to w and the errors of the scrape to errw
+func (r *ProbeResult) WriteText(w, errw io.Writer) error {
+	for _, mf := range r.Metrics {
+		if _, err := expfmt.MetricFamilyToText(w, mf); err != nil {
+			return err
+		}
+	}
+
+	s := r.Status
+	if !s.Connected && s.LastConnectError != "" {
+		fmt.Fprintf(errw, "%s: could not connect: %s\n", s.Target, s.LastConnectError)
+	}
+
+	for _, c := range s.Collectors {
+		if c.LastError != "" {
+			fmt.Fprintf(errw, "%s: %s: %s\n", s.Target, c.Feature, c.LastError)
+		}
+
+		if c.TimedOut {
+			fmt.Fprintf(errw, "%s: %s: timed out\n", s.Target, c.Feature)
+		}
+	}
+
+	return nil
+}
+
+// Probe scrapes the device of a target once, e.g. to test the config of a
+// device. Collectors which have not finished when ctx is done are abandoned.
+func (e *Exporter) Probe(ctx context.Context, target string) (*ProbeResult, error) {
+	cfg, devs := e.snapshot()
+
+	d, err := e.deviceForTarget(target, devs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(e.newJunosCollector(ctx, cfg, []*connector.Device{d}, ""))
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	connected := slices.ContainsFunc(e.connManager.Devices(), func(cd *connector.Device) bool {
+		return cd.Host == d.Host
+	})
+
+	return &ProbeResult{
+		Metrics: mfs,
+		Status:  e.deviceStatus(cfg, d.Host, connected),
+	}, nil
+}
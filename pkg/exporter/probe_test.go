@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+var probeTestRegistrations = []collector.Registration{
+	{
+		Key:     "a",
+		Feature: "a",
+		New: func(p *collector.Params) collector.RPCCollector {
+			return &slowCollector{}
+		},
+	},
+	{
+		Key:     "b",
+		Feature: "b",
+		New: func(p *collector.Params) collector.RPCCollector {
+			return &stateCollector{}
+		},
+	},
+}
+
+func TestProbe(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	}
+	c.Features.Set("a", true)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectors(probeTestRegistrations...))
+	require.NoError(t, err)
+
+	res, err := e.Probe(context.Background(), "router1")
+	require.NoError(t, err)
+
+	assert.True(t, res.Failed(), "device is unreachable")
+	assert.False(t, res.Status.Connected)
+	assert.Equal(t, "unreachable", res.Status.LastConnectError)
+	assert.False(t, res.Status.LastScrape.IsZero())
+
+	names := make([]string, 0)
+	for _, mf := range res.Metrics {
+		names = append(names, mf.GetName())
+	}
+	assert.Contains(t, names, "junos_up")
+
+	_, err = e.Probe(context.Background(), "router2")
+	assert.ErrorIs(t, err, ErrUnknownTarget)
+}
+
+func TestWithFeatures(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	}
+	c.Features.Set("a", true)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectors(probeTestRegistrations...))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, e.enabledFeatures(c, "router1"))
+
+	e, err = New(c, WithConnectionManager(&fakeConnectionManager{}), WithCollectors(probeTestRegistrations...), WithFeatures("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, e.enabledFeatures(c, "router1"), "features of the config are ignored")
+}
+
+func TestProbeResultOutput(t *testing.T) {
+	res := &ProbeResult{
+		Status: &DeviceStatus{
+			Target:             "router1",
+			Connected:          true,
+			LastScrapeDuration: 2 * time.Second,
+			Collectors: []*CollectorStatus{
+				{Feature: "bgp", Name: "BGP", LastRun: time.Now(), LastDuration: time.Second, LastError: "parse error"},
+				{Feature: "ospf", Name: "OSPF", LastRun: time.Now(), TimedOut: true},
+				{Feature: "isis"},
+			},
+		},
+	}
+
+	s := res.Summary()
+	assert.Equal(t, "router1", s.Target)
+	assert.True(t, s.Failed)
+	assert.Equal(t, 2.0, s.DurationSeconds)
+	if assert.Len(t, s.Collectors, 2, "collectors which did not run are omitted") {
+		assert.Equal(t, "parse error", s.Collectors[0].Error)
+		assert.True(t, s.Collectors[1].TimedOut)
+	}
+
+	var out, errs bytes.Buffer
+	require.NoError(t, res.WriteText(&out, &errs))
+	assert.Equal(t, "router1: bgp: parse error\nrouter1: ospf: timed out\n", errs.String())
+
+	out.Reset()
+	require.NoError(t, res.WriteJSON(&out))
+	assert.Contains(t, out.String(), `"timed_out": true`)
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// seriesLimiter enforces the series limits of a device during one scrape.
+// The series of all passes of the device count against the same limits.
+type seriesLimiter struct {
+	limits *config.SeriesLimitsConfig
+
+	mu       sync.Mutex
+	total    int
+	features map[string]int
+}
+
+// newSeriesLimiter returns the limiter of a device (nil if no limit is set)
+func newSeriesLimiter(limits *config.SeriesLimitsConfig) *seriesLimiter {
+	if !limits.Enabled() {
+		return nil
+	}
+
+	return &seriesLimiter{
+		limits:   limits,
+		features: make(map[string]int),
+	}
+}
+
+// forCollector returns the limit of the next run of the collector of a feature
+func (s *seriesLimiter) forCollector(feature string) *collectorSeriesLimit {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remaining := -1
+	if l := s.limits.LimitForCollector(feature); l > 0 {
+		remaining = l - s.features[feature]
+	}
+
+	if l := s.limits.MaxSeries; l > 0 && (remaining < 0 || l-s.total < remaining) {
+		remaining = l - s.total
+	}
+
+	return &collectorSeriesLimit{
+		limiter:  s,
+		feature:  feature,
+		max:      remaining,
+		truncate: s.limits.Truncate(),
+	}
+}
+
+func (s *seriesLimiter) add(feature string, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.total += count
+	s.features[feature] += count
+}
+
+// collectorSeriesLimit applies the limit to the metrics of one collector run (max < 0: no limit).
+// In truncate mode metrics are forwarded up to the limit, otherwise they are
+// buffered and forwarded only if the collector stays within the limit.
+type collectorSeriesLimit struct {
+	limiter  *seriesLimiter
+	feature  string
+	max      int
+	truncate bool
+
+	count    int
+	buffer   []prometheus.Metric
+	exceeded bool
+}
+
+func (l *collectorSeriesLimit) add(m prometheus.Metric, ch chan<- prometheus.Metric) {
+	l.count++
+
+	if l.max >= 0 && l.count > l.max {
+		l.exceeded = true
+		l.buffer = nil
+		return
+	}
+
+	if l.truncate {
+		ch <- m
+		return
+	}
+
+	if !l.exceeded {
+		l.buffer = append(l.buffer, m)
+	}
+}
+
+func (l *collectorSeriesLimit) actionDescription() string {
+	if l.truncate {
+		return "series truncated"
+	}
+
+	return "series dropped"
+}
+
+// flush forwards the buffered metrics and counts the series forwarded by the run against the limits of the device
+func (l *collectorSeriesLimit) flush(ch chan<- prometheus.Metric) {
+	forwarded := l.count
+	if l.exceeded {
+		forwarded = 0
+		if l.truncate {
+			forwarded = max(l.max, 0)
+		}
+	}
+
+	for _, m := range l.buffer {
+		ch <- m
+	}
+	l.buffer = nil
+
+	l.limiter.add(l.feature, forwarded)
+}
+
+// runCollectorWithLimit runs a collector like runCollector and applies the series limit to its metrics (no limit if limit is nil)
+func runCollectorWithLimit(ctx context.Context, col collector.RPCCollector, cl collector.Client, ch chan<- prometheus.Metric, labelValues []string, limit *collectorSeriesLimit, abandoned *abandonedCollectors) error {
+	if limit == nil {
+		return runCollector(ctx, col, cl, ch, labelValues, abandoned)
+	}
+
+	metrics := make(chan prometheus.Metric)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- runCollector(ctx, col, cl, metrics, labelValues, abandoned)
+		close(metrics)
+	}()
+
+	for m := range metrics {
+		limit.add(m, ch)
+	}
+	limit.flush(ch)
+
+	return <-done
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+type countingCollector struct {
+	count int
+}
+
+func (*countingCollector) Name() string {
+	return "Counting"
+}
+
+func (*countingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- testDesc
+}
+
+func (c *countingCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	for i := 0; i < c.count; i++ {
+		ch <- prometheus.MustNewConstMetric(testDesc, prometheus.GaugeValue, float64(i), labelValues...)
+	}
+
+	return nil
+}
+
+func runWithLimit(t *testing.T, limit *collectorSeriesLimit, count int) int {
+	ch := make(chan prometheus.Metric, count)
+
+	err := runCollectorWithLimit(context.Background(), &countingCollector{count: count}, nil, ch, []string{"router1"}, limit, nil)
+	assert.NoError(t, err)
+
+	return len(ch)
+}
+
+func TestSeriesLimiterDisabled(t *testing.T) {
+	assert.Nil(t, newSeriesLimiter(&config.SeriesLimitsConfig{Action: config.SeriesLimitTruncate}))
+
+	var l *seriesLimiter
+	assert.Nil(t, l.forCollector("mac"))
+	assert.Equal(t, 5, runWithLimit(t, nil, 5))
+}
+
+func TestSeriesLimiterCollectorLimit(t *testing.T) {
+	l := newSeriesLimiter(&config.SeriesLimitsConfig{
+		Collectors: map[string]int{"mac": 3},
+	})
+
+	limit := l.forCollector("bgp")
+	assert.Equal(t, 5, runWithLimit(t, limit, 5), "no limit for bgp")
+	assert.False(t, limit.exceeded)
+
+	limit = l.forCollector("mac")
+	assert.Equal(t, 0, runWithLimit(t, limit, 5), "series of mac dropped")
+	assert.True(t, limit.exceeded)
+
+	limit = l.forCollector("mac")
+	assert.Equal(t, 3, runWithLimit(t, limit, 3), "dropped series do not count")
+	assert.False(t, limit.exceeded)
+
+	limit = l.forCollector("mac")
+	assert.Equal(t, 0, runWithLimit(t, limit, 1), "limit reached by previous pass")
+	assert.True(t, limit.exceeded)
+}
+
+func TestSeriesLimiterTargetLimit(t *testing.T) {
+	l := newSeriesLimiter(&config.SeriesLimitsConfig{
+		MaxSeries:  10,
+		Action:     config.SeriesLimitTruncate,
+		Collectors: map[string]int{"subscriber": 8},
+	})
+
+	limit := l.forCollector("subscriber")
+	assert.Equal(t, 8, runWithLimit(t, limit, 20), "truncated to collector limit")
+	assert.True(t, limit.exceeded)
+
+	limit = l.forCollector("bgp")
+	assert.Equal(t, 2, runWithLimit(t, limit, 5), "truncated to remaining series of the target")
+	assert.True(t, limit.exceeded)
+
+	limit = l.forCollector("bgp")
+	assert.Equal(t, 0, runWithLimit(t, limit, 5))
+	assert.True(t, limit.exceeded)
+
+	limit = l.forCollector("interfaces")
+	assert.Equal(t, 0, runWithLimit(t, limit, 0))
+	assert.False(t, limit.exceeded, "collector without series")
+}
@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+)
+
+// Status is the state of the devices of the config as seen by the exporter
+type Status struct {
+	Devices  []*DeviceStatus
+	Patterns []*PatternStatus
+}
+
+// PatternStatus is the state of the targets matching a host pattern of the config
+type PatternStatus struct {
+	Pattern string
+
+	// Targets are the targets matching the pattern which were scraped in the last 24 hours
+	Targets []*DeviceStatus
+}
+
+// DeviceStatus is the state of a device
+type DeviceStatus struct {
+	Target string
+
+	// DiscoveredName is the hostname discovered on the device (discover_name)
+	DiscoveredName string
+
+	// Connected is whether there is an open SSH connection to the device
+	Connected bool
+
+	// LastConnectError is the error of the last failed connection attempt (empty if no attempt failed)
+	LastConnectError string
+
+	// LastConnectErrorTime is the time of the last failed connection attempt
+	LastConnectErrorTime time.Time
+
+	// LastScrape is the time the last scrape of the device finished (zero if it was not scraped yet)
+	LastScrape time.Time
+
+	// LastScrapeDuration is the duration of the last scrape
+	LastScrapeDuration time.Duration
+
+	// Collectors are the collectors enabled for the device
+	Collectors []*CollectorStatus
+}
+
+// CollectorStatus is the state of a collector of a device. For devices with
+// logical systems or routing instances the state is the one of the last pass.
+type CollectorStatus struct {
+	Feature string
+
+	// Name is the name of the collector used in the collector label (empty if it did not run yet)
+	Name string
+
+	// LastRun is the time the last run of the collector finished (zero if it did not run yet)
+	LastRun time.Time
+
+	// LastDuration is the duration of the last run
+	LastDuration time.Duration
+
+	// LastError is the error of the last run (empty on success)
+	LastError string
+
+	// TimedOut is whether the last run did not finish before the scrape deadline
+	TimedOut bool
+
+	// SeriesLimitExceeded is whether a series limit was exceeded by the last run
+	SeriesLimitExceeded bool
+}
+
+// patternTargetTTL is the time after which the status of a target matching a
+// host pattern is dropped if the target was not scraped anymore
+const patternTargetTTL = 24 * time.Hour
+
+// statusExpiryInterval is the minimal interval between two runs of expire
+const statusExpiryInterval = time.Hour
+
+// statusStore records the results of connection attempts and scrapes (key: host of the device)
+type statusStore struct {
+	mu         sync.Mutex
+	devices    map[string]*DeviceStatus
+	configured map[string]bool
+	lastExpiry time.Time
+}
+
+func newStatusStore() *statusStore {
+	return &statusStore{
+		devices: make(map[string]*DeviceStatus),
+	}
+}
+
+// device returns the status of a device, the lock has to be held by the caller
+func (s *statusStore) device(host string) *DeviceStatus {
+	d, found := s.devices[host]
+	if !found {
+		d = &DeviceStatus{Target: host}
+		s.devices[host] = d
+	}
+
+	return d
+}
+
+func (s *statusStore) connectFailed(host string, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.device(host)
+	d.LastConnectError = err.Error()
+	d.LastConnectErrorTime = time.Now()
+}
+
+func (s *statusStore) scraped(host string, duration time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	d := s.device(host)
+	d.LastScrape = now
+	d.LastScrapeDuration = duration
+
+	if now.Sub(s.lastExpiry) > statusExpiryInterval {
+		s.expire(now)
+	}
+}
+
+// reset is called when a config is loaded. The status of hosts which are
+// neither configured nor matching a host pattern (matches) is dropped.
+func (s *statusStore) reset(configured []*connector.Device, matches func(host string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.configured = make(map[string]bool, len(configured))
+	for _, d := range configured {
+		s.configured[d.Host] = true
+	}
+
+	for h := range s.devices {
+		if !s.configured[h] && !matches(h) {
+			delete(s.devices, h)
+		}
+	}
+
+	s.expire(time.Now())
+}
+
+// expire drops the status of targets matching a host pattern which were not
+// scraped for patternTargetTTL, the lock has to be held by the caller
+func (s *statusStore) expire(now time.Time) {
+	s.lastExpiry = now
+
+	for h, d := range s.devices {
+		if s.configured[h] {
+			continue
+		}
+
+		if now.Sub(d.LastScrape) > patternTargetTTL && now.Sub(d.LastConnectErrorTime) > patternTargetTTL {
+			delete(s.devices, h)
+		}
+	}
+}
+
+func (s *statusStore) collected(host string, c CollectorStatus) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.device(host)
+	c.LastRun = time.Now()
+
+	i := slices.IndexFunc(d.Collectors, func(cs *CollectorStatus) bool {
+		return cs.Feature == c.Feature
+	})
+	if i < 0 {
+		d.Collectors = append(d.Collectors, &c)
+		return
+	}
+
+	d.Collectors[i] = &c
+}
+
+// snapshot returns a copy of the status of a device with the collectors of features
+func (s *statusStore) snapshot(host string, features []string) *DeviceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &DeviceStatus{Target: host}
+	d, found := s.devices[host]
+	if found {
+		*res = *d
+	}
+
+	res.Collectors = make([]*CollectorStatus, 0, len(features))
+	for _, f := range features {
+		c := &CollectorStatus{Feature: f}
+		if found {
+			if i := slices.IndexFunc(d.Collectors, func(cs *CollectorStatus) bool { return cs.Feature == f }); i >= 0 {
+				*c = *d.Collectors[i]
+			}
+		}
+
+		res.Collectors = append(res.Collectors, c)
+	}
+
+	return res
+}
+
+// hosts returns the hosts with a recorded status
+func (s *statusStore) hosts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hosts := make([]string, 0, len(s.devices))
+	for h := range s.devices {
+		hosts = append(hosts, h)
+	}
+
+	slices.Sort(hosts)
+	return hosts
+}
+
+// Status returns the state of the devices and host patterns of the config:
+// connection state and the results of the last connection attempt and scrape.
+func (e *Exporter) Status() *Status {
+	cfg, devs := e.snapshot()
+
+	connected := make(map[string]bool)
+	for _, d := range e.connManager.Devices() {
+		connected[d.Host] = true
+	}
+
+	res := &Status{
+		Devices:  make([]*DeviceStatus, 0, len(devs)),
+		Patterns: make([]*PatternStatus, 0),
+	}
+
+	configured := make(map[string]bool)
+	for _, d := range devs {
+		configured[d.Host] = true
+		res.Devices = append(res.Devices, e.deviceStatus(cfg, d.Host, connected[d.Host]))
+	}
+
+	hosts := e.status.hosts()
+	for _, dc := range cfg.Devices {
+		if !dc.IsHostPattern {
+			continue
+		}
+
+		p := &PatternStatus{Pattern: dc.Host, Targets: make([]*DeviceStatus, 0)}
+		for _, h := range hosts {
+			if !configured[h] && dc.HostPattern != nil && dc.HostPattern.MatchString(h) {
+				p.Targets = append(p.Targets, e.deviceStatus(cfg, h, connected[h]))
+			}
+		}
+
+		res.Patterns = append(res.Patterns, p)
+	}
+
+	return res
+}
+
+// matchesHostPattern returns whether a host matches a host pattern of the config
+func matchesHostPattern(cfg *config.Config, host string) bool {
+	return slices.ContainsFunc(cfg.Devices, func(dc *config.DeviceConfig) bool {
+		return dc.IsHostPattern && dc.HostPattern != nil && dc.HostPattern.MatchString(host)
+	})
+}
+
+func (e *Exporter) deviceStatus(cfg *config.Config, host string, connected bool) *DeviceStatus {
+	s := e.status.snapshot(host, e.enabledFeatures(cfg, host))
+	s.Connected = connected
+
+	if name, found := e.discoveredNames.Load(host); found {
+		s.DiscoveredName = name.(string)
+	}
+
+	return s
+}
+
+// enabledFeatures returns the features of the collectors enabled for a device
+func (e *Exporter) enabledFeatures(cfg *config.Config, host string) []string {
+	f := cfg.FeaturesForDevice(host)
+
+	features := make([]string, 0)
+	for _, r := range e.collectorRegistrations() {
+		if r.New != nil && e.collectorEnabled(f, r) {
+			features = append(features, r.Feature)
+		}
+	}
+
+	slices.Sort(features)
+	return slices.Compact(features)
+}
+
+// ScrapeTarget scrapes the device of a target like a request with the target
+// parameter does. The results are recorded in the status of the device.
+func (e *Exporter) ScrapeTarget(ctx context.Context, target string) ([]*dto.MetricFamily, error) {
+	cfg, devs := e.snapshot()
+
+	d, err := e.deviceForTarget(target, devs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+
+	return e.gatherer(ctx, cfg, []*connector.Device{d}, "").Gather()
+}
+
+// Reconnect closes the connection to the device of a target and connects to it again
+func (e *Exporter) Reconnect(target string) error {
+	cfg, devs := e.snapshot()
+
+	d, err := e.deviceForTarget(target, devs, cfg)
+	if err != nil {
+		return err
+	}
+
+	if d == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+
+	e.connManager.Close(d.Host)
+
+	_, err = e.clientForDevice(d, cfg)
+	return err
+}
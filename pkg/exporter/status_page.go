@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// statusPage is the data of the status page template
+type statusPage struct {
+	Version string
+	Message string
+	Failed  bool
+	Actions bool
+	Status  *Status
+	Configs map[string]string
+	Now     time.Time
+}
+
+var statusTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"age": func(now, t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+
+		return now.Sub(t).Round(time.Second).String() + " ago"
+	},
+	"duration": func(d time.Duration) string {
+		return d.Round(time.Millisecond).String()
+	},
+	"deviceData": func(p *statusPage, d *DeviceStatus) any {
+		return struct {
+			Page   *statusPage
+			Device *DeviceStatus
+		}{p, d}
+	},
+}).Parse(`<html>
+<head>
+<title>JunOS Exporter - Status</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; vertical-align: top; }
+.ok { color: green; }
+.error { color: #c00; }
+form { display: inline; }
+</style>
+</head>
+<body>
+<h1>JunOS Exporter (Version {{.Version}})</h1>
+<p><a href="/">Home</a></p>
+{{if .Message}}<p class="{{if .Failed}}error{{else}}ok{{end}}">{{.Message}}</p>{{end}}
+
+<h2>Devices</h2>
+{{range .Status.Devices}}{{template "device" (deviceData $ .)}}{{else}}<p>No devices configured.</p>{{end}}
+
+{{if .Status.Patterns}}<h2>Host patterns</h2>
+{{range .Status.Patterns}}<h3>{{.Pattern}}</h3>
+{{range .Targets}}{{template "device" (deviceData $ .)}}{{else}}<p>No targets matching the pattern were scraped yet.</p>{{end}}
+{{end}}{{end}}
+</body>
+</html>
+
+{{define "device"}}{{$now := .Page.Now}}{{with .Device}}
+<h3 id="{{.Target}}">{{.Target}}{{if .DiscoveredName}} ({{.DiscoveredName}}){{end}}</h3>
+<table>
+<tr><th>Connection</th><td>{{if .Connected}}<span class="ok">connected</span>{{else}}not connected{{end}}</td></tr>
+<tr><th>Last connect error</th><td>{{if .LastConnectError}}<span class="error">{{.LastConnectError}}</span> ({{age $now .LastConnectErrorTime}}){{else}}-{{end}}</td></tr>
+<tr><th>Last scrape</th><td>{{age $now .LastScrape}}{{if not .LastScrape.IsZero}} (took {{duration .LastScrapeDuration}}){{end}}</td></tr>
+</table>
+{{if $.Page.Actions}}<form method="post" action="/status/scrape"><input type="hidden" name="target" value="{{.Target}}"><button type="submit">Test scrape</button></form>
+<form method="post" action="/status/reconnect"><input type="hidden" name="target" value="{{.Target}}"><button type="submit">Reconnect</button></form>
+{{end}}<table>
+<tr><th>Collector</th><th>Name</th><th>Last run</th><th>Duration</th><th>Last error</th></tr>
+{{range .Collectors}}<tr>
+<td>{{.Feature}}</td>
+<td>{{.Name}}</td>
+<td>{{age $now .LastRun}}</td>
+<td>{{if not .LastRun.IsZero}}{{duration .LastDuration}}{{end}}</td>
+<td>{{if .LastError}}<span class="error">{{.LastError}}</span>{{end}}{{if .TimedOut}}<span class="error">timed out</span> {{end}}{{if .SeriesLimitExceeded}}<span class="error">series limit exceeded</span>{{end}}</td>
+</tr>{{end}}
+</table>
+<details><summary>Effective config</summary><pre>{{index $.Page.Configs .Target}}</pre></details>
+{{end}}{{end}}`))
+
+// StatusHandler returns the handler of the status page (/status): the state of the
+// devices of the config with connection state, last errors and the collectors.
+// If actions is set, the buttons scraping a target (POST /status/scrape) and
+// reconnecting to it (POST /status/reconnect) are served as well. They should
+// only be enabled with authentication. As the page shows the effective config of
+// the devices, it should be served with authentication in any case. version is
+// shown in the header of the page.
+func (e *Exporter) StatusHandler(version string, actions bool) http.Handler {
+	h := &statusHandler{exp: e, version: version, actions: actions}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", h.handleStatus)
+	if actions {
+		mux.HandleFunc("/status/scrape", h.handleScrape)
+		mux.HandleFunc("/status/reconnect", h.handleReconnect)
+	}
+
+	return mux
+}
+
+type statusHandler struct {
+	exp     *Exporter
+	version string
+	actions bool
+}
+
+func (h *statusHandler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	h.writePage(w, "", false)
+}
+
+// handleScrape scrapes a target and shows the status page with the result
+func (h *statusHandler) handleScrape(w http.ResponseWriter, r *http.Request) {
+	target, ok := statusActionTarget(w, r)
+	if !ok {
+		return
+	}
+
+	t := time.Now()
+	mfs, err := h.exp.ScrapeTarget(r.Context(), target)
+	if err != nil {
+		h.writePage(w, fmt.Sprintf("Scrape of %s failed: %v", target, err), true)
+		return
+	}
+
+	series := 0
+	for _, mf := range mfs {
+		series += len(mf.GetMetric())
+	}
+
+	h.writePage(w, fmt.Sprintf("Scraped %s in %s: %d series", target, time.Since(t).Round(time.Millisecond), series), false)
+}
+
+// handleReconnect closes the connection to a target, connects again and shows the status page with the result
+func (h *statusHandler) handleReconnect(w http.ResponseWriter, r *http.Request) {
+	target, ok := statusActionTarget(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.exp.Reconnect(target)
+	if err != nil {
+		h.writePage(w, fmt.Sprintf("Reconnect to %s failed: %v", target, err), true)
+		return
+	}
+
+	h.writePage(w, fmt.Sprintf("Reconnected to %s", target), false)
+}
+
+// statusActionTarget returns the target of an action of the status page. Actions
+// have to be POST requests from the same origin, so other sites can not trigger
+// them in the browser of a logged in user.
+func statusActionTarget(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST method expected", http.StatusMethodNotAllowed)
+		return "", false
+	}
+
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+		return "", false
+	}
+
+	target := r.FormValue("target")
+	if target == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return "", false
+	}
+
+	return target, true
+}
+
+func sameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin" || site == "none"
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+func (h *statusHandler) writePage(w http.ResponseWriter, message string, failed bool) {
+	p := &statusPage{
+		Version: h.version,
+		Message: message,
+		Failed:  failed,
+		Actions: h.actions,
+		Status:  h.exp.Status(),
+		Configs: make(map[string]string),
+		Now:     time.Now(),
+	}
+
+	targets := make([]string, 0)
+	for _, d := range p.Status.Devices {
+		targets = append(targets, d.Target)
+	}
+	for _, pattern := range p.Status.Patterns {
+		for _, d := range pattern.Targets {
+			targets = append(targets, d.Target)
+		}
+	}
+
+	for _, t := range targets {
+		b, err := h.exp.EffectiveConfigYAML(t)
+		if err != nil {
+			p.Configs[t] = err.Error()
+			continue
+		}
+
+		p.Configs[t] = string(b)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusTemplate.Execute(w, p); err != nil {
+		log.Errorf("Could not render status page: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+func newStatusPageTestExporter(t *testing.T) *Exporter {
+	c, err := config.Load(strings.NewReader(`
+devices:
+  - host: router1
+    password: secret
+    features:
+      bfd: true
+  - host: 'edge\d+'
+    host_pattern: true
+    password: secret
+`), false, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}))
+	assert.NoError(t, err)
+
+	return e
+}
+
+func TestStatusHandler(t *testing.T) {
+	e := newStatusPageTestExporter(t)
+
+	w := httptest.NewRecorder()
+	e.StatusHandler("1.0", false).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "JunOS Exporter (Version 1.0)")
+	assert.Contains(t, body, "<h3 id=\"router1\">router1</h3>")
+	assert.Contains(t, body, "<td>bfd</td>")
+	assert.Contains(t, body, "edge\\d&#43;")
+	assert.Contains(t, body, "password: &lt;secret&gt;", "effective config with redacted secrets")
+	assert.NotContains(t, body, "password: secret")
+	assert.NotContains(t, body, "/status/reconnect", "actions are disabled")
+
+	form := url.Values{"target": {"router1"}}.Encode()
+
+	r := httptest.NewRequest(http.MethodPost, "/status/reconnect", strings.NewReader(form))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	e.StatusHandler("1.0", false).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code, "actions are disabled")
+
+	h := e.StatusHandler("1.0", true)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Contains(t, w.Body.String(), "action=\"/status/reconnect\"")
+
+	r = httptest.NewRequest(http.MethodPost, "/status/reconnect", strings.NewReader(form))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Reconnect to router1 failed")
+	assert.Contains(t, w.Body.String(), "Last connect error</th><td><span class=\"error\">unreachable")
+}
+
+func TestStatusActionsRejectForeignRequests(t *testing.T) {
+	h := newStatusPageTestExporter(t).StatusHandler("1.0", true)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		status  int
+	}{
+		{
+			name:   "GET",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:    "cross-site fetch metadata",
+			method:  http.MethodPost,
+			headers: map[string]string{"Sec-Fetch-Site": "cross-site"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "foreign origin",
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "https://evil.example.com"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:   "missing target",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, "/status/scrape", nil)
+			for k, v := range test.headers {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, test.status, w.Code)
+		})
+	}
+}
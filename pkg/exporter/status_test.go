@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+)
+
+func TestStatus(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1", Features: &config.FeatureConfig{BGP: true, Interfaces: true}},
+			{Host: "router2"},
+		},
+	}
+	c.Features.Interfaces = true
+
+	cm := &fakeConnectionManager{connected: []*connector.Device{{Host: "router2"}}}
+	e, err := New(c, WithConnectionManager(cm))
+	assert.NoError(t, err)
+
+	s := e.Status()
+	if assert.Len(t, s.Devices, 2) {
+		assert.Equal(t, "router1", s.Devices[0].Target)
+		assert.True(t, s.Devices[0].LastScrape.IsZero(), "not scraped yet")
+		assert.Empty(t, s.Devices[0].LastConnectError)
+		assert.False(t, s.Devices[0].Connected)
+		assert.True(t, s.Devices[1].Connected)
+
+		features := make([]string, 0)
+		for _, col := range s.Devices[0].Collectors {
+			features = append(features, col.Feature)
+			assert.True(t, col.LastRun.IsZero())
+		}
+		assert.Contains(t, features, "bgp")
+		assert.Contains(t, features, "interfaces")
+	}
+	assert.Empty(t, s.Patterns)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics?target=router1", nil))
+
+	s = e.Status()
+	assert.Equal(t, "unreachable", s.Devices[0].LastConnectError)
+	assert.False(t, s.Devices[0].LastConnectErrorTime.IsZero())
+	assert.False(t, s.Devices[0].LastScrape.IsZero(), "scraped")
+	assert.True(t, s.Devices[1].LastScrape.IsZero(), "not scraped")
+}
+
+func TestStatusPatterns(t *testing.T) {
+	c, err := config.Load(strings.NewReader(`
+password: secret
+devices:
+  - host: router1
+  - host: 'edge\d+'
+    host_pattern: true
+`), true, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}))
+	assert.NoError(t, err)
+
+	_, err = e.ScrapeTarget(context.Background(), "edge1")
+	assert.NoError(t, err)
+
+	_, err = e.ScrapeTarget(context.Background(), "core1")
+	assert.ErrorIs(t, err, ErrUnknownTarget)
+
+	s := e.Status()
+	assert.Len(t, s.Devices, 1)
+	if assert.Len(t, s.Patterns, 1) {
+		assert.Equal(t, `edge\d+`, s.Patterns[0].Pattern)
+		if assert.Len(t, s.Patterns[0].Targets, 1) {
+			assert.Equal(t, "edge1", s.Patterns[0].Targets[0].Target)
+			assert.Equal(t, "unreachable", s.Patterns[0].Targets[0].LastConnectError)
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	c := &config.Config{
+		Password: "secret",
+		Devices: []*config.DeviceConfig{
+			{Host: "router1"},
+		},
+	}
+
+	cm := &fakeConnectionManager{}
+	e, err := New(c, WithConnectionManager(cm))
+	assert.NoError(t, err)
+
+	assert.EqualError(t, e.Reconnect("router1"), "unreachable")
+	assert.Equal(t, []string{"router1"}, cm.closed)
+	assert.Equal(t, "unreachable", e.Status().Devices[0].LastConnectError)
+
+	assert.ErrorIs(t, e.Reconnect("router2"), ErrUnknownTarget)
+}
+
+func TestStatusPrunedOnReload(t *testing.T) {
+	c, err := config.Load(strings.NewReader(`
+password: secret
+devices:
+  - host: router1
+  - host: router2
+  - host: 'edge\d+'
+    host_pattern: true
+`), true, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := New(c, WithConnectionManager(&fakeConnectionManager{}))
+	assert.NoError(t, err)
+
+	for _, target := range []string{"router1", "router2", "edge1"} {
+		_, err = e.ScrapeTarget(context.Background(), target)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"edge1", "router1", "router2"}, e.status.hosts())
+
+	c, err = config.Load(strings.NewReader(`
+password: secret
+devices:
+  - host: router1
+`), true, collector.DefaultRegistry)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Reload(c))
+
+	assert.Equal(t, []string{"router1"}, e.status.hosts())
+}
+
+func TestStatusStoreExpire(t *testing.T) {
+	s := newStatusStore()
+	s.reset([]*connector.Device{{Host: "router1"}}, func(string) bool { return true })
+
+	s.scraped("router1", time.Second)
+	s.scraped("edge1", time.Second)
+	s.scraped("edge2", time.Second)
+	s.connectFailed("edge3", errors.New("unreachable"))
+
+	s.mu.Lock()
+	s.devices["router1"].LastScrape = time.Now().Add(-2 * patternTargetTTL)
+	s.devices["edge1"].LastScrape = time.Now().Add(-2 * patternTargetTTL)
+	s.expire(time.Now())
+	s.mu.Unlock()
+
+	assert.Equal(t, []string{"edge2", "edge3", "router1"}, s.hosts(), "configured hosts do not expire")
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+var tracer = otel.GetTracerProvider().Tracer(
+	"github.com/czerwonk/junos_exporter",
+	trace.WithSchemaURL(semconv.SchemaURL),
+)
+
+type clientTracingAdapter struct {
+	cl  *rpc.Client
+	ctx context.Context
+}
+
+// RunCommandAndParse implements RunCommandAndParse of the collector.Client interface
+func (cta *clientTracingAdapter) RunCommandAndParse(cmd string, obj any) error {
+	return cta.cl.RunCommandAndParseContext(cta.ctx, cmd, obj)
+}
+
+// RunCommandAndParseWithParser implements RunCommandAndParseWithParser of the collector.Client interface
+func (cta *clientTracingAdapter) RunCommandAndParseWithParser(cmd string, parser rpc.Parser) error {
+	_, span := tracer.Start(cta.ctx, "RunCommandAndParseWithParser", trace.WithAttributes(
+		attribute.String("command", cmd),
+	))
+	defer span.End()
+
+	err := cta.cl.RunCommandAndParseWithParserContext(cta.ctx, cmd, parser)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// IsSatelliteEnabled implements IsSatelliteEnabled of the collector.Client interface
+func (cta *clientTracingAdapter) IsSatelliteEnabled() bool {
+	return cta.cl.IsSatelliteEnabled()
+}
+
+func (cta *clientTracingAdapter) IsScrapingLicenseEnabled() bool {
+	return cta.cl.IsScrapingLicenseEnabled()
+}
+
+// Device implements Device of the collector.Client interface
+func (cta *clientTracingAdapter) Device() *connector.Device {
+	return cta.cl.Device()
+}
+
+// Context implements Context of the collector.Client interface
+func (cta *clientTracingAdapter) Context() context.Context {
+	return cta.ctx
+}
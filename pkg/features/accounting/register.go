@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package accounting
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "accounting",
+		Feature: "accounting",
+		Flag:    "accounting.enabled",
+		Help:    "Scrape accounting flow metrics",
+		Commands: []string{
+			"show services accounting flow inline-jflow",
+			"show services accounting errors inline-jflow fpc-slot *",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -31,12 +31,18 @@ type alarmCollector struct {
 	filter *regexp.Regexp
 }
 
+// Options are the options of the collector
+type Options struct {
+	// Filter is a regex matching alarms to ignore
+	Filter string `yaml:"filter,omitempty"`
+}
+
 // NewCollector creates a new collector
-func NewCollector(alarmsFilter string) collector.RPCCollector {
+func NewCollector(opts Options) collector.RPCCollector {
 	c := new(alarmCollector)
 
-	if len(alarmsFilter) > 0 {
-		c.filter = regexp.MustCompile(alarmsFilter)
+	if len(opts.Filter) > 0 {
+		c.filter = regexp.MustCompile(opts.Filter)
 	}
 
 	return c
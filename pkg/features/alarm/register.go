@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package alarm
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "alarm",
+		Feature:        "alarm",
+		Flag:           "alarm.enabled",
+		Help:           "Scrape Alarm metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show system alarms",
+			"show chassis alarms",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(collector.OptionsOf[Options](p))
+		},
+	})
+}
@@ -7,29 +7,30 @@ import (
 )
 
 type singleEngineResult struct {
-	XMLName xml.Name `xml:"rpc-reply"`
+	XMLName     xml.Name         `xml:"rpc-reply"`
 	Information alarmInformation `xml:"alarm-information"`
 }
 
 type multiEngineResult struct {
-	XMLName xml.Name `xml:"rpc-reply"`
+	XMLName     xml.Name `xml:"rpc-reply"`
 	Information struct {
 		RoutingEngines []routingEngine `xml:"multi-routing-engine-item"`
 	} `xml:"multi-routing-engine-results"`
 }
 
 type routingEngine struct {
-	Name    string    `xml:"re-name"`
+	Name      string           `xml:"re-name"`
 	AlarmInfo alarmInformation `xml:"alarm-information"`
 }
 
 type alarmInformation struct {
-	XMLName xml.Name `xml:"alarm-information"`
+	XMLName xml.Name  `xml:"alarm-information"`
 	Details []details `xml:"alarm-detail"`
 }
 
+// details is part of the schema of the API (/api/v1/targets/{target}/alarm)
 type details struct {
-	Class       string `xml:"alarm-class"`
-	Description string `xml:"alarm-description"`
-	Type        string `xml:"alarm-type"`
+	Class       string `xml:"alarm-class" json:"class"`
+	Description string `xml:"alarm-description" json:"description"`
+	Type        string `xml:"alarm-type" json:"type"`
 }
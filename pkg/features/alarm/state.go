@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: MIT
+
+package alarm
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+type state struct {
+	Alarms []details `json:"alarms"`
+}
+
+// State returns the active system and chassis alarms (including alarms ignored by the filter)
+func (c *alarmCollector) State(client collector.Client) (any, error) {
+	_, alarms, err := c.alarmCounter(client)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &state{Alarms: []details{}}
+	if alarms != nil {
+		s.Alarms = append(s.Alarms, *alarms...)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package arp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "arp",
+		Feature: "arp",
+		Flag:    "arps.enabled",
+		Help:    "Scrape ARP metrics",
+		Commands: []string{
+			"show arp no-resolve",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package bfd
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "bfd",
+		Feature: "bfd",
+		Flag:    "bfd.enabled",
+		Help:    "Scrape BFD metrics",
+		Commands: []string{
+			"show bfd session extensive",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -66,17 +66,19 @@ func newDescriptions(dynLabels dynamiclabels.Labels) *description {
 }
 
 type bgpCollector struct {
-	LogicalSystem string
-	descriptionRe *regexp.Regexp
+	LogicalSystem   string
+	RoutingInstance string
+	descriptionRe   *regexp.Regexp
 }
 
 type groupMap map[int64]group
 
 // NewCollector creates a new collector
-func NewCollector(logicalSystem string, descRe *regexp.Regexp) collector.RPCCollector {
+func NewCollector(logicalSystem, routingInstance string, descRe *regexp.Regexp) collector.RPCCollector {
 	return &bgpCollector{
-		LogicalSystem: logicalSystem,
-		descriptionRe: descRe,
+		LogicalSystem:   logicalSystem,
+		RoutingInstance: routingInstance,
+		descriptionRe:   descRe,
 	}
 }
 
@@ -112,30 +114,47 @@ func (c *bgpCollector) Collect(client collector.Client, ch chan<- prometheus.Met
 		return fmt.Errorf("could not retrieve BGP group information: %w", err)
 	}
 
+	peers, err := c.peers(client)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		c.collectForPeer(peer, groups, ch, labelValues)
+	}
+
+	return nil
+}
+
+func (c *bgpCollector) peers(client collector.Client) ([]peer, error) {
 	var x result
 	var cmd strings.Builder
 	cmd.WriteString("show bgp neighbor")
+	if c.RoutingInstance != "" {
+		cmd.WriteString(" instance ")
+		cmd.WriteString(c.RoutingInstance)
+	}
 	if c.LogicalSystem != "" {
 		cmd.WriteString(" logical-system ")
 		cmd.WriteString(c.LogicalSystem)
 	}
 
-	err = client.RunCommandAndParse(cmd.String(), &x)
+	err := client.RunCommandAndParse(cmd.String(), &x)
 	if err != nil {
-		return err
-	}
-
-	for _, peer := range x.Information.Peers {
-		c.collectForPeer(peer, groups, ch, labelValues)
+		return nil, err
 	}
 
-	return nil
+	return x.Information.Peers, nil
 }
 
 func (c *bgpCollector) collectGroups(client collector.Client) (groupMap, error) {
 	var x groupResult
 	var cmd strings.Builder
 	cmd.WriteString("show bgp group")
+	if c.RoutingInstance != "" {
+		cmd.WriteString(" instance ")
+		cmd.WriteString(c.RoutingInstance)
+	}
 	if c.LogicalSystem != "" {
 		cmd.WriteString(" logical-system ")
 		cmd.WriteString(c.LogicalSystem)
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+
+package bgp
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	masterPeer = `<bgp-peer>
+        <peer-address>192.0.2.1+179</peer-address>
+        <peer-as>64496</peer-as>
+        <peer-state>Established</peer-state>
+        <peer-cfg-rti>master</peer-cfg-rti>
+    </bgp-peer>`
+	customerPeer = `<bgp-peer>
+        <peer-address>198.51.100.1+179</peer-address>
+        <peer-as>64497</peer-as>
+        <peer-state>Established</peer-state>
+        <peer-cfg-rti>CUSTOMER-A</peer-cfg-rti>
+    </bgp-peer>`
+)
+
+func TestCollectRoutingInstances(t *testing.T) {
+	cl := &mockClient{
+		responses: map[string]string{
+			"show bgp group":                     `<rpc-reply><bgp-group-information/></rpc-reply>`,
+			"show bgp group instance master":     `<rpc-reply><bgp-group-information/></rpc-reply>`,
+			"show bgp group instance CUSTOMER-A": `<rpc-reply><bgp-group-information/></rpc-reply>`,
+
+			// the output without instance contains the peers of all routing instances
+			"show bgp neighbor":                     `<rpc-reply><bgp-information>` + masterPeer + customerPeer + `</bgp-information></rpc-reply>`,
+			"show bgp neighbor instance master":     `<rpc-reply><bgp-information>` + masterPeer + `</bgp-information></rpc-reply>`,
+			"show bgp neighbor instance CUSTOMER-A": `<rpc-reply><bgp-information>` + customerPeer + `</bgp-information></rpc-reply>`,
+		},
+	}
+
+	up := newDescriptions(nil).upDesc.String()
+	peers := make(map[string]int)
+	for _, ri := range []string{"master", "CUSTOMER-A"} {
+		ch := make(chan prometheus.Metric, 100)
+		err := NewCollector("", ri, nil).Collect(cl, ch, []string{"router1"})
+		require.NoError(t, err)
+		close(ch)
+
+		for m := range ch {
+			if m.Desc().String() != up {
+				continue
+			}
+
+			var pb dto.Metric
+			require.NoError(t, m.Write(&pb))
+			for _, lp := range pb.GetLabel() {
+				if lp.GetName() == "ip" {
+					peers[lp.GetValue()]++
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]int{"192.0.2.1": 1, "198.51.100.1": 1}, peers, "every peer is exported once")
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package bgp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:              "bgp",
+		Feature:          "bgp",
+		Flag:             "bgp.enabled",
+		Help:             "Scrape BGP metrics",
+		DefaultEnabled:   true,
+		LogicalSystems:   true,
+		RoutingInstances: true,
+		Commands: []string{
+			"show bgp group",
+			"show bgp group instance *",
+			"show bgp group logical-system *",
+			"show bgp neighbor",
+			"show bgp neighbor instance *",
+			"show bgp neighbor logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, p.RoutingInstance, p.InterfaceDescriptionRegex)
+		},
+	})
+}
@@ -2,6 +2,8 @@
 
 package bgp
 
+// The JSON tags are part of the schema of the API (/api/v1/targets/{target}/bgp)
+
 type result struct {
 	Information struct {
 		Peers []peer `xml:"bgp-peer"`
@@ -9,50 +11,50 @@ type result struct {
 }
 
 type peer struct {
-	CFGRTI             string            `xml:"peer-cfg-rti"`
-	IP                 string            `xml:"peer-address"`
-	ASN                string            `xml:"peer-as"`
-	LocalASN           int64             `xml:"local-as"`
-	State              string            `xml:"peer-state"`
-	Group              string            `xml:"peer-group"`
-	GroupIndex         int64             `xml:"peer-group-index"`
-	Description        string            `xml:"description"`
-	Flaps              int64             `xml:"flap-count"`
-	InputMessages      int64             `xml:"input-messages"`
-	OutputMessages     int64             `xml:"output-messages"`
-	RIBs               []rib             `xml:"bgp-rib"`
-	OptionInformation  optionInformation `xml:"bgp-option-information"`
-	LocalInterfaceName string            `xml:"local-interface-name"`
+	CFGRTI             string            `xml:"peer-cfg-rti" json:"routing_instance"`
+	IP                 string            `xml:"peer-address" json:"address"`
+	ASN                string            `xml:"peer-as" json:"asn"`
+	LocalASN           int64             `xml:"local-as" json:"local_asn"`
+	State              string            `xml:"peer-state" json:"state"`
+	Group              string            `xml:"peer-group" json:"group"`
+	GroupIndex         int64             `xml:"peer-group-index" json:"group_index"`
+	Description        string            `xml:"description" json:"description"`
+	Flaps              int64             `xml:"flap-count" json:"flaps"`
+	InputMessages      int64             `xml:"input-messages" json:"input_messages"`
+	OutputMessages     int64             `xml:"output-messages" json:"output_messages"`
+	RIBs               []rib             `xml:"bgp-rib" json:"ribs"`
+	OptionInformation  optionInformation `xml:"bgp-option-information" json:"options"`
+	LocalInterfaceName string            `xml:"local-interface-name" json:"local_interface,omitempty"`
 }
 
 type rib struct {
-	Name               string `xml:"name"`
-	ActivePrefixes     int64  `xml:"active-prefix-count"`
-	ReceivedPrefixes   int64  `xml:"received-prefix-count"`
-	AcceptedPrefixes   int64  `xml:"accepted-prefix-count"`
-	RejectedPrefixes   int64  `xml:"suppressed-prefix-count"`
-	AdvertisedPrefixes int64  `xml:"advertised-prefix-count"`
+	Name               string `xml:"name" json:"name"`
+	ActivePrefixes     int64  `xml:"active-prefix-count" json:"active_prefixes"`
+	ReceivedPrefixes   int64  `xml:"received-prefix-count" json:"received_prefixes"`
+	AcceptedPrefixes   int64  `xml:"accepted-prefix-count" json:"accepted_prefixes"`
+	RejectedPrefixes   int64  `xml:"suppressed-prefix-count" json:"rejected_prefixes"`
+	AdvertisedPrefixes int64  `xml:"advertised-prefix-count" json:"advertised_prefixes"`
 }
 
 type optionInformation struct {
-	ExportPolicy    string      `xml:"export-policy"`
-	ImportPolicy    string      `xml:"import-policy"`
-	AddressFamilies string      `xml:"address-families"`
-	LocalAddress    string      `xml:"local-address"`
-	Holdtime        int64       `xml:"holdtime"`
-	MetricOut       int64       `xml:"metric-out"`
-	Preference      int64       `xml:"preference"`
-	PrefixLimit     prefixLimit `xml:"prefix-limit"`
-	LocalAs         int64       `xml:"local-as"`
-	LocalSystemAs   int64       `xml:"local-system-as"`
-	Options         string      `xml:"bgp-options"`
+	ExportPolicy    string      `xml:"export-policy" json:"export_policy"`
+	ImportPolicy    string      `xml:"import-policy" json:"import_policy"`
+	AddressFamilies string      `xml:"address-families" json:"address_families"`
+	LocalAddress    string      `xml:"local-address" json:"local_address"`
+	Holdtime        int64       `xml:"holdtime" json:"hold_time"`
+	MetricOut       int64       `xml:"metric-out" json:"metric_out"`
+	Preference      int64       `xml:"preference" json:"preference"`
+	PrefixLimit     prefixLimit `xml:"prefix-limit" json:"prefix_limit"`
+	LocalAs         int64       `xml:"local-as" json:"local_as"`
+	LocalSystemAs   int64       `xml:"local-system-as" json:"local_system_as"`
+	Options         string      `xml:"bgp-options" json:"options"`
 }
 
 type prefixLimit struct {
-	NlriType          string `xml:"nlri-type"`
-	PrefixCount       int64  `xml:"prefix-count"`
-	LimitAction       string `xml:"limit-action"`
-	WarningPercentage int64  `xml:"warning-percentage"`
+	NlriType          string `xml:"nlri-type" json:"nlri_type"`
+	PrefixCount       int64  `xml:"prefix-count" json:"prefix_count"`
+	LimitAction       string `xml:"limit-action" json:"limit_action"`
+	WarningPercentage int64  `xml:"warning-percentage" json:"warning_percentage"`
 }
 
 type groupResult struct {
@@ -61,6 +63,6 @@ type groupResult struct {
 	} `xml:"bgp-group-information"`
 }
 type group struct {
-	Index int64  `xml:"group-index"`
-	Name  string `xml:"name"`
+	Index int64  `xml:"group-index" json:"index"`
+	Name  string `xml:"name" json:"name"`
 }
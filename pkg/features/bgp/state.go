@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+
+package bgp
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+)
+
+type state struct {
+	Groups []group `json:"groups"`
+	Peers  []peer  `json:"peers"`
+}
+
+// State returns the BGP groups and peers (including RIBs and policies)
+func (c *bgpCollector) State(client collector.Client) (any, error) {
+	groups, err := c.collectGroups(client)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := c.peers(client)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &state{
+		Groups: make([]group, 0, len(groups)),
+		Peers:  make([]peer, 0, len(peers)),
+	}
+
+	for _, idx := range slices.Sorted(maps.Keys(groups)) {
+		s.Groups = append(s.Groups, groups[idx])
+	}
+
+	for _, p := range peers {
+		p.Group = groupForPeer(p, groups)
+		p.OptionInformation.ImportPolicy = formatPolicy(p.OptionInformation.ImportPolicy)
+		p.OptionInformation.ExportPolicy = formatPolicy(p.OptionInformation.ExportPolicy)
+		s.Peers = append(s.Peers, p)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+
+package bgp
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+// mockClient serves canned XML bodies keyed by the command
+type mockClient struct {
+	responses map[string]string
+}
+
+func (m *mockClient) RunCommandAndParse(cmd string, obj any) error {
+	body, ok := m.responses[cmd]
+	if !ok {
+		return fmt.Errorf("mockClient: no canned response for %q", cmd)
+	}
+
+	return xml.Unmarshal([]byte(body), obj)
+}
+
+func (m *mockClient) RunCommandAndParseWithParser(cmd string, parser rpc.Parser) error {
+	body, ok := m.responses[cmd]
+	if !ok {
+		return fmt.Errorf("mockClient: no canned response for %q", cmd)
+	}
+
+	return parser([]byte(body))
+}
+
+func (m *mockClient) IsSatelliteEnabled() bool {
+	return false
+}
+
+func (m *mockClient) IsScrapingLicenseEnabled() bool {
+	return false
+}
+
+func (m *mockClient) Device() *connector.Device {
+	return &connector.Device{Host: "router1"}
+}
+
+func (m *mockClient) Context() context.Context {
+	return context.TODO()
+}
+
+func TestState(t *testing.T) {
+	cl := &mockClient{
+		responses: map[string]string{
+			"show bgp group": `<rpc-reply>
+<bgp-group-information>
+    <bgp-group>
+        <group-index>1</group-index>
+        <name>transit</name>
+    </bgp-group>
+    <bgp-group>
+        <group-index>0</group-index>
+        <name>ibgp</name>
+    </bgp-group>
+</bgp-group-information>
+</rpc-reply>`,
+			"show bgp neighbor": `<rpc-reply>
+<bgp-information>
+    <bgp-peer>
+        <peer-address>192.0.2.1+179</peer-address>
+        <peer-as>64496</peer-as>
+        <peer-state>Established</peer-state>
+        <peer-group-index>1</peer-group-index>
+        <description>transit1</description>
+        <flap-count>2</flap-count>
+        <bgp-option-information>
+            <import-policy>
+                transit-in
+            </import-policy>
+            <export-policy>transit-out</export-policy>
+            <local-as>64511</local-as>
+        </bgp-option-information>
+        <bgp-rib>
+            <name>inet.0</name>
+            <active-prefix-count>100</active-prefix-count>
+            <received-prefix-count>120</received-prefix-count>
+            <accepted-prefix-count>110</accepted-prefix-count>
+        </bgp-rib>
+    </bgp-peer>
+</bgp-information>
+</rpc-reply>`,
+		},
+	}
+
+	s, err := NewCollector("", "", nil).(*bgpCollector).State(cl)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+  "groups": [
+    {"index": 0, "name": "ibgp"},
+    {"index": 1, "name": "transit"}
+  ],
+  "peers": [
+    {
+      "routing_instance": "",
+      "address": "192.0.2.1+179",
+      "asn": "64496",
+      "local_asn": 0,
+      "state": "Established",
+      "group": "transit",
+      "group_index": 1,
+      "description": "transit1",
+      "flaps": 2,
+      "input_messages": 0,
+      "output_messages": 0,
+      "ribs": [
+        {"name": "inet.0", "active_prefixes": 100, "received_prefixes": 120, "accepted_prefixes": 110, "rejected_prefixes": 0, "advertised_prefixes": 0}
+      ],
+      "options": {
+        "export_policy": "transit-out",
+        "import_policy": "transit-in",
+        "address_families": "",
+        "local_address": "",
+        "hold_time": 0,
+        "metric_out": 0,
+        "preference": 0,
+        "prefix_limit": {"nlri_type": "", "prefix_count": 0, "limit_action": "", "warning_percentage": 0},
+        "local_as": 64511,
+        "local_system_as": 0,
+        "options": ""
+      }
+    }
+  ]
+}`, string(b))
+}
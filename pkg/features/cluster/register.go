@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package cluster
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "cluster",
+		Feature: "cluster",
+		Flag:    "cluster.enabled",
+		Help:    "Scrape chassis cluster metrics",
+		Commands: []string{
+			"show chassis cluster status",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
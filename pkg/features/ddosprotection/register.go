@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package ddosprotection
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "ddosprotection",
+		Feature: "ddos_protection",
+		Flag:    "ddos_protection.enabled",
+		Help:    "Scrape DDoS protection metrics",
+		Commands: []string{
+			"show ddos-protection protocols statistics",
+			"show ddos-protection protocols parameters",
+			"show ddos-protection protocols flow-detection",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
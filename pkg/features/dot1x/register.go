@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package dot1x
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "dot1x",
+		Feature: "dot1x",
+		Flag:    "dot1x.enabled",
+		Help:    "Scrape dot1x metrics",
+		Commands: []string{
+			"show dot1x interface extensive",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "env",
+		Feature:        "environment",
+		Flag:           "environment.enabled",
+		Help:           "Scrape environment metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show chassis environment",
+			"show chassis environment satellite",
+			"show chassis environment pem",
+			"show chassis environment psm",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -56,6 +56,11 @@ var (
 	// ============== L3 context descriptors (from show evpn l3-context) ==============
 	l3ContextVNI   *prometheus.Desc
 	l3ContextCount *prometheus.Desc
+
+	// names of the metrics by RPC, so RPCs can be skipped if their metrics are dropped
+	instanceMetrics     collector.MetricNames
+	duplicateMACMetrics collector.MetricNames
+	l3ContextMetrics    collector.MetricNames
 )
 
 func init() {
@@ -70,100 +75,102 @@ func init() {
 	l3CtxLabels := []string{"target", "context", "type", "advertisement_mode", "router_mac", "encapsulation"}
 	stateLabels := []string{"target"}
 
-	instanceInfo = prometheus.NewDesc(prefix+"instance_info",
+	instanceInfo = instanceMetrics.NewDesc(prefix+"instance_info",
 		"Per-EVI metadata (always 1). Labels carry route-distinguisher, encapsulation, router-id and source VTEP address.",
 		infoLabels, nil)
-	instanceNeighborCount = prometheus.NewDesc(prefix+"instance_neighbor_count",
+	instanceNeighborCount = instanceMetrics.NewDesc(prefix+"instance_neighbor_count",
 		"Number of EVPN neighbors (PEs) for this instance", il, nil)
-	instanceESICount = prometheus.NewDesc(prefix+"instance_esi_count",
+	instanceESICount = instanceMetrics.NewDesc(prefix+"instance_esi_count",
 		"Number of Ethernet Segment Identifiers known by this instance", il, nil)
-	instanceLocalInterfaces = prometheus.NewDesc(prefix+"instance_local_interfaces",
+	instanceLocalInterfaces = instanceMetrics.NewDesc(prefix+"instance_local_interfaces",
 		"Number of local interfaces in this EVPN instance", il, nil)
-	instanceLocalInterfacesUp = prometheus.NewDesc(prefix+"instance_local_interfaces_up",
+	instanceLocalInterfacesUp = instanceMetrics.NewDesc(prefix+"instance_local_interfaces_up",
 		"Number of local interfaces currently up in this EVPN instance", il, nil)
-	instanceIRBInterfaces = prometheus.NewDesc(prefix+"instance_irb_interfaces",
+	instanceIRBInterfaces = instanceMetrics.NewDesc(prefix+"instance_irb_interfaces",
 		"Number of IRB interfaces in this EVPN instance", il, nil)
-	instanceIRBInterfacesUp = prometheus.NewDesc(prefix+"instance_irb_interfaces_up",
+	instanceIRBInterfacesUp = instanceMetrics.NewDesc(prefix+"instance_irb_interfaces_up",
 		"Number of IRB interfaces currently up in this EVPN instance", il, nil)
-	instanceProtectInterfaces = prometheus.NewDesc(prefix+"instance_protect_interfaces",
+	instanceProtectInterfaces = instanceMetrics.NewDesc(prefix+"instance_protect_interfaces",
 		"Number of protect (backup) interfaces in this EVPN instance", il, nil)
-	instanceBridgeDomains = prometheus.NewDesc(prefix+"instance_bridge_domains",
+	instanceBridgeDomains = instanceMetrics.NewDesc(prefix+"instance_bridge_domains",
 		"Number of bridge domains in this EVPN instance", il, nil)
-	instanceLocalMACs = prometheus.NewDesc(prefix+"instance_local_mac_count",
+	instanceLocalMACs = instanceMetrics.NewDesc(prefix+"instance_local_mac_count",
 		"Number of MACs learned locally in this EVPN instance", il, nil)
-	instanceRemoteMACs = prometheus.NewDesc(prefix+"instance_remote_mac_count",
+	instanceRemoteMACs = instanceMetrics.NewDesc(prefix+"instance_remote_mac_count",
 		"Number of MACs learned from remote PEs in this EVPN instance", il, nil)
-	instanceLocalMACIPs = prometheus.NewDesc(prefix+"instance_local_mac_ip_count",
+	instanceLocalMACIPs = instanceMetrics.NewDesc(prefix+"instance_local_mac_ip_count",
 		"Number of local MAC+IP bindings in this EVPN instance", il, nil)
-	instanceRemoteMACIPs = prometheus.NewDesc(prefix+"instance_remote_mac_ip_count",
+	instanceRemoteMACIPs = instanceMetrics.NewDesc(prefix+"instance_remote_mac_ip_count",
 		"Number of remote MAC+IP bindings in this EVPN instance", il, nil)
-	instanceLocalDefaultGwMACs = prometheus.NewDesc(prefix+"instance_local_default_gateway_mac_count",
+	instanceLocalDefaultGwMACs = instanceMetrics.NewDesc(prefix+"instance_local_default_gateway_mac_count",
 		"Number of local default-gateway MACs in this EVPN instance", il, nil)
-	instanceRemoteDefaultGwMACs = prometheus.NewDesc(prefix+"instance_remote_default_gateway_mac_count",
+	instanceRemoteDefaultGwMACs = instanceMetrics.NewDesc(prefix+"instance_remote_default_gateway_mac_count",
 		"Number of remote default-gateway MACs in this EVPN instance", il, nil)
-	instanceDuplicateMACThreshold = prometheus.NewDesc(prefix+"instance_duplicate_mac_threshold",
+	instanceDuplicateMACThreshold = instanceMetrics.NewDesc(prefix+"instance_duplicate_mac_threshold",
 		"Configured duplicate-MAC detection threshold for this EVPN instance", il, nil)
-	instanceDuplicateMACWindow = prometheus.NewDesc(prefix+"instance_duplicate_mac_window_seconds",
+	instanceDuplicateMACWindow = instanceMetrics.NewDesc(prefix+"instance_duplicate_mac_window_seconds",
 		"Configured duplicate-MAC detection window in seconds for this EVPN instance", il, nil)
 
-	neighborMACRoutes = prometheus.NewDesc(prefix+"neighbor_mac_routes",
+	neighborMACRoutes = instanceMetrics.NewDesc(prefix+"neighbor_mac_routes",
 		"Per-neighbor count of EVPN Type-2 MAC routes (without IP)", neighborLabels, nil)
-	neighborMACIPRoutes = prometheus.NewDesc(prefix+"neighbor_mac_ip_routes",
+	neighborMACIPRoutes = instanceMetrics.NewDesc(prefix+"neighbor_mac_ip_routes",
 		"Per-neighbor count of EVPN Type-2 MAC+IP routes", neighborLabels, nil)
-	neighborAutoDiscoveryRoutes = prometheus.NewDesc(prefix+"neighbor_ethernet_autodiscovery_routes",
+	neighborAutoDiscoveryRoutes = instanceMetrics.NewDesc(prefix+"neighbor_ethernet_autodiscovery_routes",
 		"Per-neighbor count of EVPN Type-1 Ethernet auto-discovery routes", neighborLabels, nil)
-	neighborInclusiveMulticastRoutes = prometheus.NewDesc(prefix+"neighbor_inclusive_multicast_routes",
+	neighborInclusiveMulticastRoutes = instanceMetrics.NewDesc(prefix+"neighbor_inclusive_multicast_routes",
 		"Per-neighbor count of EVPN Type-3 inclusive-multicast routes", neighborLabels, nil)
-	neighborEthernetSegmentRoutes = prometheus.NewDesc(prefix+"neighbor_ethernet_segment_routes",
+	neighborEthernetSegmentRoutes = instanceMetrics.NewDesc(prefix+"neighbor_ethernet_segment_routes",
 		"Per-neighbor count of EVPN Type-4 Ethernet-segment routes", neighborLabels, nil)
 
 	// Detail (Phase A): per-interface, per-IRB, per-bridge-domain, per-ESI.
-	interfaceStatus = prometheus.NewDesc(prefix+"interface_status",
+	interfaceStatus = instanceMetrics.NewDesc(prefix+"interface_status",
 		"EVPN interface status (0: down, 1: up). Labels carry the ESI, mode (single-homed / all-active / active-standby) and E-tree role.",
 		interfaceLabels, nil)
-	irbStatus = prometheus.NewDesc(prefix+"irb_status",
+	irbStatus = instanceMetrics.NewDesc(prefix+"irb_status",
 		"IRB interface status within an EVPN instance (0: down, 1: up). Labels carry the IRB VNI and L3 context.",
 		irbLabels, nil)
-	bridgeDomainInterfaceCount = prometheus.NewDesc(prefix+"bridge_domain_interface_count",
+	bridgeDomainInterfaceCount = instanceMetrics.NewDesc(prefix+"bridge_domain_interface_count",
 		"Total interfaces in this bridge-domain", bdLabels, nil)
-	bridgeDomainInterfaceUpCount = prometheus.NewDesc(prefix+"bridge_domain_interface_up_count",
+	bridgeDomainInterfaceUpCount = instanceMetrics.NewDesc(prefix+"bridge_domain_interface_up_count",
 		"Interfaces currently up in this bridge-domain", bdLabels, nil)
-	esiResolved = prometheus.NewDesc(prefix+"esi_resolved",
+	esiResolved = instanceMetrics.NewDesc(prefix+"esi_resolved",
 		"EVPN ESI resolution state (0: unresolved, 1: resolved). Join with junos_evpn_esi_designated_forwarder_info on (target, instance, esi) for DF/local-interface labels.",
 		esiCountLabels, nil)
-	esiRemotePECount = prometheus.NewDesc(prefix+"esi_remote_pe_count",
+	esiRemotePECount = instanceMetrics.NewDesc(prefix+"esi_remote_pe_count",
 		"Number of remote PEs known for this Ethernet Segment", esiCountLabels, nil)
-	esiDesignatedForwarderInfo = prometheus.NewDesc(prefix+"esi_designated_forwarder_info",
+	esiDesignatedForwarderInfo = instanceMetrics.NewDesc(prefix+"esi_designated_forwarder_info",
 		"EVPN ESI designated-forwarder election state (gauge=1 info-pattern). Labels churn on DF election events; join with junos_evpn_esi_resolved for clean state queries.",
 		esiDFLabels, nil)
 
 	// Duplicate-MAC: target-level total always emitted, per-instance only when > 0.
-	duplicateMACTotal = prometheus.NewDesc(prefix+"duplicate_mac_total",
+	duplicateMACTotal = duplicateMACMetrics.NewDesc(prefix+"duplicate_mac_total",
 		"Total MAC entries currently suppressed by duplicate-MAC detection across all EVIs on this device. Non-zero indicates a forwarding loop or split-brain.",
 		stateLabels, nil)
-	duplicateMACCount = prometheus.NewDesc(prefix+"duplicate_mac_count",
+	duplicateMACCount = duplicateMACMetrics.NewDesc(prefix+"duplicate_mac_count",
 		"MAC entries currently suppressed by duplicate-MAC detection in a specific EVPN instance. Emitted only when count > 0.",
 		il, nil)
 
 	// L3 context.
-	l3ContextVNI = prometheus.NewDesc(prefix+"l3_context_vni",
+	l3ContextVNI = l3ContextMetrics.NewDesc(prefix+"l3_context_vni",
 		"EVPN L3 context (VRF) VNI. Labels carry context type, advertisement mode, router MAC and encapsulation.",
 		l3CtxLabels, nil)
-	l3ContextCount = prometheus.NewDesc(prefix+"l3_context_count",
+	l3ContextCount = l3ContextMetrics.NewDesc(prefix+"l3_context_count",
 		"Number of EVPN L3 contexts configured on this device", []string{"target"}, nil)
 }
 
-type evpnCollector struct{}
+type evpnCollector struct {
+	params *collector.Params
+}
 
 // Name returns the name of the collector.
 func (*evpnCollector) Name() string { return "evpn" }
 
 // NewCollector creates a new collector.
-func NewCollector() collector.RPCCollector { return new(evpnCollector) }
+func NewCollector(p *collector.Params) collector.RPCCollector { return &evpnCollector{params: p} }
 
-// Describe describes the metrics.
-func (*evpnCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, d := range []*prometheus.Desc{
+// instanceDescs are the descriptions of the metrics of RPC 1 (see Collect).
+func instanceDescs() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		instanceInfo,
 		instanceNeighborCount, instanceESICount,
 		instanceLocalInterfaces, instanceLocalInterfacesUp,
@@ -179,6 +186,16 @@ func (*evpnCollector) Describe(ch chan<- *prometheus.Desc) {
 		interfaceStatus, irbStatus,
 		bridgeDomainInterfaceCount, bridgeDomainInterfaceUpCount,
 		esiResolved, esiRemotePECount, esiDesignatedForwarderInfo,
+	}
+}
+
+// Describe describes the metrics.
+func (*evpnCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range instanceDescs() {
+		ch <- d
+	}
+
+	for _, d := range []*prometheus.Desc{
 		duplicateMACTotal, duplicateMACCount,
 		l3ContextVNI, l3ContextCount,
 	} {
@@ -200,43 +217,51 @@ func (*evpnCollector) Describe(ch chan<- *prometheus.Desc) {
 //
 //  3. show evpn l3-context — BEST EFFORT. Returns L3 (IRB) context info.
 //     Empty when EVPN-IRB is not configured.
+//
+// An RPC is skipped if all of its metrics are dropped by the relabel rules.
 func (c *evpnCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
 	// --- RPC 1: instance (required) ---
-	var instances []evpnInstance
-	if err := client.RunCommandAndParseWithParser("show evpn instance extensive", func(b []byte) error {
-		var perr error
-		instances, perr = parseInstances(b)
-		return perr
-	}); err != nil {
-		return err
-	}
+	if !c.params.Dropped(instanceMetrics.Names()...) {
+		var instances []evpnInstance
+		if err := client.RunCommandAndParseWithParser("show evpn instance extensive", func(b []byte) error {
+			var perr error
+			instances, perr = parseInstances(b)
+			return perr
+		}); err != nil {
+			return err
+		}
 
-	for _, in := range instances {
-		c.emitInstance(ch, labelValues, in)
+		for _, in := range instances {
+			c.emitInstance(ch, labelValues, in)
+		}
 	}
 
 	// --- RPC 2: duplicate-MAC (best effort) ---
-	var dupInstances []duplicateInstance
-	if err := client.RunCommandAndParseWithParser("show evpn database state duplicate", func(b []byte) error {
-		var perr error
-		dupInstances, perr = parseDuplicateMACs(b)
-		return perr
-	}); err != nil {
-		log.Warnf("evpn: 'show evpn database state duplicate' failed: %v", err)
-	} else {
-		c.emitDuplicateMACs(ch, labelValues, dupInstances)
+	if !c.params.Dropped(duplicateMACMetrics.Names()...) {
+		var dupInstances []duplicateInstance
+		if err := client.RunCommandAndParseWithParser("show evpn database state duplicate", func(b []byte) error {
+			var perr error
+			dupInstances, perr = parseDuplicateMACs(b)
+			return perr
+		}); err != nil {
+			log.Warnf("evpn: 'show evpn database state duplicate' failed: %v", err)
+		} else {
+			c.emitDuplicateMACs(ch, labelValues, dupInstances)
+		}
 	}
 
 	// --- RPC 3: l3-context (best effort) ---
-	var contexts []l3Context
-	if err := client.RunCommandAndParseWithParser("show evpn l3-context", func(b []byte) error {
-		var perr error
-		contexts, perr = parseL3Contexts(b)
-		return perr
-	}); err != nil {
-		log.Warnf("evpn: 'show evpn l3-context' failed: %v", err)
-	} else {
-		c.emitL3Contexts(ch, labelValues, contexts)
+	if !c.params.Dropped(l3ContextMetrics.Names()...) {
+		var contexts []l3Context
+		if err := client.RunCommandAndParseWithParser("show evpn l3-context", func(b []byte) error {
+			var perr error
+			contexts, perr = parseL3Contexts(b)
+			return perr
+		}); err != nil {
+			log.Warnf("evpn: 'show evpn l3-context' failed: %v", err)
+		} else {
+			c.emitL3Contexts(ch, labelValues, contexts)
+		}
 	}
 
 	return nil
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package evpn
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "evpn",
+		Feature: "evpn",
+		Flag:    "evpn.enabled",
+		Help:    "Scrape EVPN instance, detail tables (interfaces/IRBs/bridge-domains/ESIs), duplicate-MAC, and L3 context metrics",
+		Commands: []string{
+			"show evpn instance extensive",
+			"show evpn database state duplicate",
+			"show evpn l3-context",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p)
+		},
+	})
+}
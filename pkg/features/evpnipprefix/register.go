@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package evpnipprefix
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "evpn_ip_prefix",
+		Feature: "evpn_ip_prefix",
+		Flag:    "evpn_ip_prefix.enabled",
+		Help:    "Scrape EVPN Type-5 (IP-prefix) database metrics; potentially large on busy fabrics",
+		Commands: []string{
+			"show evpn ip-prefix-database",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -26,12 +26,22 @@ func init() {
 }
 
 type firewallCollector struct {
+	LogicalSystem   string
 	filterNameRegex string
 }
 
+// Options are the options of the collector
+type Options struct {
+	// FilterNameRegex is a regex matching the names of the filters to collect
+	FilterNameRegex string `yaml:"filter_name_regex,omitempty"`
+}
+
 // NewCollector creates a new collector
-func NewCollector(filterNameRegex string) collector.RPCCollector {
-	return &firewallCollector{filterNameRegex: filterNameRegex}
+func NewCollector(logicalSystem string, opts Options) collector.RPCCollector {
+	return &firewallCollector{
+		LogicalSystem:   logicalSystem,
+		filterNameRegex: opts.FilterNameRegex,
+	}
 }
 
 // Name returns the name of the collector
@@ -54,7 +64,12 @@ func (c *firewallCollector) Collect(client collector.Client, ch chan<- prometheu
 	if filterNameRegex == "" {
 		filterNameRegex = ".*"
 	}
-	err := client.RunCommandAndParse("show firewall filter regex "+filterNameRegex, &x)
+	cmd := "show firewall filter regex " + filterNameRegex
+	if c.LogicalSystem != "" {
+		cmd += " logical-system " + c.LogicalSystem
+	}
+
+	err := client.RunCommandAndParse(cmd, &x)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: MIT
+
+package firewall
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "firewall",
+		Feature:        "firewall",
+		Flag:           "firewall.enabled",
+		Help:           "Scrape Firewall count metrics",
+		DefaultEnabled: true,
+		LogicalSystems: true,
+		Commands: []string{
+			"show firewall filter regex *",
+			"show firewall filter regex * logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, collector.OptionsOf[Options](p))
+		},
+	})
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package fpc
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "fpc",
+		Feature: "fpc",
+		Flag:    "fpc.enabled",
+		Help:    "Scrape line card metrics",
+		Commands: []string{
+			"show chassis fpc",
+			"show chassis fpc detail",
+			"show chassis fpc pic-status",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
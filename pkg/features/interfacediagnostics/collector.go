@@ -182,20 +182,11 @@ func (c *interfaceDiagnosticsCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect collects metrics from JunOS
 func (c *interfaceDiagnosticsCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-	diagnostics, err := c.interfaceDiagnostics(client)
+	diagnostics, err := c.allInterfaceDiagnostics(client)
 	if err != nil {
 		return err
 	}
 
-	// add satellite details if feature is enabled
-	if client.IsSatelliteEnabled() {
-		diagnosticsSatellite, err := c.interfaceDiagnosticsSatellite(client)
-		if err != nil {
-			return err
-		}
-		diagnostics = append(diagnostics, diagnosticsSatellite...)
-	}
-
 	diagnosticsDict := make(map[string]*interfaceDiagnostics)
 
 	ifMediaDict, err := c.interfaceMediaInfo(client)
@@ -285,6 +276,25 @@ func (c *interfaceDiagnosticsCollector) Collect(client collector.Client, ch chan
 	return nil
 }
 
+// allInterfaceDiagnostics returns the diagnostics of the interfaces of the device and, if enabled, of its satellites
+func (c *interfaceDiagnosticsCollector) allInterfaceDiagnostics(client collector.Client) ([]*interfaceDiagnostics, error) {
+	diagnostics, err := c.interfaceDiagnostics(client)
+	if err != nil {
+		return nil, err
+	}
+
+	// add satellite details if feature is enabled
+	if client.IsSatelliteEnabled() {
+		diagnosticsSatellite, err := c.interfaceDiagnosticsSatellite(client)
+		if err != nil {
+			return nil, err
+		}
+		diagnostics = append(diagnostics, diagnosticsSatellite...)
+	}
+
+	return diagnostics, nil
+}
+
 func (c *interfaceDiagnosticsCollector) interfaceMediaInfo(client collector.Client) (map[string]*physicalInterface, error) {
 	var x = interfacesMediaStruct{}
 	err := client.RunCommandAndParse("show interfaces media", &x)
@@ -2,52 +2,53 @@
 
 package interfacediagnostics
 
+// interfaceDiagnostics is part of the schema of the API (/api/v1/targets/{target}/interface_diagnostic)
 type interfaceDiagnostics struct {
-	Index                              string
-	Name                               string
-	LaserBiasCurrent                   float64
-	LaserBiasCurrentHighAlarmThreshold float64
-	LaserBiasCurrentLowAlarmThreshold  float64
-	LaserBiasCurrentHighWarnThreshold  float64
-	LaserBiasCurrentLowWarnThreshold   float64
-
-	LaserOutputPower                   float64
-	LaserOutputPowerHighAlarmThreshold float64
-	LaserOutputPowerLowAlarmThreshold  float64
-	LaserOutputPowerHighWarnThreshold  float64
-	LaserOutputPowerLowWarnThreshold   float64
-
-	LaserOutputPowerDbm                   float64
-	LaserOutputPowerHighAlarmThresholdDbm float64
-	LaserOutputPowerLowAlarmThresholdDbm  float64
-	LaserOutputPowerHighWarnThresholdDbm  float64
-	LaserOutputPowerLowWarnThresholdDbm   float64
-
-	ModuleTemperature                   float64
-	ModuleTemperatureHighAlarmThreshold float64
-	ModuleTemperatureLowAlarmThreshold  float64
-	ModuleTemperatureHighWarnThreshold  float64
-	ModuleTemperatureLowWarnThreshold   float64
-
-	LaserRxOpticalPower                   float64
-	LaserRxOpticalPowerHighAlarmThreshold float64
-	LaserRxOpticalPowerLowAlarmThreshold  float64
-	LaserRxOpticalPowerHighWarnThreshold  float64
-	LaserRxOpticalPowerLowWarnThreshold   float64
-
-	LaserRxOpticalPowerDbm                   float64
-	LaserRxOpticalPowerHighAlarmThresholdDbm float64
-	LaserRxOpticalPowerLowAlarmThresholdDbm  float64
-	LaserRxOpticalPowerHighWarnThresholdDbm  float64
-	LaserRxOpticalPowerLowWarnThresholdDbm   float64
-
-	ModuleVoltage                   float64
-	ModuleVoltageHighAlarmThreshold float64
-	ModuleVoltageLowAlarmThreshold  float64
-	ModuleVoltageHighWarnThreshold  float64
-	ModuleVoltageLowWarnThreshold   float64
-	RxSignalAvgOpticalPower         float64
-	RxSignalAvgOpticalPowerDbm      float64
-
-	Lanes []*interfaceDiagnostics
+	Index                              string  `json:"index"`
+	Name                               string  `json:"name"`
+	LaserBiasCurrent                   float64 `json:"laser_bias_current"`
+	LaserBiasCurrentHighAlarmThreshold float64 `json:"laser_bias_current_high_alarm_threshold"`
+	LaserBiasCurrentLowAlarmThreshold  float64 `json:"laser_bias_current_low_alarm_threshold"`
+	LaserBiasCurrentHighWarnThreshold  float64 `json:"laser_bias_current_high_warn_threshold"`
+	LaserBiasCurrentLowWarnThreshold   float64 `json:"laser_bias_current_low_warn_threshold"`
+
+	LaserOutputPower                   float64 `json:"laser_output_power"`
+	LaserOutputPowerHighAlarmThreshold float64 `json:"laser_output_power_high_alarm_threshold"`
+	LaserOutputPowerLowAlarmThreshold  float64 `json:"laser_output_power_low_alarm_threshold"`
+	LaserOutputPowerHighWarnThreshold  float64 `json:"laser_output_power_high_warn_threshold"`
+	LaserOutputPowerLowWarnThreshold   float64 `json:"laser_output_power_low_warn_threshold"`
+
+	LaserOutputPowerDbm                   float64 `json:"laser_output_power_dbm"`
+	LaserOutputPowerHighAlarmThresholdDbm float64 `json:"laser_output_power_high_alarm_threshold_dbm"`
+	LaserOutputPowerLowAlarmThresholdDbm  float64 `json:"laser_output_power_low_alarm_threshold_dbm"`
+	LaserOutputPowerHighWarnThresholdDbm  float64 `json:"laser_output_power_high_warn_threshold_dbm"`
+	LaserOutputPowerLowWarnThresholdDbm   float64 `json:"laser_output_power_low_warn_threshold_dbm"`
+
+	ModuleTemperature                   float64 `json:"module_temperature"`
+	ModuleTemperatureHighAlarmThreshold float64 `json:"module_temperature_high_alarm_threshold"`
+	ModuleTemperatureLowAlarmThreshold  float64 `json:"module_temperature_low_alarm_threshold"`
+	ModuleTemperatureHighWarnThreshold  float64 `json:"module_temperature_high_warn_threshold"`
+	ModuleTemperatureLowWarnThreshold   float64 `json:"module_temperature_low_warn_threshold"`
+
+	LaserRxOpticalPower                   float64 `json:"laser_rx_optical_power"`
+	LaserRxOpticalPowerHighAlarmThreshold float64 `json:"laser_rx_optical_power_high_alarm_threshold"`
+	LaserRxOpticalPowerLowAlarmThreshold  float64 `json:"laser_rx_optical_power_low_alarm_threshold"`
+	LaserRxOpticalPowerHighWarnThreshold  float64 `json:"laser_rx_optical_power_high_warn_threshold"`
+	LaserRxOpticalPowerLowWarnThreshold   float64 `json:"laser_rx_optical_power_low_warn_threshold"`
+
+	LaserRxOpticalPowerDbm                   float64 `json:"laser_rx_optical_power_dbm"`
+	LaserRxOpticalPowerHighAlarmThresholdDbm float64 `json:"laser_rx_optical_power_high_alarm_threshold_dbm"`
+	LaserRxOpticalPowerLowAlarmThresholdDbm  float64 `json:"laser_rx_optical_power_low_alarm_threshold_dbm"`
+	LaserRxOpticalPowerHighWarnThresholdDbm  float64 `json:"laser_rx_optical_power_high_warn_threshold_dbm"`
+	LaserRxOpticalPowerLowWarnThresholdDbm   float64 `json:"laser_rx_optical_power_low_warn_threshold_dbm"`
+
+	ModuleVoltage                   float64 `json:"module_voltage"`
+	ModuleVoltageHighAlarmThreshold float64 `json:"module_voltage_high_alarm_threshold"`
+	ModuleVoltageLowAlarmThreshold  float64 `json:"module_voltage_low_alarm_threshold"`
+	ModuleVoltageHighWarnThreshold  float64 `json:"module_voltage_high_warn_threshold"`
+	ModuleVoltageLowWarnThreshold   float64 `json:"module_voltage_low_warn_threshold"`
+	RxSignalAvgOpticalPower         float64 `json:"rx_signal_avg_optical_power"`
+	RxSignalAvgOpticalPowerDbm      float64 `json:"rx_signal_avg_optical_power_dbm"`
+
+	Lanes []*interfaceDiagnostics `json:"lanes,omitempty"`
 }
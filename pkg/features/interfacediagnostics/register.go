@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+
+package interfacediagnostics
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "ifacediag",
+		Feature:        "interface_diagnostic",
+		Flag:           "ifdiag.enabled",
+		Help:           "Scrape optical interface diagnostic metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show chassis hardware",
+			"show chassis pic fpc-slot * pic-slot *",
+			"show interfaces diagnostics optics",
+			"show interfaces diagnostics optics satellite",
+			"show interfaces media",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.InterfaceDescriptionRegex)
+		},
+	})
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package interfacediagnostics
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+type state struct {
+	Interfaces []*interfaceDiagnostics `json:"interfaces"`
+}
+
+// State returns the optics diagnostics of the interfaces
+func (c *interfaceDiagnosticsCollector) State(client collector.Client) (any, error) {
+	diagnostics, err := c.allInterfaceDiagnostics(client)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &state{Interfaces: []*interfaceDiagnostics{}}
+	s.Interfaces = append(s.Interfaces, diagnostics...)
+
+	return s, nil
+}
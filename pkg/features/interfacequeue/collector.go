@@ -32,43 +32,65 @@ type description struct {
 	tailDropPackets      *prometheus.Desc
 	totalDropPackets     *prometheus.Desc
 	totalDropBytes       *prometheus.Desc
+
+	// names are the fully-qualified names of the metrics
+	names []string
 }
 
 func newDescriptions(dynLabels dynamiclabels.Labels) *description {
 	d := new(description)
+	m := new(collector.MetricNames)
 
 	l := []string{"target", "name", "if_index", "description"}
 	l = append(l, "queue_number")
 	l = append(l, "forwarding_class")
 	l = append(l, dynLabels.Keys()...)
 
-	d.queuedPackets = prometheus.NewDesc(prefix+"queued_packets_count", "Number of queued packets", l, nil)
-	d.queuedBytes = prometheus.NewDesc(prefix+"queued_bytes_count", "Number of bytes of queued packets", l, nil)
-	d.transferedPackets = prometheus.NewDesc(prefix+"transfered_packets_count", "Number of transfered packets", l, nil)
-	d.transferedBytes = prometheus.NewDesc(prefix+"transfered_bytes_count", "Number of bytes of transfered packets", l, nil)
-	d.rateLimitDropPackets = prometheus.NewDesc(prefix+"rate_limit_drop_packets_count", "Number of packets droped by rate limit", l, nil)
-	d.rateLimitDropBytes = prometheus.NewDesc(prefix+"rate_limit_drop_bytes_count", "Number of bytes droped by rate limit", l, nil)
-	d.redPackets = prometheus.NewDesc(prefix+"red_packets_count", "Number of queued packets", l, nil)
-	d.redBytes = prometheus.NewDesc(prefix+"red_bytes_count", "Number of bytes of queued packets", l, nil)
-	d.redPacketsLow = prometheus.NewDesc(prefix+"red_packets_low_count", "Number of queued packets", l, nil)
-	d.redBytesLow = prometheus.NewDesc(prefix+"red_bytes_low_count", "Number of bytes of queued packets", l, nil)
-	d.redPacketsMediumLow = prometheus.NewDesc(prefix+"red_packets_medium_low_count", "Number of queued packets", l, nil)
-	d.redBytesMediumLow = prometheus.NewDesc(prefix+"red_bytes_medium_low_count", "Number of bytes of queued packets", l, nil)
-	d.redPacketsMediumHigh = prometheus.NewDesc(prefix+"red_packets_medium_high_count", "Number of queued packets", l, nil)
-	d.redBytesMediumHigh = prometheus.NewDesc(prefix+"red_bytes_medium_high_count", "Number of bytes of queued packets", l, nil)
-	d.redPacketsHigh = prometheus.NewDesc(prefix+"red_packets_high_count", "Number of queued packets", l, nil)
-	d.redBytesHigh = prometheus.NewDesc(prefix+"red_bytes_high_count", "Number of bytes of queued packets", l, nil)
-	d.tailDropPackets = prometheus.NewDesc(prefix+"tail_drop_packets_count", "Number of tail droped packets", l, nil)
-	d.totalDropPackets = prometheus.NewDesc(prefix+"drop_packets_count", "Number of packets droped", l, nil)
-	d.totalDropBytes = prometheus.NewDesc(prefix+"drop_bytes_count", "Number of bytes droped", l, nil)
+	d.queuedPackets = m.NewDesc(prefix+"queued_packets_count", "Number of queued packets", l, nil)
+	d.queuedBytes = m.NewDesc(prefix+"queued_bytes_count", "Number of bytes of queued packets", l, nil)
+	d.transferedPackets = m.NewDesc(prefix+"transfered_packets_count", "Number of transfered packets", l, nil)
+	d.transferedBytes = m.NewDesc(prefix+"transfered_bytes_count", "Number of bytes of transfered packets", l, nil)
+	d.rateLimitDropPackets = m.NewDesc(prefix+"rate_limit_drop_packets_count", "Number of packets droped by rate limit", l, nil)
+	d.rateLimitDropBytes = m.NewDesc(prefix+"rate_limit_drop_bytes_count", "Number of bytes droped by rate limit", l, nil)
+	d.redPackets = m.NewDesc(prefix+"red_packets_count", "Number of queued packets", l, nil)
+	d.redBytes = m.NewDesc(prefix+"red_bytes_count", "Number of bytes of queued packets", l, nil)
+	d.redPacketsLow = m.NewDesc(prefix+"red_packets_low_count", "Number of queued packets", l, nil)
+	d.redBytesLow = m.NewDesc(prefix+"red_bytes_low_count", "Number of bytes of queued packets", l, nil)
+	d.redPacketsMediumLow = m.NewDesc(prefix+"red_packets_medium_low_count", "Number of queued packets", l, nil)
+	d.redBytesMediumLow = m.NewDesc(prefix+"red_bytes_medium_low_count", "Number of bytes of queued packets", l, nil)
+	d.redPacketsMediumHigh = m.NewDesc(prefix+"red_packets_medium_high_count", "Number of queued packets", l, nil)
+	d.redBytesMediumHigh = m.NewDesc(prefix+"red_bytes_medium_high_count", "Number of bytes of queued packets", l, nil)
+	d.redPacketsHigh = m.NewDesc(prefix+"red_packets_high_count", "Number of queued packets", l, nil)
+	d.redBytesHigh = m.NewDesc(prefix+"red_bytes_high_count", "Number of bytes of queued packets", l, nil)
+	d.tailDropPackets = m.NewDesc(prefix+"tail_drop_packets_count", "Number of tail droped packets", l, nil)
+	d.totalDropPackets = m.NewDesc(prefix+"drop_packets_count", "Number of packets droped", l, nil)
+	d.totalDropBytes = m.NewDesc(prefix+"drop_bytes_count", "Number of bytes droped", l, nil)
+
+	d.names = m.Names()
 
 	return d
 }
 
+// all returns all descriptions
+func (d *description) all() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		d.queuedBytes, d.queuedPackets,
+		d.transferedBytes, d.transferedPackets,
+		d.rateLimitDropBytes, d.rateLimitDropPackets,
+		d.redPackets, d.redBytes,
+		d.redPacketsLow, d.redBytesLow,
+		d.redPacketsMediumLow, d.redBytesMediumLow,
+		d.redPacketsMediumHigh, d.redBytesMediumHigh,
+		d.redPacketsHigh, d.redBytesHigh,
+		d.tailDropPackets, d.totalDropBytes, d.totalDropPackets,
+	}
+}
+
 // NewCollector creates an queue collector instance
-func NewCollector(descRe *regexp.Regexp) collector.RPCCollector {
+func NewCollector(descRe *regexp.Regexp, p *collector.Params) collector.RPCCollector {
 	c := &interfaceQueueCollector{
 		descriptionRe: descRe,
+		params:        p,
 	}
 
 	return c
@@ -76,6 +98,7 @@ func NewCollector(descRe *regexp.Regexp) collector.RPCCollector {
 
 type interfaceQueueCollector struct {
 	descriptionRe *regexp.Regexp
+	params        *collector.Params
 }
 
 // Name returns the name of the collector
@@ -85,30 +108,17 @@ func (*interfaceQueueCollector) Name() string {
 
 // Describe describes the metrics
 func (c *interfaceQueueCollector) Describe(ch chan<- *prometheus.Desc) {
-	d := newDescriptions(nil)
-	ch <- d.queuedBytes
-	ch <- d.queuedPackets
-	ch <- d.transferedBytes
-	ch <- d.transferedPackets
-	ch <- d.rateLimitDropBytes
-	ch <- d.rateLimitDropPackets
-	ch <- d.redPackets
-	ch <- d.redBytes
-	ch <- d.redPacketsLow
-	ch <- d.redBytesLow
-	ch <- d.redPacketsMediumLow
-	ch <- d.redBytesMediumLow
-	ch <- d.redPacketsMediumHigh
-	ch <- d.redBytesMediumHigh
-	ch <- d.redPacketsHigh
-	ch <- d.redBytesHigh
-	ch <- d.tailDropPackets
-	ch <- d.totalDropBytes
-	ch <- d.totalDropPackets
+	for _, d := range newDescriptions(nil).all() {
+		ch <- d
+	}
 }
 
-// Collect collects metrics from JunOS
+// Collect collects metrics from JunOS. Nothing is collected if all metrics are dropped by the relabel rules.
 func (c *interfaceQueueCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	if c.params.Dropped(newDescriptions(nil).names...) {
+		return nil
+	}
+
 	q := result{}
 
 	err := client.RunCommandAndParse("show interfaces queue", &q)
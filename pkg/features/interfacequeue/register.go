@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package interfacequeue
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "ifacequeue",
+		Feature:        "interface_queue",
+		Flag:           "queues.enabled",
+		Help:           "Scrape interface queue metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show interfaces queue",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.InterfaceDescriptionRegex, p)
+		},
+	})
+}
@@ -107,15 +107,23 @@ func newDescriptions(dynLabels dynamiclabels.Labels) *description {
 
 // Collector collects interface metrics
 type interfaceCollector struct {
+	LogicalSystem      string
 	descriptionRe      *regexp.Regexp
 	interfaceNameRegex string
 }
 
+// Options are the options of the collector
+type Options struct {
+	// NameRegex is a regex matching the names of the interfaces to collect
+	NameRegex string `yaml:"name_regex,omitempty"`
+}
+
 // NewCollector creates a new collector
-func NewCollector(descRe *regexp.Regexp, interfaceNameRegex string) collector.RPCCollector {
+func NewCollector(logicalSystem string, descRe *regexp.Regexp, opts Options) collector.RPCCollector {
 	c := &interfaceCollector{
+		LogicalSystem:      logicalSystem,
 		descriptionRe:      descRe,
-		interfaceNameRegex: interfaceNameRegex,
+		interfaceNameRegex: opts.NameRegex,
 	}
 
 	return c
@@ -190,6 +198,9 @@ func (c *interfaceCollector) interfaceStats(client collector.Client) ([]*interfa
 	if c.interfaceNameRegex != "" {
 		cmd = fmt.Sprintf("show interfaces extensive %s", c.interfaceNameRegex)
 	}
+	if c.LogicalSystem != "" {
+		cmd += " logical-system " + c.LogicalSystem
+	}
 	err := client.RunCommandAndParse(cmd, &x)
 	if err != nil {
 		return nil, err
@@ -43,6 +43,7 @@ func (m *mockClient) Context() context.Context {
 func TestInterfaceCollectorCommand(t *testing.T) {
 	tests := []struct {
 		name               string
+		logicalSystem      string
 		interfaceNameRegex string
 		expectedCmd        string
 	}{
@@ -56,11 +57,17 @@ func TestInterfaceCollectorCommand(t *testing.T) {
 			interfaceNameRegex: "[!(d)][!(i)]*",
 			expectedCmd:        "show interfaces extensive [!(d)][!(i)]*",
 		},
+		{
+			name:               "logical system",
+			logicalSystem:      "ls1",
+			interfaceNameRegex: "ge-*",
+			expectedCmd:        "show interfaces extensive ge-* logical-system ls1",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			col := NewCollector(nil, tt.interfaceNameRegex).(*interfaceCollector)
+			col := NewCollector(tt.logicalSystem, nil, Options{NameRegex: tt.interfaceNameRegex}).(*interfaceCollector)
 			client := new(mockClient)
 			_, _ = col.interfaceStats(client)
 			if client.lastCmd != tt.expectedCmd {
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+
+package interfaces
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "iface",
+		Feature:        "interfaces",
+		Flag:           "interfaces.enabled",
+		Help:           "Scrape interface metrics",
+		DefaultEnabled: true,
+		LogicalSystems: true,
+		Commands: []string{
+			"show interfaces extensive",
+			"show interfaces extensive logical-system *",
+			"show interfaces extensive *",
+			"show interfaces extensive * logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, p.InterfaceDescriptionRegex, collector.OptionsOf[Options](p))
+		},
+	})
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package ipsec
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "ipsec",
+		Feature: "ipsec",
+		Flag:    "ipsec.enabled",
+		Help:    "Scrape IPSec metrics",
+		Commands: []string{
+			"show security ipsec security-associations",
+			"show configuration security ipsec",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
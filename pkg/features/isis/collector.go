@@ -57,11 +57,16 @@ func init() {
 }
 
 type isisCollector struct {
+	LogicalSystem   string
+	RoutingInstance string
 }
 
 // NewCollector creates a new collector
-func NewCollector() collector.RPCCollector {
-	return new(isisCollector)
+func NewCollector(logicalSystem, routingInstance string) collector.RPCCollector {
+	return &isisCollector{
+		LogicalSystem:   logicalSystem,
+		RoutingInstance: routingInstance,
+	}
 }
 
 // Name returns the name of the collector
@@ -120,21 +125,21 @@ func (c *isisCollector) Collect(client collector.Client, ch chan<- prometheus.Me
 	}
 
 	var ifas interfaces
-	err = client.RunCommandAndParse("show isis interface extensive", &ifas)
+	err = client.RunCommandAndParse(c.command("show isis interface extensive"), &ifas)
 	if err != nil {
 		return fmt.Errorf("failed to run command 'show isis interface extensive': %w", err)
 	}
 	c.isisInterfaces(ifas, ch, labelValues)
 
 	var coverage backupCoverage
-	err = client.RunCommandAndParse("show isis backup coverage", &coverage)
+	err = client.RunCommandAndParse(c.command("show isis backup coverage"), &coverage)
 	if err != nil {
 		return fmt.Errorf("failed to run command 'show isis backup coverage': %w", err)
 	}
 	c.isisBackupCoverage(coverage, ch, labelValues)
 
 	var backupPath backupSPF
-	err = client.RunCommandAndParse("show isis backup spf results", &backupPath)
+	err = client.RunCommandAndParse(c.command("show isis backup spf results"), &backupPath)
 	if err != nil {
 		return fmt.Errorf("failed to run command 'show isis backup spf results': %w", err)
 	}
@@ -147,7 +152,7 @@ func (c *isisCollector) isisAdjancies(client collector.Client) (*adjacencies, er
 	total := 0
 
 	var x result
-	err := client.RunCommandAndParse("show isis adjacency", &x)
+	err := client.RunCommandAndParse(c.command("show isis adjacency"), &x)
 	if err != nil {
 		return nil, err
 	}
@@ -238,3 +243,15 @@ func percentageToFloat64(percentageStr string) float64 {
 
 	return value
 }
+
+func (c *isisCollector) command(cmd string) string {
+	if c.RoutingInstance != "" {
+		cmd += " instance " + c.RoutingInstance
+	}
+
+	if c.LogicalSystem != "" {
+		cmd += " logical-system " + c.LogicalSystem
+	}
+
+	return cmd
+}
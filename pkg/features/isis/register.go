@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: MIT
+
+package isis
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:              "isis",
+		Feature:          "isis",
+		Flag:             "isis.enabled",
+		Help:             "Scrape ISIS metrics",
+		DefaultEnabled:   true,
+		LogicalSystems:   true,
+		RoutingInstances: true,
+		Commands: []string{
+			"show isis adjacency",
+			"show isis adjacency instance *",
+			"show isis adjacency logical-system *",
+			"show isis interface extensive",
+			"show isis interface extensive instance *",
+			"show isis interface extensive logical-system *",
+			"show isis backup coverage",
+			"show isis backup coverage instance *",
+			"show isis backup coverage logical-system *",
+			"show isis backup spf results",
+			"show isis backup spf results instance *",
+			"show isis backup spf results logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, p.RoutingInstance)
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package krt
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "krt",
+		Feature: "krt",
+		Flag:    "krt.enabled",
+		Help:    "Scrape KRT queue metrics",
+		Commands: []string{
+			"show krt queue",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
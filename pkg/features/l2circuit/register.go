@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package l2circuit
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "l2c",
+		Feature: "l2circuit",
+		Flag:    "l2circuit.enabled",
+		Help:    "Scrape l2circuit metrics",
+		Commands: []string{
+			"show l2circuit connections brief",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package l2vpn
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "l2vpn",
+		Feature: "l2vpn",
+		Flag:    "l2vpn.enabled",
+		Help:    "Scrape l2vpn metrics",
+		Commands: []string{
+			"show l2vpn connections",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package lacp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "lacp",
+		Feature: "lacp",
+		Flag:    "lacp.enabled",
+		Help:    "Scrape LACP metrics",
+		Commands: []string{
+			"show lacp interfaces",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
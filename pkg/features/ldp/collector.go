@@ -32,11 +32,16 @@ func init() {
 
 // Collector collects ldpv3 metrics
 type ldpCollector struct {
+	LogicalSystem   string
+	RoutingInstance string
 }
 
 // NewCollector creates a new collector
-func NewCollector() collector.RPCCollector {
-	return new(ldpCollector)
+func NewCollector(logicalSystem, routingInstance string) collector.RPCCollector {
+	return &ldpCollector{
+		LogicalSystem:   logicalSystem,
+		RoutingInstance: routingInstance,
+	}
 }
 
 // Name returns the name of the collector
@@ -63,7 +68,7 @@ func (c *ldpCollector) Collect(client collector.Client, ch chan<- prometheus.Met
 
 func (c *ldpCollector) collectLDPMetrics(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
 	var x = result{}
-	err := client.RunCommandAndParse("show ldp neighbor", &x)
+	err := client.RunCommandAndParse(c.command("show ldp neighbor"), &x)
 	if err != nil {
 		return err
 	}
@@ -76,7 +81,7 @@ func (c *ldpCollector) collectLDPMetrics(client collector.Client, ch chan<- prom
 
 func (c *ldpCollector) collectLDPSessions(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
 	var x = sessionResult{}
-	err := client.RunCommandAndParse("show ldp session", &x)
+	err := client.RunCommandAndParse(c.command("show ldp session"), &x)
 	if err != nil {
 		return err
 	}
@@ -92,3 +97,15 @@ func (c *ldpCollector) collectLDPSessions(client collector.Client, ch chan<- pro
 
 	return nil
 }
+
+func (c *ldpCollector) command(cmd string) string {
+	if c.RoutingInstance != "" {
+		cmd += " instance " + c.RoutingInstance
+	}
+
+	if c.LogicalSystem != "" {
+		cmd += " logical-system " + c.LogicalSystem
+	}
+
+	return cmd
+}
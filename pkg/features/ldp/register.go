@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package ldp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:              "ldp",
+		Feature:          "ldp",
+		Flag:             "ldp.enabled",
+		Help:             "Scrape ldp metrics",
+		DefaultEnabled:   true,
+		LogicalSystems:   true,
+		RoutingInstances: true,
+		Commands: []string{
+			"show ldp neighbor",
+			"show ldp neighbor instance *",
+			"show ldp neighbor logical-system *",
+			"show ldp session",
+			"show ldp session instance *",
+			"show ldp session logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, p.RoutingInstance)
+		},
+	})
+}
@@ -38,16 +38,7 @@ func (*lldpCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect collects metrics from JunOS
 func (c *lldpCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-	// Get LLDP neighbors (active connections)
-	var neighborResult = result{}
-	err := client.RunCommandAndParse("show lldp neighbors", &neighborResult)
-	if err != nil {
-		return err
-	}
-
-	// Get LLDP local information (all LLDP-enabled interfaces)
-	var localResult = localResult{}
-	err = client.RunCommandAndParse("show lldp local-information", &localResult)
+	neighborResult, localResult, err := c.results(client)
 	if err != nil {
 		return err
 	}
@@ -102,3 +93,21 @@ func (c *lldpCollector) Collect(client collector.Client, ch chan<- prometheus.Me
 
 	return nil
 }
+
+func (c *lldpCollector) results(client collector.Client) (*result, *localResult, error) {
+	// Get LLDP neighbors (active connections)
+	var neighborResult = result{}
+	err := client.RunCommandAndParse("show lldp neighbors", &neighborResult)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get LLDP local information (all LLDP-enabled interfaces)
+	var localResult = localResult{}
+	err = client.RunCommandAndParse("show lldp local-information", &localResult)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &neighborResult, &localResult, nil
+}
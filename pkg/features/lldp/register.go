@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package lldp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "lldp",
+		Feature: "lldp",
+		Flag:    "lldp.enabled",
+		Help:    "Scrape LLDP metrics",
+		Commands: []string{
+			"show lldp neighbors",
+			"show lldp local-information",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
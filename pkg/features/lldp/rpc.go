@@ -2,6 +2,8 @@
 
 package lldp
 
+// The JSON tags are part of the schema of the API (/api/v1/targets/{target}/lldp)
+
 type result struct {
 	Information struct {
 		Neighbors []neighbor `xml:"lldp-neighbor-information"`
@@ -9,11 +11,11 @@ type result struct {
 }
 
 type neighbor struct {
-	LocalPortID              string `xml:"lldp-local-port-id"`
-	LocalParentInterfaceName string `xml:"lldp-local-parent-interface-name"`
-	RemoteChassisID          string `xml:"lldp-remote-chassis-id"`
-	RemotePortID             string `xml:"lldp-remote-port-id"`
-	RemoteSystemName         string `xml:"lldp-remote-system-name"`
+	LocalPortID              string `xml:"lldp-local-port-id" json:"local_port"`
+	LocalParentInterfaceName string `xml:"lldp-local-parent-interface-name" json:"local_parent_interface"`
+	RemoteChassisID          string `xml:"lldp-remote-chassis-id" json:"remote_chassis_id"`
+	RemotePortID             string `xml:"lldp-remote-port-id" json:"remote_port"`
+	RemoteSystemName         string `xml:"lldp-remote-system-name" json:"remote_system_name"`
 }
 
 type localResult struct {
@@ -23,9 +25,9 @@ type localResult struct {
 }
 
 type localInterface struct {
-	InterfaceName        string `xml:"lldp-local-interface-name"`
-	ParentInterfaceName  string `xml:"lldp-parent-local-interface-name"`
-	InterfaceID          string `xml:"lldp-local-interface-id"`
-	InterfaceDescription string `xml:"lldp-local-interface-description"`
-	InterfaceStatus      string `xml:"lldp-local-interface-status"`
+	InterfaceName        string `xml:"lldp-local-interface-name" json:"interface"`
+	ParentInterfaceName  string `xml:"lldp-parent-local-interface-name" json:"parent_interface"`
+	InterfaceID          string `xml:"lldp-local-interface-id" json:"interface_id"`
+	InterfaceDescription string `xml:"lldp-local-interface-description" json:"description"`
+	InterfaceStatus      string `xml:"lldp-local-interface-status" json:"status"`
 }
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: MIT
+
+package lldp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+type state struct {
+	Neighbors       []neighbor       `json:"neighbors"`
+	LocalInterfaces []localInterface `json:"local_interfaces"`
+}
+
+// State returns the LLDP neighbors and the LLDP enabled local interfaces
+func (c *lldpCollector) State(client collector.Client) (any, error) {
+	neighbors, local, err := c.results(client)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &state{
+		Neighbors:       neighbors.Information.Neighbors,
+		LocalInterfaces: local.Information.LocalInterfaces,
+	}
+
+	if s.Neighbors == nil {
+		s.Neighbors = []neighbor{}
+	}
+
+	if s.LocalInterfaces == nil {
+		s.LocalInterfaces = []localInterface{}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package mac
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "mac",
+		Feature: "mac",
+		Flag:    "mac.enabled",
+		Help:    "Scrape MAC address table metrics",
+		Commands: []string{
+			"show ethernet-switching table summary",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package macsec
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "macsec",
+		Feature:        "macsec",
+		Flag:           "macsec.enabled",
+		Help:           "Scrape MACSec metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show security macsec connections",
+			"show security macsec statistics",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -95,7 +95,7 @@ func TestCollectDefaultGroupReusesDetailResponse(t *testing.T) {
 		},
 	}
 
-	col := NewCollector(nil).(*mnhaCollector) // nil -> defaults to [0]
+	col := NewCollector(Options{}).(*mnhaCollector) // nil -> defaults to [0]
 	ch := make(chan prometheus.Metric, 64)
 
 	err := col.Collect(client, ch, []string{"srx1"})
@@ -120,7 +120,7 @@ func TestCollectMultipleGroupsQueriesOnlyNonDefault(t *testing.T) {
 		},
 	}
 
-	col := NewCollector([]int{0, 1}).(*mnhaCollector)
+	col := NewCollector(Options{SRGIDs: []int{0, 1}}).(*mnhaCollector)
 	ch := make(chan prometheus.Metric, 64)
 
 	err := col.Collect(client, ch, []string{"srx1"})
@@ -152,7 +152,7 @@ func TestCollectFallsBackToExplicitQueryWhenDetailFails(t *testing.T) {
 		},
 	}
 
-	col := NewCollector(nil).(*mnhaCollector)
+	col := NewCollector(Options{}).(*mnhaCollector)
 	ch := make(chan prometheus.Metric, 64)
 
 	err := col.Collect(client, ch, []string{"srx1"})
@@ -173,7 +173,7 @@ func TestCollectJoinsErrorsFromBothCalls(t *testing.T) {
 		},
 	}
 
-	col := NewCollector(nil).(*mnhaCollector)
+	col := NewCollector(Options{}).(*mnhaCollector)
 	ch := make(chan prometheus.Metric, 64)
 
 	err := col.Collect(client, ch, []string{"srx1"})
@@ -88,11 +88,18 @@ type mnhaCollector struct {
 	srgIDs []int
 }
 
+// Options are the options of the collector
+type Options struct {
+	// SRGIDs are the services-redundancy-groups polled individually
+	SRGIDs []int `yaml:"srg_ids,omitempty"`
+}
+
 // NewCollector creates a new collector for MNHA (Mixed/Multi-Node High Availability).
-// srgIDs configures which services-redundancy-groups are polled individually
+// opts.SRGIDs configures which services-redundancy-groups are polled individually
 // via "show chassis high-availability services-redundancy-group <id>".
 // If empty, group 0 (the default group) is used.
-func NewCollector(srgIDs []int) collector.RPCCollector {
+func NewCollector(opts Options) collector.RPCCollector {
+	srgIDs := opts.SRGIDs
 	if len(srgIDs) == 0 {
 		srgIDs = []int{0}
 	}
@@ -284,3 +291,25 @@ func parseDetectTimeMs(s string) (float64, bool) {
 
 	return count * interval, true
 }
+
+// ParseSRGIDs parses a comma-separated list of services-redundancy-group
+// IDs (e.g. "0,1,2"). Non-numeric entries are ignored.
+func ParseSRGIDs(s string) []int {
+	ids := make([]int, 0)
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package mnha
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "mnha",
+		Feature: "mnha",
+		Flag:    "mnha.enabled",
+		Help:    "Scrape MNHA (Mixed/Multi-Node High Availability) metrics",
+		Commands: []string{
+			"show chassis high-availability information detail",
+			"show chassis high-availability services-redundancy-group *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(collector.OptionsOf[Options](p))
+		},
+	})
+}
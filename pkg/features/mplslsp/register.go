@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package mplslsp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "mpls_lsp",
+		Feature: "mpls_lsp",
+		Flag:    "mpls_lsp.enabled",
+		Help:    "Scrape MPLS LSP metrics",
+		Commands: []string{
+			"show mpls lsp ingress extensive",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: MIT
+
+package nat
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "nat",
+		Feature: "nat",
+		Flag:    "nat.enabled",
+		Help:    "Scrape NAT metrics",
+		Commands: []string{
+			"show services nat statistics",
+			"show services nat pool",
+			"show services nat pool detail",
+			"show services service-sets cpu-usage",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p)
+		},
+	})
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package nat2
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "nat2",
+		Feature: "nat2",
+		Flag:    "nat2.enabled",
+		Help:    "Scrape NAT2 metrics",
+		Commands: []string{
+			"show services nat statistics",
+			"show services nat source pool all",
+			"show services service-sets cpu-usage",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
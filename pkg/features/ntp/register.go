@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package ntp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "ntp",
+		Feature: "ntp",
+		Flag:    "ntp.enabled",
+		Help:    "Scrape NTP metrics",
+		Commands: []string{
+			"show ntp status",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -31,12 +31,16 @@ func init() {
 
 // Collector collects OSPFv3 metrics
 type ospfCollector struct {
-	LogicalSystem string
+	LogicalSystem   string
+	RoutingInstance string
 }
 
 // NewCollector creates a new collector
-func NewCollector(logicalSystem string) collector.RPCCollector {
-	return &ospfCollector{LogicalSystem: logicalSystem}
+func NewCollector(logicalSystem, routingInstance string) collector.RPCCollector {
+	return &ospfCollector{
+		LogicalSystem:   logicalSystem,
+		RoutingInstance: routingInstance,
+	}
 }
 
 // Name returns the name of the collector
@@ -66,6 +70,9 @@ func (c *ospfCollector) collectOSPFMetrics(client collector.Client, ch chan<- pr
 	var x = v2Result{}
 	var cmd strings.Builder
 	cmd.WriteString("show ospf overview")
+	if c.RoutingInstance != "" {
+		cmd.WriteString(" instance " + c.RoutingInstance)
+	}
 	if c.LogicalSystem != "" {
 		cmd.WriteString(" logical-system " + c.LogicalSystem)
 	}
@@ -96,6 +103,9 @@ func (c *ospfCollector) collectOSPFv3Metrics(client collector.Client, ch chan<-
 	var x = v3Result{}
 	var cmd strings.Builder
 	cmd.WriteString("show ospf3 overview")
+	if c.RoutingInstance != "" {
+		cmd.WriteString(" instance " + c.RoutingInstance)
+	}
 	if c.LogicalSystem != "" {
 		cmd.WriteString(" logical-system " + c.LogicalSystem)
 	}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package ospf
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:              "ospf",
+		Feature:          "ospf",
+		Flag:             "ospf.enabled",
+		Help:             "Scrape OSPFv3 metrics",
+		DefaultEnabled:   true,
+		LogicalSystems:   true,
+		RoutingInstances: true,
+		Commands: []string{
+			"show ospf overview",
+			"show ospf overview instance *",
+			"show ospf overview logical-system *",
+			"show ospf3 overview",
+			"show ospf3 overview instance *",
+			"show ospf3 overview logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, p.RoutingInstance)
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package poe
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "poe",
+		Feature: "poe",
+		Flag:    "poe.enabled",
+		Help:    "Scrape PoE metrics",
+		Commands: []string{
+			"show poe interface",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+
+package power
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "power",
+		Feature: "power",
+		Flag:    "power.enabled",
+		Help:    "Scrape power metrics",
+		Commands: []string{
+			"show chassis hardware",
+			"show chassis power",
+			"show chassis power-budget-statistics",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
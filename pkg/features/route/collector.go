@@ -3,11 +3,18 @@
 package route
 
 import (
+	"strings"
+
 	"github.com/czerwonk/junos_exporter/pkg/collector"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-const prefix string = "junos_routes_"
+const (
+	prefix string = "junos_routes_"
+
+	// defaultRoutingInstance is the name of the default routing instance
+	defaultRoutingInstance = "master"
+)
 
 var (
 	totalRoutesDesc      *prometheus.Desc
@@ -29,6 +36,8 @@ func init() {
 }
 
 type routeCollector struct {
+	LogicalSystem   string
+	RoutingInstance string
 }
 
 // Name returns the name of the collector
@@ -37,8 +46,11 @@ func (*routeCollector) Name() string {
 }
 
 // NewCollector creates a new collector
-func NewCollector() collector.RPCCollector {
-	return new(routeCollector)
+func NewCollector(logicalSystem, routingInstance string) collector.RPCCollector {
+	return &routeCollector{
+		LogicalSystem:   logicalSystem,
+		RoutingInstance: routingInstance,
+	}
 }
 
 // Describe describes the metrics
@@ -53,7 +65,23 @@ func (*routeCollector) Describe(ch chan<- *prometheus.Desc) {
 // Collect collects metrics from JunOS
 func (c *routeCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
 	var x = result{}
-	err := client.RunCommandAndParse("show route summary", &x)
+	var cmd strings.Builder
+	cmd.WriteString("show route summary")
+	switch c.RoutingInstance {
+	case "":
+	case defaultRoutingInstance:
+		// the tables of the default instance have no instance prefix, this selects inet.0, inet6.0 etc.
+		// without the tables of the other instances
+		cmd.WriteString(" table inet")
+	default:
+		// table names are matched by prefix, so this selects all tables of the instance (e.g. <instance>.inet.0, <instance>.inet6.0)
+		cmd.WriteString(" table " + c.RoutingInstance + ".")
+	}
+	if c.LogicalSystem != "" {
+		cmd.WriteString(" logical-system " + c.LogicalSystem)
+	}
+
+	err := client.RunCommandAndParse(cmd.String(), &x)
 	if err != nil {
 		return err
 	}
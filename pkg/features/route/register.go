@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+
+package route
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:              "routes",
+		Feature:          "routes",
+		Flag:             "routes.enabled",
+		Help:             "Scrape routing table metrics",
+		DefaultEnabled:   true,
+		LogicalSystems:   true,
+		RoutingInstances: true,
+		Commands: []string{
+			"show route summary",
+			"show route summary table *",
+			"show route summary logical-system *",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p.LogicalSystem, p.RoutingInstance)
+		},
+	})
+}
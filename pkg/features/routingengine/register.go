@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package routingengine
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "routingengine",
+		Feature:        "routing_engine",
+		Flag:           "routingengine.enabled",
+		Help:           "Scrape Routing Engine metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show chassis routing-engine",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+
+package routinginstance
+
+import (
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix = "junos_routing_instance_"
+
+var (
+	infoDesc           *prometheus.Desc
+	interfaceCountDesc *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target", "name"}
+	interfaceCountDesc = prometheus.NewDesc(prefix+"interface_count", "Number of interfaces assigned to the routing instance", l, nil)
+
+	l = append(l, "type", "state")
+	infoDesc = prometheus.NewDesc(prefix+"info", "Information about the routing instance (type and state)", l, nil)
+}
+
+type routingInstanceCollector struct {
+}
+
+// NewCollector creates a new collector
+func NewCollector() collector.RPCCollector {
+	return new(routingInstanceCollector)
+}
+
+// Name returns the name of the collector
+func (*routingInstanceCollector) Name() string {
+	return "Routing Instances"
+}
+
+// Describe describes the metrics
+func (*routingInstanceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- infoDesc
+	ch <- interfaceCountDesc
+}
+
+// Collect collects metrics from JunOS
+func (c *routingInstanceCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	var x = result{}
+	err := client.RunCommandAndParse("show route instance detail", &x)
+	if err != nil {
+		return err
+	}
+
+	for _, inst := range x.Information.Instances {
+		l := append(labelValues, inst.Name)
+		ch <- prometheus.MustNewConstMetric(interfaceCountDesc, prometheus.GaugeValue, float64(len(inst.Interfaces)), l...)
+
+		l = append(l, inst.Type, inst.State)
+		ch <- prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, l...)
+	}
+
+	return nil
+}
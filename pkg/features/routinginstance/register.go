@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package routinginstance
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "routing_instance",
+		Feature: "routing_instance",
+		Flag:    "routing_instance.enabled",
+		Help:    "Scrape routing instance metrics",
+		Commands: []string{
+			"show route instance detail",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
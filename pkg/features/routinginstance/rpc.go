@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: MIT
+
+package routinginstance
+
+type result struct {
+	Information struct {
+		Instances []instance `xml:"instance-core"`
+	} `xml:"instance-information"`
+}
+
+type instance struct {
+	Name       string `xml:"instance-name"`
+	Type       string `xml:"instance-type"`
+	State      string `xml:"instance-state"`
+	Interfaces []struct {
+		Name string `xml:"interface-name"`
+	} `xml:"instance-interface"`
+}
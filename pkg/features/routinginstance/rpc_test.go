@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+
+package routinginstance
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInstanceDetail(t *testing.T) {
+	body := `
+<rpc-reply xmlns:junos="http://xml.juniper.net/junos/23.4R1/junos">
+    <instance-information xmlns="http://xml.juniper.net/junos/23.4R1/junos-routing" junos:style="detail">
+        <instance-core>
+            <instance-name>master</instance-name>
+            <router-id>192.0.2.1</router-id>
+            <instance-type>forwarding</instance-type>
+            <instance-state>Active</instance-state>
+        </instance-core>
+        <instance-core>
+            <instance-name>CUSTOMER-A</instance-name>
+            <router-id>192.0.2.1</router-id>
+            <instance-type>vrf</instance-type>
+            <instance-state>Active</instance-state>
+            <instance-interface>
+                <interface-name>ge-0/0/1.100</interface-name>
+            </instance-interface>
+            <instance-interface>
+                <interface-name>lo0.100</interface-name>
+            </instance-interface>
+            <instance-route-distinguisher>
+                <route-distinguisher>192.0.2.1:100</route-distinguisher>
+            </instance-route-distinguisher>
+        </instance-core>
+    </instance-information>
+</rpc-reply>`
+
+	var x result
+	err := xml.Unmarshal([]byte(body), &x)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(x.Information.Instances), "instance count")
+
+	i := x.Information.Instances[1]
+	assert.Equal(t, "CUSTOMER-A", i.Name)
+	assert.Equal(t, "vrf", i.Type)
+	assert.Equal(t, "Active", i.State)
+	assert.Equal(t, 2, len(i.Interfaces), "interface count")
+}
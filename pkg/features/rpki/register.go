@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package rpki
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "rpki",
+		Feature: "rpki",
+		Flag:    "rpki.enabled",
+		Help:    "Scrape rpki metrics",
+		Commands: []string{
+			"show validation session",
+			"show validation statistics",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package rpm
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "rpm",
+		Feature: "rpm",
+		Flag:    "rpm.enabled",
+		Help:    "Scrape RPM metrics",
+		Commands: []string{
+			"show services rpm probe-results",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
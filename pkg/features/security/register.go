@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package security
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "security",
+		Feature: "security",
+		Flag:    "security.enabled",
+		Help:    "Scrape security metrics",
+		Commands: []string{
+			"show security monitoring",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package securityike
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "security_ike",
+		Feature: "security_ike",
+		Flag:    "security_ike.enabled",
+		Help:    "Scrape security IKE metrics",
+		Commands: []string{
+			"show security ike active-peer",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package securitypolicies
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "security_policies",
+		Feature: "security_policies",
+		Flag:    "security_policies.enabled",
+		Help:    "Scrape security policy metrics",
+		Commands: []string{
+			"show security policies detail",
+			"show security policies hit-count",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
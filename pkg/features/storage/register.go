@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "storage",
+		Feature: "storage",
+		Flag:    "storage.enabled",
+		Help:    "Scrape system storage metrics",
+		Commands: []string{
+			"show system storage",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -20,23 +20,31 @@ func init() {
 	subscriberInfo = prometheus.NewDesc(prefix+"", "Subscriber Detail", l, nil)
 }
 
+type subscriberCollector struct {
+	params *collector.Params
+}
+
 // Name implements collector.RPCCollector.
-func (*subcsribers_information) Name() string {
+func (*subscriberCollector) Name() string {
 	return "Subscriber Detail"
 }
 
 // NewCollector creates a new collector
-func NewCollector() collector.RPCCollector {
-	return new(subcsribers_information)
+func NewCollector(p *collector.Params) collector.RPCCollector {
+	return &subscriberCollector{params: p}
 }
 
 // Describe describes the metrics
-func (*subcsribers_information) Describe(ch chan<- *prometheus.Desc) {
+func (*subscriberCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- subscriberInfo
 }
 
-// Collect collects metrics from JunOS
-func (c *subcsribers_information) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+// Collect collects metrics from JunOS. Nothing is collected if the metric is dropped by the relabel rules.
+func (c *subscriberCollector) Collect(client collector.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	if c.params.Dropped(prefix) {
+		return nil
+	}
+
 	var x = subcsribers_information{}
 	err := client.RunCommandAndParse("show subscribers client-type dhcp detail", &x) //TODO: see if client-type dhcp can be left out
 	if err != nil {
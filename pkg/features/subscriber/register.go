@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package subscriber
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "subscriber",
+		Feature: "subscriber",
+		Flag:    "subscriber.enabled",
+		Help:    "Scrape subscribers detail",
+		Commands: []string{
+			"show subscribers client-type dhcp detail",
+			"show interfaces demux0 brief",
+		},
+		New: func(p *collector.Params) collector.RPCCollector {
+			return NewCollector(p)
+		},
+	})
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+
+package system
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:       "system",
+		Feature:   "system",
+		Flag:      "system.enabled",
+		Help:      "Scrape system metrics",
+		EnabledBy: []string{"license"},
+		Commands: []string{
+			"show system information",
+			"show system buffers",
+			"show system commit",
+			"show system license usage",
+			"show chassis satellite detail",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+
+	// license and satellite have no collector of their own, they enable additional RPCs of the client
+	collector.Register(collector.Registration{
+		Key:     "license",
+		Feature: "license",
+		Flag:    "license.enabled",
+		Help:    "Scrape license metrics",
+	})
+	collector.Register(collector.Registration{
+		Key:     "satellite",
+		Feature: "satellite",
+		Flag:    "satellite.enabled",
+		Help:    "Scrape metrics from satellite devices",
+	})
+}
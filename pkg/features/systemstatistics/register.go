@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+
+package systemstatistics
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:            "system_statistics",
+		Feature:        "system_statistics",
+		Flag:           "systemstatistics.enabled",
+		Help:           "Scrape system statistics metrics",
+		DefaultEnabled: true,
+		Commands: []string{
+			"show system statistics ip",
+			"show system statistics ip6",
+			"show system statistics udp",
+			"show system statistics tcp",
+			"show system statistics arp",
+			"show system statistics icmp",
+			"show system statistics icmp6",
+			"show system statistics mpls",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
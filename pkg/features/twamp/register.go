@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package twamp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "twamp",
+		Feature: "twamp",
+		Flag:    "twamp.enabled",
+		Help:    "Scrape TWAMP metrics",
+		Commands: []string{
+			"show services monitoring twamp client probe-results",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
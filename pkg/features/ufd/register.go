@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package ufd
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "ufd",
+		Feature: "ufd",
+		Flag:    "ufd.enabled",
+		Help:    "Scrape UFD (uplink-failure-detection) metrics",
+		Commands: []string{
+			"show uplink-failure-detection",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
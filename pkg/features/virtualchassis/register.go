@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+
+package virtualchassis
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "virtual_chassis",
+		Feature: "virtual_chassis",
+		Flag:    "virtual_chassis.enabled",
+		Help:    "Scrape virtual chassis metrics",
+		Commands: []string{
+			"show virtual-chassis status",
+			"show virtual-chassis vc-port all-members",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
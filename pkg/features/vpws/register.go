@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package vpws
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "vpws",
+		Feature: "vpws",
+		Flag:    "vpws.enabled",
+		Help:    "Scrape EVPN VPWS metrics",
+		Commands: []string{
+			"show evpn vpws-instance",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+
+package vrrp
+
+import "github.com/czerwonk/junos_exporter/pkg/collector"
+
+func init() {
+	collector.Register(collector.Registration{
+		Key:     "vrrp",
+		Feature: "vrrp",
+		Flag:    "vrrp.enabled",
+		Help:    "Scrape VRRP metrics",
+		Commands: []string{
+			"show vrrp summary",
+		},
+		New: func(*collector.Params) collector.RPCCollector {
+			return NewCollector()
+		},
+	})
+}
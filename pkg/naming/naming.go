@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+
+// Package naming translates the metrics of the exporter to the v2 naming
+// scheme. In contrast to v1, monotonic values are reported as counters with
+// the suffix _total, gauges do not use the suffixes _count and _total and
+// metrics are named with their unit (e.g. _celsius, _volts).
+//
+// v2 is opt-in. During the deprecation window of the v1 names both schemes can
+// be reported at the same time (Mode Both).
+package naming
+
+//go:generate go run ../../internal/metricnames/gen -features ../features -out ../../docs/metric-names-v2.md
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Mode is the naming scheme of the reported metrics
+type Mode int
+
+const (
+	// V1 reports the metrics with the names and types of the v1 scheme (default)
+	V1 Mode = iota
+
+	// V2 reports the metrics with the names and types of the v2 scheme
+	V2
+
+	// Both reports the metrics of the v1 and the v2 scheme. Metrics whose
+	// v2 name equals the v1 name are reported with their v1 type.
+	Both
+)
+
+// ParseMode parses the name of a mode (v1, v2 or both)
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "v1", "":
+		return V1, nil
+	case "v2":
+		return V2, nil
+	case "both":
+		return Both, nil
+	default:
+		return V1, fmt.Errorf("invalid metric naming %q (valid: v1, v2, both)", s)
+	}
+}
+
+// String implements fmt.Stringer and flag.Value interface
+func (m Mode) String() string {
+	switch m {
+	case V2:
+		return "v2"
+	case Both:
+		return "both"
+	default:
+		return "v1"
+	}
+}
+
+// Set implements flag.Value interface
+func (m *Mode) Set(s string) error {
+	v, err := ParseMode(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+// Name returns the v2 name and type of a metric reported as name with type t in the v1 scheme
+func Name(name string, t dto.MetricType) (string, dto.MetricType) {
+	if t != dto.MetricType_COUNTER && t != dto.MetricType_GAUGE {
+		return name, t
+	}
+
+	if gauges[name] {
+		t = dto.MetricType_GAUGE
+	} else if isCounter(name) {
+		t = dto.MetricType_COUNTER
+	}
+
+	if n, found := names[name]; found {
+		return n, t
+	}
+
+	if t == dto.MetricType_COUNTER {
+		return counterName(name), t
+	}
+
+	return strings.TrimSuffix(name, "_count"), t
+}
+
+// Names returns the names a metric reported as name in the v1 scheme is
+// reported with in mode m. As the type of the metric is not known, both the
+// name as counter and as gauge are returned for v2.
+func Names(name string, m Mode) []string {
+	if m == V1 {
+		return []string{name}
+	}
+
+	names := make([]string, 0, 3)
+	if m == Both {
+		names = append(names, name)
+	}
+
+	for _, t := range []dto.MetricType{dto.MetricType_COUNTER, dto.MetricType_GAUGE} {
+		n, _ := Name(name, t)
+		if !slices.Contains(names, n) {
+			names = append(names, n)
+		}
+	}
+
+	return names
+}
+
+func isCounter(name string) bool {
+	if counters[name] {
+		return true
+	}
+
+	for _, p := range counterPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func counterName(name string) string {
+	if strings.HasSuffix(name, "_total") {
+		return name
+	}
+
+	for _, s := range []string{"_count", "_cnt", "_counter"} {
+		if n, found := strings.CutSuffix(name, s); found {
+			name = n
+			break
+		}
+	}
+
+	return name + "_total"
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: MIT
+
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name         string
+		typ          dto.MetricType
+		expectedName string
+		expectedType dto.MetricType
+	}{
+		{"junos_interface_receive_bytes", dto.MetricType_COUNTER, "junos_interface_receive_bytes_total", dto.MetricType_COUNTER},
+		{"junos_interface_receive_packets_total", dto.MetricType_COUNTER, "junos_interface_receive_packets_total", dto.MetricType_COUNTER},
+		{"junos_interface_queues_drop_packets_count", dto.MetricType_COUNTER, "junos_interface_queues_drop_packets_total", dto.MetricType_COUNTER},
+		{"junos_bgp_session_messages_input_count", dto.MetricType_GAUGE, "junos_bgp_session_messages_input_total", dto.MetricType_COUNTER},
+		{"junos_bgp_session_flap_count", dto.MetricType_GAUGE, "junos_bgp_session_flaps_total", dto.MetricType_COUNTER},
+		{"junos_bgp_session_prefixes_received_count", dto.MetricType_GAUGE, "junos_bgp_session_prefixes_received", dto.MetricType_GAUGE},
+		{"junos_nat_statistics_nat_total_pkts_processed", dto.MetricType_GAUGE, "junos_nat_statistics_nat_total_pkts_processed_total", dto.MetricType_COUNTER},
+		{"junos_route_engine_uptime_seconds", dto.MetricType_COUNTER, "junos_route_engine_uptime_seconds", dto.MetricType_GAUGE},
+		{"junos_route_engine_temp", dto.MetricType_GAUGE, "junos_route_engine_temperature_celsius", dto.MetricType_GAUGE},
+		{"junos_interface_diagnostics_laser_bias_low_warn_threshold", dto.MetricType_GAUGE, "junos_interface_diagnostics_laser_bias_low_warn_threshold_milliamperes", dto.MetricType_GAUGE},
+		{"junos_interface_up", dto.MetricType_GAUGE, "junos_interface_up", dto.MetricType_GAUGE},
+		{"junos_interface_up", dto.MetricType_UNTYPED, "junos_interface_up", dto.MetricType_UNTYPED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, typ := Name(test.name, test.typ)
+			assert.Equal(t, test.expectedName, name)
+			assert.Equal(t, test.expectedType, typ)
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, []string{"junos_route_engine_temp"}, Names("junos_route_engine_temp", V1))
+	assert.Equal(t, []string{"junos_route_engine_temperature_celsius"}, Names("junos_route_engine_temp", V2))
+	assert.Equal(t, []string{"junos_route_engine_temp", "junos_route_engine_temperature_celsius"}, Names("junos_route_engine_temp", Both))
+	assert.Equal(t, []string{"junos_foo_total", "junos_foo"}, Names("junos_foo_count", V2), "unknown type")
+}
+
+func TestParseMode(t *testing.T) {
+	for s, expected := range map[string]Mode{"": V1, "v1": V1, "V2": V2, "both": Both} {
+		m, err := ParseMode(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, m, s)
+	}
+
+	_, err := ParseMode("v3")
+	assert.Error(t, err)
+}
+
+func testCollector() prometheus.Collector {
+	bytes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "junos_interface_receive_bytes", Help: "Received data in bytes"}, []string{"target"})
+	bytes.WithLabelValues("router1").Add(42)
+
+	flaps := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "junos_bgp_session_flap_count", Help: "Number of session flaps"}, []string{"target"})
+	flaps.WithLabelValues("router1").Set(3)
+
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "junos_up", Help: "Scrape of target was successful"}, []string{"target"})
+	up.WithLabelValues("router1").Set(1)
+
+	uptime := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "junos_route_engine_uptime_seconds", Help: "Seconds since boot"}, []string{"target"})
+	uptime.WithLabelValues("router1").Add(100)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(bytes, flaps, up, uptime)
+
+	return collectorFunc(func(ch chan<- prometheus.Metric) {
+		reg.Collect(ch)
+	})
+}
+
+func TestCollector(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     Mode
+		expected string
+	}{
+		{
+			name: "v1",
+			mode: V1,
+			expected: `
+# HELP junos_bgp_session_flap_count Number of session flaps
+# TYPE junos_bgp_session_flap_count gauge
+junos_bgp_session_flap_count{target="router1"} 3
+# HELP junos_interface_receive_bytes Received data in bytes
+# TYPE junos_interface_receive_bytes counter
+junos_interface_receive_bytes{target="router1"} 42
+# HELP junos_route_engine_uptime_seconds Seconds since boot
+# TYPE junos_route_engine_uptime_seconds counter
+junos_route_engine_uptime_seconds{target="router1"} 100
+# HELP junos_up Scrape of target was successful
+# TYPE junos_up gauge
+junos_up{target="router1"} 1
+`,
+		},
+		{
+			name: "v2",
+			mode: V2,
+			expected: `
+# HELP junos_bgp_session_flaps_total Number of session flaps
+# TYPE junos_bgp_session_flaps_total counter
+junos_bgp_session_flaps_total{target="router1"} 3
+# HELP junos_interface_receive_bytes_total Received data in bytes
+# TYPE junos_interface_receive_bytes_total counter
+junos_interface_receive_bytes_total{target="router1"} 42
+# HELP junos_route_engine_uptime_seconds Seconds since boot
+# TYPE junos_route_engine_uptime_seconds gauge
+junos_route_engine_uptime_seconds{target="router1"} 100
+# HELP junos_up Scrape of target was successful
+# TYPE junos_up gauge
+junos_up{target="router1"} 1
+`,
+		},
+		{
+			name: "both",
+			mode: Both,
+			expected: `
+# HELP junos_bgp_session_flap_count Number of session flaps
+# TYPE junos_bgp_session_flap_count gauge
+junos_bgp_session_flap_count{target="router1"} 3
+# HELP junos_bgp_session_flaps_total Number of session flaps
+# TYPE junos_bgp_session_flaps_total counter
+junos_bgp_session_flaps_total{target="router1"} 3
+# HELP junos_interface_receive_bytes Received data in bytes
+# TYPE junos_interface_receive_bytes counter
+junos_interface_receive_bytes{target="router1"} 42
+# HELP junos_interface_receive_bytes_total Received data in bytes
+# TYPE junos_interface_receive_bytes_total counter
+junos_interface_receive_bytes_total{target="router1"} 42
+# HELP junos_route_engine_uptime_seconds Seconds since boot
+# TYPE junos_route_engine_uptime_seconds counter
+junos_route_engine_uptime_seconds{target="router1"} 100
+# HELP junos_up Scrape of target was successful
+# TYPE junos_up gauge
+junos_up{target="router1"} 1
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := Collector(testCollector(), test.mode)
+			assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(test.expected)))
+
+			reg := prometheus.NewRegistry()
+			reg.MustRegister(testCollector())
+			assert.NoError(t, testutil.GatherAndCompare(Gatherer(reg, test.mode), strings.NewReader(test.expected)))
+		})
+	}
+}
+
+type collectorFunc func(ch chan<- prometheus.Metric)
+
+func (f collectorFunc) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (f collectorFunc) Collect(ch chan<- prometheus.Metric) {
+	f(ch)
+}
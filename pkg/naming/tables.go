@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: MIT
+
+package naming
+
+// counters are the metrics reported as gauge in the v1 scheme representing monotonic values
+var counters = map[string]bool{
+	"junos_accounting_inline_creation_failure_count":                            true,
+	"junos_accounting_inline_ipv4_creation_failure_count":                       true,
+	"junos_accounting_inline_ipv6_creation_failure_count":                       true,
+	"junos_bgp_session_flap_count":                                              true,
+	"junos_bgp_session_messages_input_count":                                    true,
+	"junos_bgp_session_messages_output_count":                                   true,
+	"junos_firewall_filter_counter_bytes":                                       true,
+	"junos_firewall_filter_counter_packets":                                     true,
+	"junos_firewall_filter_policer_bytes":                                       true,
+	"junos_firewall_filter_policer_packets":                                     true,
+	"junos_mpls_lsp_path_flapcount":                                             true,
+	"junos_nat2_statistics_address_pool_hits":                                   true,
+	"junos_nat2_statistics_app_exceed_port_limit_error":                         true,
+	"junos_nat2_statistics_app_out_of_port_error":                               true,
+	"junos_nat2_statistics_blk_exceed_limit_error":                              true,
+	"junos_nat2_statistics_blk_mem_alloc_error":                                 true,
+	"junos_nat2_statistics_blk_out_of_port_error":                               true,
+	"junos_nat2_statistics_out_of_addr_error":                                   true,
+	"junos_nat2_statistics_out_of_blk_error":                                    true,
+	"junos_nat2_statistics_out_of_port_error":                                   true,
+	"junos_nat2_statistics_parity_port_error":                                   true,
+	"junos_nat2_statistics_preserve_range_error":                                true,
+	"junos_nat2_statistics_session_xlate464_clat_prefix_not_found":              true,
+	"junos_nat2_statistics_session_xlate464_embeded_ipv4_not_found":             true,
+	"junos_nat2_statistics_source_pool_eif_flow_limit_exceed_drops":             true,
+	"junos_nat_statistics_no_sext_in_xlate_pkt":                                 true,
+	"junos_nat_statistics_pool_app_exceed_port_limit_errors":                    true,
+	"junos_nat_statistics_pool_app_port_errors":                                 true,
+	"junos_nat_statistics_pool_block_allocation_errors":                         true,
+	"junos_nat_statistics_pool_blocks_limit_exceeded_errors":                    true,
+	"junos_nat_statistics_pool_eif_inbound_limit_exceed_drop":                   true,
+	"junos_nat_statistics_pool_mem_alloc_errors":                                true,
+	"junos_nat_statistics_pool_out_of_port_errors":                              true,
+	"junos_nat_statistics_pool_parity_port_errors":                              true,
+	"junos_nat_statistics_pool_preserve_range_errors":                           true,
+	"junos_nat_statistics_pool_session_cnt_update_fail_on_close":                true,
+	"junos_nat_statistics_pool_session_cnt_update_fail_on_create":               true,
+	"junos_nat_statistics_total_session_close":                                  true,
+	"junos_rpki_session_flap_count":                                             true,
+	"junos_system_io_requests_count":                                            true,
+	"junos_system_jumbo_clusters_denied_count":                                  true,
+	"junos_system_mbuf_and_clusters_denied_count":                               true,
+	"junos_system_mbufs_and_clusters_denied_count":                              true,
+	"junos_system_mbufs_denied_count":                                           true,
+	"junos_system_sfbufs_delayed_count":                                         true,
+	"junos_system_sfbufs_denied_count":                                          true,
+	"junos_systemstatistics_icmp6_errors_not_generated_because_rate_limitation": true,
+	"junos_systemstatistics_icmp_drops_due_to_rate_limit":                       true,
+	"junos_systemstatistics_ipv4_option_packets_dropped_due_to_rate_limit":      true,
+	"junos_systemstatistics_ipv6_fragments_that_exceeded_limit":                 true,
+	"junos_systemstatistics_ipv6_option_packets_dropped_due_to_rate_limit":      true,
+}
+
+// counterPrefixes are the prefixes of the names of metrics reported as gauge in
+// the v1 scheme representing monotonic values
+var counterPrefixes = []string{
+	"junos_nat_statistics_nat_",
+	"junos_nat_statistics_nat64_",
+	"junos_nat2_statistics_nat_",
+	"junos_nat2_statistics_nat64_",
+}
+
+// gauges are the metrics reported as counter in the v1 scheme representing values which can decrease
+var gauges = map[string]bool{
+	"junos_fpc_uptime_seconds":                   true,
+	"junos_interface_fec_ccw_error_rate":         true,
+	"junos_interface_fec_mode":                   true,
+	"junos_interface_fec_nccw_error_rate":        true,
+	"junos_isis_adjacency_count":                 true,
+	"junos_route_engine_uptime_seconds":          true,
+	"junos_subscriber_info":                      true,
+	"junos_systemstatistics_arp_iri_cnt":         true,
+	"junos_systemstatistics_arp_iri_max":         true,
+	"junos_systemstatistics_arp_mgnt_cnt":        true,
+	"junos_systemstatistics_arp_mgnt_max":        true,
+	"junos_systemstatistics_arp_public_cnt":      true,
+	"junos_systemstatistics_arp_public_max":      true,
+	"junos_systemstatistics_arp_system_max":      true,
+	"junos_systemstatistics_icmp6_nd_iri_cnt":    true,
+	"junos_systemstatistics_icmp6_nd_iri_max":    true,
+	"junos_systemstatistics_icmp6_nd_mgt_cnt":    true,
+	"junos_systemstatistics_icmp6_nd_mgt_max":    true,
+	"junos_systemstatistics_icmp6_nd_public_cnt": true,
+	"junos_systemstatistics_icmp6_nd_public_max": true,
+	"junos_systemstatistics_icmp6_nd_system_max": true,
+}
+
+// names are the v2 names of metrics which can not be derived from the v1 name
+var names = map[string]string{
+	"junos_bgp_session_flap_count":                "junos_bgp_session_flaps_total",
+	"junos_chassis_cluster_failover_count":        "junos_chassis_cluster_failovers_total",
+	"junos_environment_item_temp":                 "junos_environment_item_temperature_celsius",
+	"junos_environment_pem_current":               "junos_environment_pem_current_amperes",
+	"junos_environment_pem_power_usage":           "junos_environment_pem_power_usage_watts",
+	"junos_environment_pem_voltage":               "junos_environment_pem_voltage_volts",
+	"junos_evpn_duplicate_mac_total":              "junos_evpn_duplicate_macs",
+	"junos_fpc_cpu_total":                         "junos_fpc_cpu_utilization_percent",
+	"junos_fpc_max_power_consumption_watt":        "junos_fpc_max_power_consumption_watts",
+	"junos_isis_adjacency_count":                  "junos_isis_interface_adjacencies",
+	"junos_isis_total_count":                      "junos_isis_adjacencies",
+	"junos_isis_up_count":                         "junos_isis_adjacencies_up",
+	"junos_mac_table_total_count":                 "junos_mac_table_entries",
+	"junos_mpls_lsp_path_flapcount":               "junos_mpls_lsp_path_flaps_total",
+	"junos_nat2_statistics_source_pool_blk_total": "junos_nat2_statistics_source_pool_blks",
+	"junos_power_budget_actual_power_used":        "junos_power_budget_actual_power_used_watts",
+	"junos_power_budget_power_supplied_psu":       "junos_power_budget_power_supplied_psu_watts",
+	"junos_power_budget_total_power_supplied":     "junos_power_budget_total_power_supplied_watts",
+	"junos_power_capacity_actual":                 "junos_power_capacity_actual_watts",
+	"junos_power_capacity_actual_usage":           "junos_power_capacity_actual_usage_watts",
+	"junos_power_capacity_allocated":              "junos_power_capacity_allocated_watts",
+	"junos_power_capacity_max":                    "junos_power_capacity_max_watts",
+	"junos_power_capacity_remaining":              "junos_power_capacity_remaining_watts",
+	"junos_power_capacity_sys_actual_usage":       "junos_power_capacity_sys_actual_usage_watts",
+	"junos_power_capacity_sys_max":                "junos_power_capacity_sys_max_watts",
+	"junos_power_capacity_sys_remaining":          "junos_power_capacity_sys_remaining_watts",
+	"junos_power_pem_current":                     "junos_power_pem_current_amperes",
+	"junos_power_pem_power_usage":                 "junos_power_pem_power_usage_watts",
+	"junos_power_pem_voltage":                     "junos_power_pem_voltage_volts",
+	"junos_route_engine_cpu_temp":                 "junos_route_engine_cpu_temperature_celsius",
+	"junos_route_engine_temp":                     "junos_route_engine_temperature_celsius",
+	"junos_routes_total_count":                    "junos_routes",
+	"junos_rpki_session_flap_count":               "junos_rpki_session_flaps_total",
+	"junos_rpm_probe_results_received_total":      "junos_rpm_probe_results_responses_received",
+	"junos_rpm_probe_results_sent_total":          "junos_rpm_probe_results_probes_sent",
+	"junos_security_policies_hit_count":           "junos_security_policies_hits_total",
+	"junos_system_jumbo_clusters_total":           "junos_system_jumbo_clusters",
+	"junos_system_mbuf_cluster_bytes_total":       "junos_system_mbuf_cluster_bytes",
+	"junos_system_mbufs_bytes_total":              "junos_system_mbufs_bytes",
+	"junos_system_network_allocated_bytes_total":  "junos_system_network_allocated_bytes",
+	"junos_twamp_probe_results_received_total":    "junos_twamp_probe_results_responses_received",
+	"junos_twamp_probe_results_sent_total":        "junos_twamp_probe_results_probes_sent",
+}
+
+func init() {
+	// optics diagnostics and their alarm and warning thresholds
+	const prefix = "junos_interface_diagnostics_"
+	for _, d := range []struct{ v1, v2, unit string }{
+		{"temp", "temperature", "celsius"},
+		{"module_voltage", "module_voltage", "volts"},
+		{"laser_bias", "laser_bias", "milliamperes"},
+		{"laser_output", "laser_output", "milliwatts"},
+		{"laser_rx", "laser_rx", "milliwatts"},
+	} {
+		names[prefix+d.v1] = prefix + d.v2 + "_" + d.unit
+
+		for _, t := range []string{"high_alarm", "low_alarm", "high_warn", "low_warn"} {
+			names[prefix+d.v1+"_"+t+"_threshold"] = prefix + d.v2 + "_" + t + "_threshold_" + d.unit
+		}
+	}
+
+	names[prefix+"rx_signal_avg"] = prefix + "rx_signal_avg_milliwatts"
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: MIT
+
+package naming
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Transform returns the metric families named according to mode m
+func Transform(mfs []*dto.MetricFamily, m Mode) []*dto.MetricFamily {
+	if m == V1 {
+		return mfs
+	}
+
+	res := make([]*dto.MetricFamily, 0, len(mfs))
+	for _, mf := range mfs {
+		v2 := convert(mf)
+
+		if m == Both {
+			res = append(res, mf)
+
+			if v2.GetName() == mf.GetName() {
+				continue
+			}
+		}
+
+		res = append(res, v2)
+	}
+
+	slices.SortFunc(res, func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return res
+}
+
+// convert returns the metric family with the name and type of the v2 scheme
+func convert(mf *dto.MetricFamily) *dto.MetricFamily {
+	name, t := Name(mf.GetName(), mf.GetType())
+	if name == mf.GetName() && t == mf.GetType() {
+		return mf
+	}
+
+	res := &dto.MetricFamily{
+		Name:   &name,
+		Help:   mf.Help,
+		Type:   &t,
+		Unit:   mf.Unit,
+		Metric: mf.Metric,
+	}
+
+	if t == mf.GetType() {
+		return res
+	}
+
+	res.Metric = make([]*dto.Metric, len(mf.Metric))
+	for i, m := range mf.Metric {
+		res.Metric[i] = convertMetric(m, t)
+	}
+
+	return res
+}
+
+func convertMetric(m *dto.Metric, t dto.MetricType) *dto.Metric {
+	v := m.GetGauge().GetValue()
+	if m.Counter != nil {
+		v = m.GetCounter().GetValue()
+	}
+
+	res := &dto.Metric{
+		Label:       m.Label,
+		TimestampMs: m.TimestampMs,
+	}
+
+	if t == dto.MetricType_COUNTER {
+		res.Counter = &dto.Counter{Value: &v}
+	} else {
+		res.Gauge = &dto.Gauge{Value: &v}
+	}
+
+	return res
+}
+
+// Gatherer wraps g so that the gathered metric families are named according to mode m
+func Gatherer(g prometheus.Gatherer, m Mode) prometheus.Gatherer {
+	if m == V1 {
+		return g
+	}
+
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, err := g.Gather()
+		return Transform(mfs, m), err
+	})
+}
+
+// Collector wraps c so that the collected metrics are named according to mode m.
+// The metrics of c are gathered by a registry before they are renamed, so c has
+// to be a valid collector for an unchecked registration.
+func Collector(c prometheus.Collector, m Mode) prometheus.Collector {
+	if m == V1 {
+		return c
+	}
+
+	return &collector{c: c, mode: m}
+}
+
+type collector struct {
+	c    prometheus.Collector
+	mode Mode
+}
+
+// Describe implements prometheus.Collector interface. Nothing is described, so
+// the collector is unchecked.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+// Collect implements prometheus.Collector interface
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&unchecked{c.c})
+
+	mfs, err := Gatherer(reg, c.mode).Gather()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(prometheus.NewInvalidDesc(err), err)
+	}
+
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			ch <- constMetric(mf, m)
+		}
+	}
+}
+
+func constMetric(mf *dto.MetricFamily, m *dto.Metric) prometheus.Metric {
+	labelNames := make([]string, len(m.Label))
+	labelValues := make([]string, len(m.Label))
+	for i, l := range m.Label {
+		labelNames[i] = l.GetName()
+		labelValues[i] = l.GetValue()
+	}
+
+	desc := prometheus.NewDesc(mf.GetName(), mf.GetHelp(), labelNames, nil)
+
+	var res prometheus.Metric
+	var err error
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		res, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, m.GetCounter().GetValue(), labelValues...)
+	case dto.MetricType_GAUGE:
+		res, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.GetGauge().GetValue(), labelValues...)
+	default:
+		res, err = prometheus.NewConstMetric(desc, prometheus.UntypedValue, m.GetUntyped().GetValue(), labelValues...)
+	}
+
+	if err != nil {
+		return prometheus.NewInvalidMetric(desc, err)
+	}
+
+	return res
+}
+
+// unchecked hides the descriptions of a collector, so it is registered as unchecked collector
+type unchecked struct {
+	prometheus.Collector
+}
+
+// Describe implements prometheus.Collector interface
+func (u *unchecked) Describe(ch chan<- *prometheus.Desc) {
+}
@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	scopeName = "github.com/czerwonk/junos_exporter"
+
+	targetLabel = "target"
+	modelLabel  = "model"
+
+	// hardwareInfoMetric is the metric the model of a device is taken from
+	hardwareInfoMetric = "junos_system_hardware_info"
+)
+
+// converter converts metric families to OTLP metrics with one resource per device
+type converter struct {
+	base         *resource.Resource
+	attributes   map[string]string
+	staticLabels func(target string) map[string]string
+	start        time.Time
+	now          time.Time
+}
+
+// device are the metrics of a device (or of the exporter itself for target "")
+type device struct {
+	target  string
+	static  map[string]string
+	metrics []metricdata.Metrics
+}
+
+// convert returns the metrics of the families grouped by device. The target,
+// the static labels of the device and its model are resource attributes,
+// all other labels are attributes of the data points. Counters are
+// cumulative monotonic sums starting at c.start.
+func (c *converter) convert(mfs []*dto.MetricFamily) []*metricdata.ResourceMetrics {
+	devices := make(map[string]*device)
+	models := make(map[string]string)
+
+	for _, mf := range mfs {
+		byTarget := make(map[string][]*dto.Metric)
+		for _, m := range mf.Metric {
+			target := labelValue(m, targetLabel)
+			byTarget[target] = append(byTarget[target], m)
+
+			if mf.GetName() == hardwareInfoMetric && models[target] == "" {
+				models[target] = labelValue(m, modelLabel)
+			}
+		}
+
+		for _, target := range slices.Sorted(maps.Keys(byTarget)) {
+			d := devices[target]
+			if d == nil {
+				d = &device{target: target}
+				if target != "" && c.staticLabels != nil {
+					d.static = c.staticLabels(target)
+				}
+				devices[target] = d
+			}
+
+			if data := c.aggregation(mf, byTarget[target], d.static); data != nil {
+				d.metrics = append(d.metrics, metricdata.Metrics{
+					Name:        mf.GetName(),
+					Description: mf.GetHelp(),
+					Unit:        mf.GetUnit(),
+					Data:        data,
+				})
+			}
+		}
+	}
+
+	res := make([]*metricdata.ResourceMetrics, 0, len(devices))
+	for _, target := range slices.Sorted(maps.Keys(devices)) {
+		d := devices[target]
+		res = append(res, &metricdata.ResourceMetrics{
+			Resource: c.resource(d, models[target]),
+			ScopeMetrics: []metricdata.ScopeMetrics{
+				{
+					Scope:   instrumentation.Scope{Name: scopeName},
+					Metrics: d.metrics,
+				},
+			},
+		})
+	}
+
+	return res
+}
+
+func (c *converter) resource(d *device, model string) *resource.Resource {
+	attrs := make([]attribute.KeyValue, 0, len(c.attributes)+len(d.static)+2)
+	for k, v := range c.attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	if d.target != "" {
+		attrs = append(attrs, attribute.String(targetLabel, d.target))
+	}
+
+	for k, v := range d.static {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	if model != "" {
+		attrs = append(attrs, attribute.String(modelLabel, model))
+	}
+
+	r, err := resource.Merge(c.base, resource.NewSchemaless(attrs...))
+	if err != nil {
+		// schema URLs can not conflict since the attributes are schemaless
+		return c.base
+	}
+
+	return r
+}
+
+func (c *converter) aggregation(mf *dto.MetricFamily, metrics []*dto.Metric, static map[string]string) metricdata.Aggregation {
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		return metricdata.Sum[float64]{
+			DataPoints:  c.dataPoints(metrics, static, true, func(m *dto.Metric) float64 { return m.GetCounter().GetValue() }),
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		}
+	case dto.MetricType_GAUGE:
+		return metricdata.Gauge[float64]{
+			DataPoints: c.dataPoints(metrics, static, false, func(m *dto.Metric) float64 { return m.GetGauge().GetValue() }),
+		}
+	case dto.MetricType_UNTYPED:
+		return metricdata.Gauge[float64]{
+			DataPoints: c.dataPoints(metrics, static, false, func(m *dto.Metric) float64 { return m.GetUntyped().GetValue() }),
+		}
+	case dto.MetricType_SUMMARY:
+		return c.summary(metrics, static)
+	case dto.MetricType_HISTOGRAM:
+		return c.histogram(metrics, static)
+	default:
+		return nil
+	}
+}
+
+func (c *converter) dataPoints(metrics []*dto.Metric, static map[string]string, cumulative bool, value func(*dto.Metric) float64) []metricdata.DataPoint[float64] {
+	points := make([]metricdata.DataPoint[float64], 0, len(metrics))
+	for _, m := range metrics {
+		p := metricdata.DataPoint[float64]{
+			Attributes: attributes(m, static),
+			Time:       c.timestamp(m),
+			Value:      value(m),
+		}
+		if cumulative {
+			p.StartTime = c.start
+		}
+
+		points = append(points, p)
+	}
+
+	return points
+}
+
+func (c *converter) summary(metrics []*dto.Metric, static map[string]string) metricdata.Summary {
+	s := metricdata.Summary{
+		DataPoints: make([]metricdata.SummaryDataPoint, 0, len(metrics)),
+	}
+
+	for _, m := range metrics {
+		p := metricdata.SummaryDataPoint{
+			Attributes: attributes(m, static),
+			StartTime:  c.start,
+			Time:       c.timestamp(m),
+			Count:      m.GetSummary().GetSampleCount(),
+			Sum:        m.GetSummary().GetSampleSum(),
+		}
+
+		for _, q := range m.GetSummary().GetQuantile() {
+			p.QuantileValues = append(p.QuantileValues, metricdata.QuantileValue{Quantile: q.GetQuantile(), Value: q.GetValue()})
+		}
+
+		s.DataPoints = append(s.DataPoints, p)
+	}
+
+	return s
+}
+
+func (c *converter) histogram(metrics []*dto.Metric, static map[string]string) metricdata.Histogram[float64] {
+	h := metricdata.Histogram[float64]{
+		DataPoints:  make([]metricdata.HistogramDataPoint[float64], 0, len(metrics)),
+		Temporality: metricdata.CumulativeTemporality,
+	}
+
+	for _, m := range metrics {
+		ph := m.GetHistogram()
+		p := metricdata.HistogramDataPoint[float64]{
+			Attributes: attributes(m, static),
+			StartTime:  c.start,
+			Time:       c.timestamp(m),
+			Count:      ph.GetSampleCount(),
+			Sum:        ph.GetSampleSum(),
+		}
+
+		// Prometheus buckets are cumulative, OTLP bucket counts are not.
+		// The +Inf bucket is implicit in OTLP (count of the last bucket).
+		var prev uint64
+		for _, b := range ph.GetBucket() {
+			if math.IsInf(b.GetUpperBound(), 1) {
+				continue
+			}
+
+			p.Bounds = append(p.Bounds, b.GetUpperBound())
+			p.BucketCounts = append(p.BucketCounts, b.GetCumulativeCount()-prev)
+			prev = b.GetCumulativeCount()
+		}
+		p.BucketCounts = append(p.BucketCounts, ph.GetSampleCount()-prev)
+
+		h.DataPoints = append(h.DataPoints, p)
+	}
+
+	return h
+}
+
+func (c *converter) timestamp(m *dto.Metric) time.Time {
+	if m.TimestampMs != nil {
+		return time.UnixMilli(m.GetTimestampMs())
+	}
+
+	return c.now
+}
+
+// attributes returns the labels of the metric without the labels which are resource attributes
+func attributes(m *dto.Metric, static map[string]string) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(m.Label))
+	for _, l := range m.Label {
+		if l.GetName() == targetLabel {
+			continue
+		}
+
+		if _, found := static[l.GetName()]; found {
+			continue
+		}
+
+		kvs = append(kvs, attribute.String(l.GetName(), l.GetValue()))
+	}
+
+	return attribute.NewSet(kvs...)
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+
+	return ""
+}
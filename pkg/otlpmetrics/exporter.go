@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+
+// Package otlpmetrics exports the metrics of the devices via OTLP (gRPC or
+// HTTP), e.g. to feed an OpenTelemetry Collector pipeline directly.
+package otlpmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+const (
+	// DefaultInterval is the interval the devices are scraped and exported in if not configured
+	DefaultInterval = time.Minute
+
+	defaultTimeout = 10 * time.Second
+)
+
+// Exporter sends metrics to an OTLP endpoint
+type Exporter struct {
+	exp          sdkmetric.Exporter
+	base         *resource.Resource
+	attributes   map[string]string
+	staticLabels func(target string) map[string]string
+	start        time.Time
+}
+
+// New creates an exporter for the endpoint of the config. The attributes of
+// res (e.g. service.name) are added to the resource of every device,
+// staticLabels returns the labels configured for the device of a target.
+func New(ctx context.Context, cfg *config.OTLPMetricsConfig, res *resource.Resource, staticLabels func(target string) map[string]string) (*Exporter, error) {
+	exp, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metrics exporter: %w", err)
+	}
+
+	return &Exporter{
+		exp:          exp,
+		base:         res,
+		attributes:   cfg.ResourceAttributes,
+		staticLabels: staticLabels,
+		start:        time.Now(),
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg *config.OTLPMetricsConfig) (sdkmetric.Exporter, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	isURL := strings.Contains(cfg.Endpoint, "://")
+
+	if cfg.Protocol == "http" {
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithTimeout(timeout),
+			otlpmetrichttp.WithHeaders(cfg.Headers),
+		}
+		if isURL {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(cfg.Endpoint))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithTimeout(timeout),
+		otlpmetricgrpc.WithHeaders(cfg.Headers),
+	}
+	if isURL {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// Export sends the metrics, one request per device
+func (e *Exporter) Export(ctx context.Context, mfs []*dto.MetricFamily) error {
+	c := &converter{
+		base:         e.base,
+		attributes:   e.attributes,
+		staticLabels: e.staticLabels,
+		start:        e.start,
+		now:          time.Now(),
+	}
+
+	var errs []error
+	for _, rm := range c.convert(mfs) {
+		if err := e.exp.Export(ctx, rm); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shutdown flushes and closes the connection to the endpoint
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return e.exp.Shutdown(ctx)
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+
+package otlpmetrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+func testMetrics(t *testing.T) []*dto.MetricFamily {
+	reg := prometheus.NewRegistry()
+
+	bytes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "junos_interface_receive_bytes", Help: "Received data in bytes"}, []string{"target", "site", "name"})
+	bytes.WithLabelValues("router1", "fra1", "xe-0/0/0").Add(42)
+
+	info := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "junos_system_hardware_info", Help: "Hardware information about this system"}, []string{"target", "site", "model"})
+	info.WithLabelValues("router1", "fra1", "mx204").Set(1)
+
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "junos_exporter_scrape_duration_seconds", Help: "Duration of a scrape", Buckets: []float64{1, 5}})
+	duration.Observe(0.5)
+	duration.Observe(3)
+	duration.Observe(10)
+
+	reg.MustRegister(bytes, info, duration)
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+
+	return mfs
+}
+
+func staticLabels(target string) map[string]string {
+	if target == "router1" {
+		return map[string]string{"site": "fra1"}
+	}
+
+	return nil
+}
+
+func attrs(kvs []*commonpb.KeyValue) map[string]string {
+	m := make(map[string]string)
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value.GetStringValue()
+	}
+
+	return m
+}
+
+func TestExport(t *testing.T) {
+	var mu sync.Mutex
+	var reqs []*colmetricpb.ExportMetricsServiceRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := &colmetricpb.ExportMetricsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(b, req))
+
+		mu.Lock()
+		reqs = append(reqs, req)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		b, _ = proto.Marshal(&colmetricpb.ExportMetricsServiceResponse{})
+		w.Write(b)
+	}))
+	defer srv.Close()
+
+	cfg := &config.OTLPMetricsConfig{
+		Endpoint:           srv.URL + "/v1/metrics",
+		Protocol:           "http",
+		Headers:            map[string]string{"X-Token": "secret"},
+		ResourceAttributes: map[string]string{"deployment.environment": "test"},
+	}
+	res := resource.NewSchemaless(attribute.String("service.name", "junos_exporter"))
+
+	ctx := context.Background()
+	e, err := New(ctx, cfg, res, staticLabels)
+	require.NoError(t, err)
+	defer e.Shutdown(ctx)
+
+	require.NoError(t, e.Export(ctx, testMetrics(t)))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// one request for the exporter itself and one for router1
+	require.Len(t, reqs, 2)
+
+	rm := reqs[0].ResourceMetrics[0]
+	assert.Equal(t, map[string]string{"service.name": "junos_exporter", "deployment.environment": "test"}, attrs(rm.Resource.Attributes))
+
+	h := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "junos_exporter_scrape_duration_seconds", h.Name)
+	hp := h.GetHistogram().DataPoints[0]
+	assert.Equal(t, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, h.GetHistogram().AggregationTemporality)
+	assert.Equal(t, []float64{1, 5}, hp.ExplicitBounds)
+	assert.Equal(t, []uint64{1, 1, 1}, hp.BucketCounts)
+	assert.Equal(t, uint64(3), hp.Count)
+	assert.Equal(t, 13.5, hp.GetSum())
+
+	rm = reqs[1].ResourceMetrics[0]
+	assert.Equal(t, map[string]string{
+		"service.name":           "junos_exporter",
+		"deployment.environment": "test",
+		"target":                 "router1",
+		"site":                   "fra1",
+		"model":                  "mx204",
+	}, attrs(rm.Resource.Attributes))
+
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	sum := metrics[0]
+	assert.Equal(t, "junos_interface_receive_bytes", sum.Name)
+	assert.True(t, sum.GetSum().IsMonotonic)
+	assert.Equal(t, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.GetSum().AggregationTemporality)
+	p := sum.GetSum().DataPoints[0]
+	assert.Equal(t, 42.0, p.GetAsDouble())
+	assert.NotZero(t, p.StartTimeUnixNano)
+	assert.Equal(t, map[string]string{"name": "xe-0/0/0"}, attrs(p.Attributes), "target and static labels are resource attributes")
+
+	gauge := metrics[1]
+	assert.Equal(t, "junos_system_hardware_info", gauge.Name)
+	assert.Equal(t, map[string]string{"model": "mx204"}, attrs(gauge.GetGauge().DataPoints[0].Attributes))
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: MIT
+
+package relabel
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+)
+
+// Transform applies the rules to the series of the metric families. Series
+// renamed by the rules are moved to the family of their new name.
+func Transform(mfs []*dto.MetricFamily, rules []*Config) []*dto.MetricFamily {
+	if len(rules) == 0 {
+		return mfs
+	}
+
+	families := make(map[string]*dto.MetricFamily)
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			labels := make(map[string]string, len(m.Label)+1)
+			for _, l := range m.Label {
+				labels[l.GetName()] = l.GetValue()
+			}
+			labels[model.MetricNameLabel] = mf.GetName()
+
+			if !Process(rules, labels) {
+				continue
+			}
+
+			name := labels[model.MetricNameLabel]
+			delete(labels, model.MetricNameLabel)
+
+			f, found := families[name]
+			if !found {
+				f = &dto.MetricFamily{
+					Name: &name,
+					Help: mf.Help,
+					Type: mf.Type,
+					Unit: mf.Unit,
+				}
+				families[name] = f
+			}
+
+			f.Metric = append(f.Metric, withLabels(m, labels))
+		}
+	}
+
+	res := make([]*dto.MetricFamily, 0, len(families))
+	for _, f := range families {
+		res = append(res, f)
+	}
+
+	slices.SortFunc(res, func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return res
+}
+
+func withLabels(m *dto.Metric, labels map[string]string) *dto.Metric {
+	res := &dto.Metric{
+		Label:       make([]*dto.LabelPair, 0, len(labels)),
+		Gauge:       m.Gauge,
+		Counter:     m.Counter,
+		Summary:     m.Summary,
+		Untyped:     m.Untyped,
+		Histogram:   m.Histogram,
+		TimestampMs: m.TimestampMs,
+	}
+
+	for _, name := range sortedKeys(labels) {
+		value := labels[name]
+		res.Label = append(res.Label, &dto.LabelPair{Name: &name, Value: &value})
+	}
+
+	return res
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// Collector wraps c so that the rules are applied to the collected metrics.
+// The metrics of c are gathered by a registry before the rules are applied, so
+// c has to be a valid collector for an unchecked registration.
+func Collector(c prometheus.Collector, rules []*Config) prometheus.Collector {
+	if len(rules) == 0 {
+		return c
+	}
+
+	return &collector{c: c, rules: rules}
+}
+
+type collector struct {
+	c     prometheus.Collector
+	rules []*Config
+}
+
+// Describe implements prometheus.Collector interface. Nothing is described, so
+// the collector is unchecked.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+// Collect implements prometheus.Collector interface
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&unchecked{c.c})
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(prometheus.NewInvalidDesc(err), err)
+	}
+
+	for _, mf := range Transform(mfs, c.rules) {
+		for _, m := range mf.Metric {
+			ch <- constMetric(mf, m)
+		}
+	}
+}
+
+func constMetric(mf *dto.MetricFamily, m *dto.Metric) prometheus.Metric {
+	labelNames := make([]string, len(m.Label))
+	labelValues := make([]string, len(m.Label))
+	for i, l := range m.Label {
+		labelNames[i] = l.GetName()
+		labelValues[i] = l.GetValue()
+	}
+
+	desc := prometheus.NewDesc(mf.GetName(), mf.GetHelp(), labelNames, nil)
+
+	var res prometheus.Metric
+	var err error
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		res, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, m.GetCounter().GetValue(), labelValues...)
+	case dto.MetricType_GAUGE:
+		res, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.GetGauge().GetValue(), labelValues...)
+	case dto.MetricType_HISTOGRAM:
+		h := m.GetHistogram()
+		buckets := make(map[float64]uint64, len(h.Bucket))
+		for _, b := range h.Bucket {
+			buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+		}
+		res, err = prometheus.NewConstHistogram(desc, h.GetSampleCount(), h.GetSampleSum(), buckets, labelValues...)
+	case dto.MetricType_SUMMARY:
+		s := m.GetSummary()
+		quantiles := make(map[float64]float64, len(s.Quantile))
+		for _, q := range s.Quantile {
+			quantiles[q.GetQuantile()] = q.GetValue()
+		}
+		res, err = prometheus.NewConstSummary(desc, s.GetSampleCount(), s.GetSampleSum(), quantiles, labelValues...)
+	default:
+		res, err = prometheus.NewConstMetric(desc, prometheus.UntypedValue, m.GetUntyped().GetValue(), labelValues...)
+	}
+
+	if err != nil {
+		return prometheus.NewInvalidMetric(desc, err)
+	}
+
+	return res
+}
+
+// unchecked hides the descriptions of a collector, so it is registered as unchecked collector
+type unchecked struct {
+	prometheus.Collector
+}
+
+// Describe implements prometheus.Collector interface
+func (u *unchecked) Describe(ch chan<- *prometheus.Desc) {
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: MIT
+
+// Package relabel implements rules to drop, keep or rewrite series before
+// exposition, similar to the metric_relabel_configs of Prometheus.
+package relabel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// Action is the action of a rule
+type Action string
+
+const (
+	// Replace sets the target label to the replacement if the regex matches the source labels
+	Replace Action = "replace"
+
+	// Keep drops series whose source labels do not match the regex
+	Keep Action = "keep"
+
+	// Drop drops series whose source labels match the regex
+	Drop Action = "drop"
+
+	// LabelDrop removes labels whose name matches the regex
+	LabelDrop Action = "labeldrop"
+
+	// LabelKeep removes labels whose name does not match the regex
+	LabelKeep Action = "labelkeep"
+)
+
+const (
+	defaultSeparator   = ";"
+	defaultRegex       = "(.*)"
+	defaultReplacement = "$1"
+)
+
+// Config is a rule applied to the series of a device. The metric name is the value of the label __name__.
+type Config struct {
+	SourceLabels []string `yaml:"source_labels,flow,omitempty"`
+	Separator    string   `yaml:"separator,omitempty"`
+	Regex        string   `yaml:"regex,omitempty"`
+	TargetLabel  string   `yaml:"target_label,omitempty"`
+	Replacement  string   `yaml:"replacement,omitempty"`
+	Action       Action   `yaml:"action,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// UnmarshalYAML sets the defaults of the fields not set in the config (same defaults as Prometheus)
+func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
+	type config Config
+	cfg := config{
+		Separator:   defaultSeparator,
+		Regex:       defaultRegex,
+		Replacement: defaultReplacement,
+		Action:      Replace,
+	}
+
+	err := unmarshal(&cfg)
+	if err != nil {
+		return err
+	}
+
+	*c = Config(cfg)
+	return nil
+}
+
+// Validate checks the rule and compiles its regex. It has to be called before the rule is applied.
+func (c *Config) Validate() error {
+	re, err := regexp.Compile("^(?:" + c.regex() + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %w", c.Regex, err)
+	}
+
+	switch c.action() {
+	case Replace:
+		if c.TargetLabel == "" {
+			return fmt.Errorf("target_label must be set for action %s", Replace)
+		}
+	case Keep, Drop:
+		if len(c.SourceLabels) == 0 {
+			return fmt.Errorf("source_labels must be set for action %s", c.Action)
+		}
+	case LabelDrop, LabelKeep:
+		if len(c.SourceLabels) > 0 || c.TargetLabel != "" {
+			return fmt.Errorf("source_labels and target_label are not allowed for action %s", c.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", c.Action)
+	}
+
+	c.re = re
+	return nil
+}
+
+// Validate checks all rules and compiles their regexes
+func Validate(rules []*Config) error {
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) regex() string {
+	if c.Regex == "" {
+		return defaultRegex
+	}
+
+	return c.Regex
+}
+
+func (c *Config) action() Action {
+	if c.Action == "" {
+		return Replace
+	}
+
+	return c.Action
+}
+
+func (c *Config) separator() string {
+	if c.Separator == "" {
+		return defaultSeparator
+	}
+
+	return c.Separator
+}
+
+// Process applies the rules to the labels of a series (including __name__) and
+// returns whether the series is kept. The labels are modified in place.
+func Process(rules []*Config, labels map[string]string) bool {
+	for _, r := range rules {
+		if !r.process(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Config) process(labels map[string]string) bool {
+	if c.re == nil {
+		return true
+	}
+
+	switch c.action() {
+	case Keep:
+		return c.re.MatchString(c.sourceValue(labels))
+	case Drop:
+		return !c.re.MatchString(c.sourceValue(labels))
+	case LabelDrop:
+		for name := range labels {
+			if name != model.MetricNameLabel && c.re.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	case LabelKeep:
+		for name := range labels {
+			if name != model.MetricNameLabel && !c.re.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	case Replace:
+		v := c.sourceValue(labels)
+		m := c.re.FindStringSubmatchIndex(v)
+		if m == nil {
+			return true
+		}
+
+		res := string(c.re.ExpandString(nil, c.Replacement, v, m))
+		if res == "" {
+			delete(labels, c.TargetLabel)
+		} else {
+			labels[c.TargetLabel] = res
+		}
+	}
+
+	return true
+}
+
+func (c *Config) sourceValue(labels map[string]string) string {
+	values := make([]string, len(c.SourceLabels))
+	for i, name := range c.SourceLabels {
+		values[i] = labels[name]
+	}
+
+	return strings.Join(values, c.separator())
+}
+
+// Dropped returns whether all series of a metric are dropped by the rules
+// regardless of their labels, so the metric does not need to be collected at all.
+// Only rules depending on the metric name alone are taken into account.
+func Dropped(rules []*Config, name string) bool {
+	labels := map[string]string{model.MetricNameLabel: name}
+
+	for _, r := range rules {
+		nameOnly := len(r.SourceLabels) == 1 && r.SourceLabels[0] == model.MetricNameLabel
+
+		switch r.action() {
+		case Keep, Drop:
+			if nameOnly && !r.process(labels) {
+				return true
+			}
+		case Replace:
+			if r.TargetLabel != model.MetricNameLabel {
+				continue
+			}
+
+			if !nameOnly {
+				// the name depends on other labels
+				return false
+			}
+
+			r.process(labels)
+			if labels[model.MetricNameLabel] == "" {
+				return false
+			}
+		}
+	}
+
+	return false
+}
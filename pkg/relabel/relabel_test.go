@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: MIT
+
+package relabel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+func parseRules(t *testing.T, s string) []*Config {
+	var rules []*Config
+	require.NoError(t, yaml.Unmarshal([]byte(s), &rules))
+	require.NoError(t, Validate(rules))
+
+	return rules
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    string
+		labels   map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "drop by name",
+			rules: `
+- source_labels: [__name__]
+  regex: junos_nat_statistics_.*
+  action: drop`,
+			labels:   map[string]string{"__name__": "junos_nat_statistics_nat64_dfbit_set", "target": "router1"},
+			expected: nil,
+		},
+		{
+			name: "regex is anchored",
+			rules: `
+- source_labels: [__name__]
+  regex: nat
+  action: drop`,
+			labels:   map[string]string{"__name__": "junos_nat_statistics_nat64_dfbit_set"},
+			expected: map[string]string{"__name__": "junos_nat_statistics_nat64_dfbit_set"},
+		},
+		{
+			name: "keep by name and label",
+			rules: `
+- source_labels: [__name__, interface]
+  regex: junos_interface_queue_.*;xe-.*
+  action: keep`,
+			labels:   map[string]string{"__name__": "junos_interface_queue_queued_packets_count", "interface": "ge-0/0/0"},
+			expected: nil,
+		},
+		{
+			name: "replace with defaults",
+			rules: `
+- source_labels: [interface]
+  regex: (\w+)-.*
+  target_label: interface_type`,
+			labels:   map[string]string{"__name__": "junos_interface_up", "interface": "xe-0/0/0"},
+			expected: map[string]string{"__name__": "junos_interface_up", "interface": "xe-0/0/0", "interface_type": "xe"},
+		},
+		{
+			name: "rename",
+			rules: `
+- source_labels: [__name__]
+  regex: junos_route_engine_temp
+  target_label: __name__
+  replacement: junos_route_engine_temperature_celsius`,
+			labels:   map[string]string{"__name__": "junos_route_engine_temp", "target": "router1"},
+			expected: map[string]string{"__name__": "junos_route_engine_temperature_celsius", "target": "router1"},
+		},
+		{
+			name: "labeldrop",
+			rules: `
+- regex: agent_.*
+  action: labeldrop`,
+			labels:   map[string]string{"__name__": "junos_subscriber_info", "agent_circuit_id": "1", "interface": "demux0.1"},
+			expected: map[string]string{"__name__": "junos_subscriber_info", "interface": "demux0.1"},
+		},
+		{
+			name: "labelkeep",
+			rules: `
+- regex: target|interface
+  action: labelkeep`,
+			labels:   map[string]string{"__name__": "junos_subscriber_info", "agent_circuit_id": "1", "interface": "demux0.1", "target": "router1"},
+			expected: map[string]string{"__name__": "junos_subscriber_info", "interface": "demux0.1", "target": "router1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			labels := test.labels
+			keep := Process(parseRules(t, test.rules), labels)
+
+			if test.expected == nil {
+				assert.False(t, keep)
+				return
+			}
+
+			assert.True(t, keep)
+			assert.Equal(t, test.expected, labels)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		rules    string
+		expected string
+	}{
+		{
+			rules:    "- source_labels: [__name__]\n  regex: '('\n  action: drop",
+			expected: "rule 0: invalid regex \"(\"",
+		},
+		{
+			rules:    "- source_labels: [__name__]\n  action: hashmod",
+			expected: "rule 0: unknown action \"hashmod\"",
+		},
+		{
+			rules:    "- source_labels: [__name__]",
+			expected: "rule 0: target_label must be set for action replace",
+		},
+		{
+			rules:    "- action: drop",
+			expected: "rule 0: source_labels must be set for action drop",
+		},
+		{
+			rules:    "- source_labels: [interface]\n  action: labeldrop",
+			expected: "rule 0: source_labels and target_label are not allowed for action labeldrop",
+		},
+	}
+
+	for _, test := range tests {
+		var rules []*Config
+		require.NoError(t, yaml.Unmarshal([]byte(test.rules), &rules))
+
+		err := Validate(rules)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), test.expected)
+		}
+	}
+}
+
+func TestDropped(t *testing.T) {
+	rules := parseRules(t, `
+- source_labels: [__name__, interface]
+  regex: junos_interface_.*;ge-.*
+  action: drop
+- source_labels: [__name__]
+  regex: junos_nat_statistics_pool_.*
+  action: drop
+- source_labels: [__name__]
+  regex: junos_evpn_(.*)
+  target_label: __name__
+  replacement: junos_evpn_v2_${1}
+- source_labels: [__name__]
+  regex: junos_evpn_v2_duplicate_mac_.*
+  action: drop
+`)
+
+	assert.True(t, Dropped(rules, "junos_nat_statistics_pool_users"))
+	assert.True(t, Dropped(rules, "junos_evpn_duplicate_mac_count"), "renamed by a previous rule")
+	assert.False(t, Dropped(rules, "junos_nat_statistics_nat64_dfbit_set"))
+	assert.False(t, Dropped(rules, "junos_interface_up"), "dropped depending on other labels")
+	assert.False(t, Dropped(nil, "junos_interface_up"))
+
+	rules = parseRules(t, `
+- source_labels: [__name__]
+  regex: junos_(up|interface_.*)
+  action: keep
+`)
+	assert.True(t, Dropped(rules, "junos_nat_statistics_pool_users"))
+	assert.False(t, Dropped(rules, "junos_interface_up"))
+
+	rules = parseRules(t, `
+- source_labels: [target]
+  target_label: __name__
+  replacement: junos_${1}_up
+- source_labels: [__name__]
+  regex: junos_nat_.*
+  action: drop
+`)
+	assert.False(t, Dropped(rules, "junos_nat_statistics_pool_users"), "name depends on other labels")
+}
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	queued := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "junos_interface_queue_queued_packets_count", Help: "Number of queued packets"}, []string{"target", "name"})
+	queued.WithLabelValues("router1", "xe-0/0/0").Set(10)
+	queued.WithLabelValues("router1", "ge-0/0/0").Set(20)
+	reg.MustRegister(queued)
+
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "junos_up", Help: "Scrape of target was successful"}, []string{"target"})
+	up.WithLabelValues("router1").Set(1)
+	reg.MustRegister(up)
+
+	rules := parseRules(t, `
+- source_labels: [__name__, name]
+  regex: junos_interface_queue_.*;ge-.*
+  action: drop
+- source_labels: [name]
+  regex: (\w+)-.*
+  target_label: type
+- source_labels: [__name__]
+  regex: junos_up
+  target_label: __name__
+  replacement: junos_device_up
+`)
+
+	c := Collector(prometheus.CollectorFunc(func(ch chan<- prometheus.Metric) {
+		queued.Collect(ch)
+		up.Collect(ch)
+	}), rules)
+
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP junos_device_up Scrape of target was successful
+# TYPE junos_device_up gauge
+junos_device_up{target="router1"} 1
+# HELP junos_interface_queue_queued_packets_count Number of queued packets
+# TYPE junos_interface_queue_queued_packets_count gauge
+junos_interface_queue_queued_packets_count{name="xe-0/0/0",target="router1",type="xe"} 10
+`)))
+}
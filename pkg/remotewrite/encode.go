@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+
+package remotewrite
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// field numbers of the messages of the remote-write protocol (prometheus.WriteRequest)
+const (
+	writeRequestTimeseries = 1
+	timeSeriesLabels       = 1
+	timeSeriesSamples      = 2
+	labelName              = 1
+	labelValue             = 2
+	sampleValue            = 1
+	sampleTimestamp        = 2
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+// encoder converts metric families to a WriteRequest
+type encoder struct {
+	externalLabels []label
+	timestamp      int64
+	buf            []byte
+}
+
+// encode returns the WriteRequest (protobuf, not compressed) of the metric
+// families. Samples without timestamp get the timestamp ts. External labels
+// are added to every series unless the series has a label with the same name.
+func encode(mfs []*dto.MetricFamily, externalLabels map[string]string, ts time.Time) []byte {
+	e := &encoder{
+		timestamp: ts.UnixMilli(),
+	}
+
+	for name, value := range externalLabels {
+		e.externalLabels = append(e.externalLabels, label{name: name, value: value})
+	}
+
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			e.addMetric(mf, m)
+		}
+	}
+
+	return e.buf
+}
+
+func (e *encoder) addMetric(mf *dto.MetricFamily, m *dto.Metric) {
+	name := mf.GetName()
+	ts := e.timestamp
+	if m.TimestampMs != nil {
+		ts = m.GetTimestampMs()
+	}
+
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		e.addSeries(name, m.Label, nil, m.GetCounter().GetValue(), ts)
+	case dto.MetricType_GAUGE:
+		e.addSeries(name, m.Label, nil, m.GetGauge().GetValue(), ts)
+	case dto.MetricType_UNTYPED:
+		e.addSeries(name, m.Label, nil, m.GetUntyped().GetValue(), ts)
+	case dto.MetricType_SUMMARY:
+		s := m.GetSummary()
+		for _, q := range s.Quantile {
+			e.addSeries(name, m.Label, &label{"quantile", formatFloat(q.GetQuantile())}, q.GetValue(), ts)
+		}
+		e.addSeries(name+"_sum", m.Label, nil, s.GetSampleSum(), ts)
+		e.addSeries(name+"_count", m.Label, nil, float64(s.GetSampleCount()), ts)
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		h := m.GetHistogram()
+		for _, b := range h.Bucket {
+			e.addSeries(name+"_bucket", m.Label, &label{"le", formatFloat(b.GetUpperBound())}, float64(b.GetCumulativeCount()), ts)
+		}
+		if n := len(h.Bucket); n == 0 || !math.IsInf(h.Bucket[n-1].GetUpperBound(), 1) {
+			e.addSeries(name+"_bucket", m.Label, &label{"le", "+Inf"}, float64(h.GetSampleCount()), ts)
+		}
+		e.addSeries(name+"_sum", m.Label, nil, h.GetSampleSum(), ts)
+		e.addSeries(name+"_count", m.Label, nil, float64(h.GetSampleCount()), ts)
+	}
+}
+
+func (e *encoder) addSeries(name string, pairs []*dto.LabelPair, extra *label, value float64, ts int64) {
+	labels := make([]label, 0, len(pairs)+len(e.externalLabels)+2)
+	labels = append(labels, label{"__name__", name})
+	for _, p := range pairs {
+		labels = append(labels, label{p.GetName(), p.GetValue()})
+	}
+	if extra != nil {
+		labels = append(labels, *extra)
+	}
+
+	for _, l := range e.externalLabels {
+		if !slices.ContainsFunc(labels, func(x label) bool { return x.name == l.name }) {
+			labels = append(labels, l)
+		}
+	}
+
+	// the protocol requires the labels of a series to be sorted by name
+	slices.SortFunc(labels, func(a, b label) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	var series []byte
+	for _, l := range labels {
+		var b []byte
+		b = protowire.AppendTag(b, labelName, protowire.BytesType)
+		b = protowire.AppendString(b, l.name)
+		b = protowire.AppendTag(b, labelValue, protowire.BytesType)
+		b = protowire.AppendString(b, l.value)
+
+		series = protowire.AppendTag(series, timeSeriesLabels, protowire.BytesType)
+		series = protowire.AppendBytes(series, b)
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, sampleValue, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(value))
+	sample = protowire.AppendTag(sample, sampleTimestamp, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(ts))
+
+	series = protowire.AppendTag(series, timeSeriesSamples, protowire.BytesType)
+	series = protowire.AppendBytes(series, sample)
+
+	e.buf = protowire.AppendTag(e.buf, writeRequestTimeseries, protowire.BytesType)
+	e.buf = protowire.AppendBytes(e.buf, series)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: MIT
+
+// Package remotewrite pushes metrics to an endpoint supporting the Prometheus
+// remote-write protocol (version 1.0: snappy compressed protobuf).
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultScrapeInterval is the interval the devices are scraped in if not configured
+	DefaultScrapeInterval = time.Minute
+
+	defaultRemoteTimeout = 30 * time.Second
+	defaultQueueCapacity = 10
+	defaultMaxRetries    = 5
+	defaultMinBackoff    = time.Second
+	defaultMaxBackoff    = 30 * time.Second
+
+	userAgent = "junos_exporter"
+)
+
+var (
+	sentRequestsDesc    = prometheus.NewDesc("junos_exporter_remote_write_sent_requests_total", "Number of requests accepted by the remote-write endpoint", nil, nil)
+	failedRequestsDesc  = prometheus.NewDesc("junos_exporter_remote_write_failed_requests_total", "Number of failed requests to the remote-write endpoint (including retries)", nil, nil)
+	droppedRequestsDesc = prometheus.NewDesc("junos_exporter_remote_write_dropped_requests_total", "Number of scrape results dropped because the queue was full or all retries failed", nil, nil)
+	queueLengthDesc     = prometheus.NewDesc("junos_exporter_remote_write_queue_length", "Number of scrape results waiting to be sent", nil, nil)
+	lastSuccessDesc     = prometheus.NewDesc("junos_exporter_remote_write_last_success_timestamp_seconds", "Timestamp of the last request accepted by the remote-write endpoint", nil, nil)
+)
+
+// Writer sends scrape results to a remote-write endpoint. Results are buffered
+// in a bounded queue, the oldest result is dropped if the queue is full.
+type Writer struct {
+	client *http.Client
+
+	mu          sync.Mutex
+	cfg         *config.RemoteWriteConfig
+	queue       [][]byte
+	notify      chan struct{}
+	sent        uint64
+	failed      uint64
+	dropped     uint64
+	lastSuccess time.Time
+}
+
+// New creates a writer sending to the endpoint of the config
+func New(cfg *config.RemoteWriteConfig) *Writer {
+	return &Writer{
+		cfg:    cfg,
+		client: &http.Client{},
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// SetConfig replaces the config of the writer (e.g. after a reload). Queued results are kept.
+func (w *Writer) SetConfig(cfg *config.RemoteWriteConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cfg = cfg
+	w.trimQueue()
+}
+
+// ScrapeInterval returns the interval the devices should be scraped in
+func (w *Writer) ScrapeInterval() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cfg.ScrapeInterval > 0 {
+		return w.cfg.ScrapeInterval
+	}
+
+	return DefaultScrapeInterval
+}
+
+// Enqueue adds the metrics of a scrape to the queue. Samples without timestamp get the timestamp ts.
+func (w *Writer) Enqueue(mfs []*dto.MetricFamily, ts time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	req := snappy.Encode(nil, encode(mfs, w.cfg.ExternalLabels, ts))
+	w.queue = append(w.queue, req)
+	w.trimQueue()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Writer) trimQueue() {
+	capacity := w.cfg.QueueCapacity
+	if capacity <= 0 {
+		capacity = defaultQueueCapacity
+	}
+
+	if n := len(w.queue) - capacity; n > 0 {
+		log.Warnf("Remote-write queue is full, dropping %d scrape result(s)", n)
+		w.queue = w.queue[n:]
+		w.dropped += uint64(n)
+	}
+}
+
+// Run sends the queued results until ctx is done
+func (w *Writer) Run(ctx context.Context) {
+	for {
+		req := w.next()
+		if req == nil {
+			select {
+			case <-w.notify:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := w.sendWithRetries(ctx, req); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Errorf("Dropping scrape result: %v", err)
+			w.mu.Lock()
+			w.dropped++
+			w.mu.Unlock()
+		}
+	}
+}
+
+// next removes the oldest result from the queue
+func (w *Writer) next() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.queue) == 0 {
+		return nil
+	}
+
+	req := w.queue[0]
+	w.queue = w.queue[1:]
+	return req
+}
+
+func (w *Writer) sendWithRetries(ctx context.Context, req []byte) error {
+	w.mu.Lock()
+	cfg := w.cfg
+	w.mu.Unlock()
+
+	maxRetries := cfg.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	backoff := durationOrDefault(cfg.MinBackoff, defaultMinBackoff)
+	maxBackoff := durationOrDefault(cfg.MaxBackoff, defaultMaxBackoff)
+
+	for attempt := 0; ; attempt++ {
+		err := w.send(ctx, cfg, req)
+		if err == nil {
+			w.mu.Lock()
+			w.sent++
+			w.lastSuccess = time.Now()
+			w.mu.Unlock()
+			return nil
+		}
+
+		w.mu.Lock()
+		w.failed++
+		w.mu.Unlock()
+
+		var re *recoverableError
+		if !errors.As(err, &re) || attempt >= maxRetries {
+			return err
+		}
+
+		log.Warnf("Remote-write request failed (attempt %d/%d), retrying in %s: %v", attempt+1, maxRetries+1, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// recoverableError is an error of a request which should be retried
+type recoverableError struct {
+	err error
+}
+
+func (e *recoverableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *recoverableError) Unwrap() error {
+	return e.err
+}
+
+func (w *Writer) send(ctx context.Context, cfg *config.RemoteWriteConfig, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, durationOrDefault(cfg.RemoteTimeout, defaultRemoteTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	err = setAuth(req, cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return &recoverableError{err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("unexpected status %s from %s: %s", resp.Status, cfg.URL, strings.TrimSpace(string(msg)))
+
+	// server errors and rate limiting are temporary, other client errors are not
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return &recoverableError{err}
+	}
+
+	return err
+}
+
+func setAuth(req *http.Request, cfg *config.RemoteWriteConfig) error {
+	if cfg.BasicAuth != nil {
+		password, err := readSecret(cfg.BasicAuth.Password, cfg.BasicAuth.PasswordFile)
+		if err != nil {
+			return err
+		}
+
+		req.SetBasicAuth(cfg.BasicAuth.Username, password)
+		return nil
+	}
+
+	token, err := readSecret(cfg.BearerToken, cfg.BearerTokenFile)
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return nil
+}
+
+// readSecret returns the secret or the content of the file (read on every request, so rotated secrets are used)
+func readSecret(secret, file string) (string, error) {
+	if file == "" {
+		return secret, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret file: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+
+	return def
+}
+
+// Describe implements prometheus.Collector interface
+func (w *Writer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sentRequestsDesc
+	ch <- failedRequestsDesc
+	ch <- droppedRequestsDesc
+	ch <- queueLengthDesc
+	ch <- lastSuccessDesc
+}
+
+// Collect implements prometheus.Collector interface
+func (w *Writer) Collect(ch chan<- prometheus.Metric) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(sentRequestsDesc, prometheus.CounterValue, float64(w.sent))
+	ch <- prometheus.MustNewConstMetric(failedRequestsDesc, prometheus.CounterValue, float64(w.failed))
+	ch <- prometheus.MustNewConstMetric(droppedRequestsDesc, prometheus.CounterValue, float64(w.dropped))
+	ch <- prometheus.MustNewConstMetric(queueLengthDesc, prometheus.GaugeValue, float64(len(w.queue)))
+
+	if !w.lastSuccess.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, float64(w.lastSuccess.Unix()))
+	}
+}
@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: MIT
+
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+)
+
+type sample struct {
+	labels    map[string]string
+	value     float64
+	timestamp int64
+}
+
+// receiver is a stand-in for a remote-write endpoint recording the received requests
+type receiver struct {
+	t      *testing.T
+	mu     sync.Mutex
+	status []int
+	reqs   []*http.Request
+	series [][]sample
+	srv    *httptest.Server
+}
+
+func newReceiver(t *testing.T, status ...int) *receiver {
+	r := &receiver{t: t, status: status}
+	r.srv = httptest.NewServer(r)
+	t.Cleanup(r.srv.Close)
+
+	return r
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reqs = append(r.reqs, req)
+
+	status := http.StatusNoContent
+	if len(r.status) > 0 {
+		status = r.status[0]
+		r.status = r.status[1:]
+	}
+
+	if status/100 == 2 {
+		b, err := io.ReadAll(req.Body)
+		require.NoError(r.t, err)
+
+		b, err = snappy.Decode(nil, b)
+		require.NoError(r.t, err)
+
+		r.series = append(r.series, decode(r.t, b))
+	}
+
+	w.WriteHeader(status)
+}
+
+func (r *receiver) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.reqs)
+}
+
+// decode parses a WriteRequest
+func decode(t *testing.T, b []byte) []sample {
+	var samples []sample
+
+	for _, ts := range fields(t, b, writeRequestTimeseries) {
+		s := sample{labels: make(map[string]string)}
+		for _, l := range fields(t, ts, timeSeriesLabels) {
+			name := fields(t, l, labelName)
+			value := fields(t, l, labelValue)
+			require.Len(t, name, 1)
+			require.Len(t, value, 1)
+			s.labels[string(name[0])] = string(value[0])
+		}
+
+		smpls := fields(t, ts, timeSeriesSamples)
+		require.Len(t, smpls, 1)
+		smpl := smpls[0]
+		for len(smpl) > 0 {
+			num, typ, n := protowire.ConsumeTag(smpl)
+			require.GreaterOrEqual(t, n, 0)
+			smpl = smpl[n:]
+
+			switch {
+			case num == sampleValue && typ == protowire.Fixed64Type:
+				v, n := protowire.ConsumeFixed64(smpl)
+				s.value = math.Float64frombits(v)
+				smpl = smpl[n:]
+			case num == sampleTimestamp && typ == protowire.VarintType:
+				v, n := protowire.ConsumeVarint(smpl)
+				s.timestamp = int64(v)
+				smpl = smpl[n:]
+			default:
+				t.Fatalf("unexpected field %d in sample", num)
+			}
+		}
+
+		samples = append(samples, s)
+	}
+
+	return samples
+}
+
+// fields returns the values of all length-delimited fields with the number num
+func fields(t *testing.T, b []byte, num protowire.Number) [][]byte {
+	var res [][]byte
+
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, l, 0)
+		b = b[l:]
+
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		require.GreaterOrEqual(t, l, 0)
+		if n == num {
+			v, _ := protowire.ConsumeBytes(b)
+			res = append(res, v)
+		}
+		b = b[l:]
+	}
+
+	return res
+}
+
+func testMetrics() []*dto.MetricFamily {
+	reg := prometheus.NewRegistry()
+
+	up := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "junos_up", Help: "Scrape of target was successful"}, []string{"target"})
+	up.WithLabelValues("router1").Set(1)
+
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "junos_collect_duration_seconds", Help: "Duration of a scrape", Buckets: []float64{1}})
+	duration.Observe(0.5)
+
+	reg.MustRegister(up, duration)
+
+	mfs, _ := reg.Gather()
+	return mfs
+}
+
+func run(t *testing.T, w *Writer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestWriter(t *testing.T) {
+	r := newReceiver(t)
+	w := New(&config.RemoteWriteConfig{
+		URL:            r.srv.URL,
+		ExternalLabels: map[string]string{"site": "ber1", "target": "default"},
+	})
+	run(t, w)
+
+	ts := time.UnixMilli(1700000000000)
+	w.Enqueue(testMetrics(), ts)
+
+	require.Eventually(t, func() bool { return r.requests() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req := r.reqs[0]
+	assert.Equal(t, "snappy", req.Header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+	assert.Equal(t, "0.1.0", req.Header.Get("X-Prometheus-Remote-Write-Version"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	expected := []sample{
+		{labels: map[string]string{"__name__": "junos_collect_duration_seconds_bucket", "le": "1", "site": "ber1", "target": "default"}, value: 1, timestamp: ts.UnixMilli()},
+		{labels: map[string]string{"__name__": "junos_collect_duration_seconds_bucket", "le": "+Inf", "site": "ber1", "target": "default"}, value: 1, timestamp: ts.UnixMilli()},
+		{labels: map[string]string{"__name__": "junos_collect_duration_seconds_sum", "site": "ber1", "target": "default"}, value: 0.5, timestamp: ts.UnixMilli()},
+		{labels: map[string]string{"__name__": "junos_collect_duration_seconds_count", "site": "ber1", "target": "default"}, value: 1, timestamp: ts.UnixMilli()},
+		{labels: map[string]string{"__name__": "junos_up", "site": "ber1", "target": "router1"}, value: 1, timestamp: ts.UnixMilli()},
+	}
+	// the external label target is only added to series without a target label
+	require.Len(t, r.series, 1)
+	assert.Equal(t, expected, r.series[0])
+}
+
+func TestWriterAuth(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("s3cr3t\n"), 0600))
+
+	tests := []struct {
+		name     string
+		cfg      config.RemoteWriteConfig
+		expected string
+	}{
+		{
+			name:     "basic auth",
+			cfg:      config.RemoteWriteConfig{BasicAuth: &config.BasicAuthConfig{Username: "user", Password: "pass"}},
+			expected: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:     "basic auth password file",
+			cfg:      config.RemoteWriteConfig{BasicAuth: &config.BasicAuthConfig{Username: "user", PasswordFile: file}},
+			expected: "Basic dXNlcjpzM2NyM3Q=",
+		},
+		{
+			name:     "bearer token",
+			cfg:      config.RemoteWriteConfig{BearerToken: "token"},
+			expected: "Bearer token",
+		},
+		{
+			name:     "bearer token file",
+			cfg:      config.RemoteWriteConfig{BearerTokenFile: file},
+			expected: "Bearer s3cr3t",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newReceiver(t)
+			test.cfg.URL = r.srv.URL
+
+			w := New(&test.cfg)
+			require.NoError(t, w.sendWithRetries(context.Background(), snappy.Encode(nil, nil)))
+			require.Equal(t, 1, r.requests())
+			assert.Equal(t, test.expected, r.reqs[0].Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestWriterRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           []int
+		expectedRequests int
+		expectedError    bool
+	}{
+		{
+			name:             "server error is retried",
+			status:           []int{http.StatusInternalServerError, http.StatusServiceUnavailable},
+			expectedRequests: 3,
+		},
+		{
+			name:             "rate limit is retried",
+			status:           []int{http.StatusTooManyRequests},
+			expectedRequests: 2,
+		},
+		{
+			name:             "client error is not retried",
+			status:           []int{http.StatusBadRequest},
+			expectedRequests: 1,
+			expectedError:    true,
+		},
+		{
+			name:             "gives up after max retries",
+			status:           []int{500, 500, 500, 500},
+			expectedRequests: 3,
+			expectedError:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newReceiver(t, test.status...)
+			w := New(&config.RemoteWriteConfig{
+				URL:        r.srv.URL,
+				MaxRetries: 2,
+				MinBackoff: time.Millisecond,
+				MaxBackoff: time.Millisecond,
+			})
+
+			err := w.sendWithRetries(context.Background(), snappy.Encode(nil, nil))
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedRequests, r.requests())
+		})
+	}
+}
+
+func TestWriterQueue(t *testing.T) {
+	r := newReceiver(t)
+	w := New(&config.RemoteWriteConfig{
+		URL:           r.srv.URL,
+		QueueCapacity: 2,
+	})
+
+	for i := 1; i <= 3; i++ {
+		w.Enqueue(testMetrics(), time.UnixMilli(int64(i)))
+	}
+
+	expected := `
+# HELP junos_exporter_remote_write_dropped_requests_total Number of scrape results dropped because the queue was full or all retries failed
+# TYPE junos_exporter_remote_write_dropped_requests_total counter
+junos_exporter_remote_write_dropped_requests_total 1
+# HELP junos_exporter_remote_write_queue_length Number of scrape results waiting to be sent
+# TYPE junos_exporter_remote_write_queue_length gauge
+junos_exporter_remote_write_queue_length 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(w, strings.NewReader(expected),
+		"junos_exporter_remote_write_dropped_requests_total", "junos_exporter_remote_write_queue_length"))
+
+	run(t, w)
+	require.Eventually(t, func() bool { return r.requests() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// the oldest result was dropped
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	require.Len(t, r.series, 2)
+	assert.Equal(t, int64(2), r.series[0][0].timestamp)
+	assert.Equal(t, int64(3), r.series[1][0].timestamp)
+}
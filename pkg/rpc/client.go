@@ -3,9 +3,11 @@
 package rpc
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/czerwonk/junos_exporter/pkg/connector"
 )
@@ -33,12 +35,20 @@ func WithLicenseInformation() ClientOption {
 	}
 }
 
+// WithMetrics records the duration, reply size and errors of the commands in m
+func WithMetrics(m *Metrics) ClientOption {
+	return func(cl *Client) {
+		cl.metrics = m
+	}
+}
+
 // Client sends commands to JunOS and parses results
 type Client struct {
 	conn      *connector.SSHConnection
 	debug     bool
 	satellite bool
 	license   bool
+	metrics   *Metrics
 }
 
 // NewClient creates a new client to connect to
@@ -54,18 +64,36 @@ func NewClient(ssh *connector.SSHConnection, opts ...ClientOption) *Client {
 
 // RunCommandAndParse runs a command on JunOS and unmarshals the XML result
 func (c *Client) RunCommandAndParse(cmd string, obj any) error {
-	return c.RunCommandAndParseWithParser(cmd, func(b []byte) error {
+	return c.RunCommandAndParseContext(context.Background(), cmd, obj)
+}
+
+// RunCommandAndParseContext runs a command on JunOS and unmarshals the XML result. The command is aborted when ctx is done.
+func (c *Client) RunCommandAndParseContext(ctx context.Context, cmd string, obj any) error {
+	return c.RunCommandAndParseWithParserContext(ctx, cmd, func(b []byte) error {
 		return xml.Unmarshal(b, obj)
 	})
 }
 
 // RunCommandAndParseWithParser runs a command on JunOS and unmarshals the XML result using the specified parser function
 func (c *Client) RunCommandAndParseWithParser(cmd string, parser Parser) error {
+	return c.RunCommandAndParseWithParserContext(context.Background(), cmd, parser)
+}
+
+// RunCommandAndParseWithParserContext runs a command on JunOS and unmarshals the XML result using the specified
+// parser function. The command is aborted when ctx is done.
+func (c *Client) RunCommandAndParseWithParserContext(ctx context.Context, cmd string, parser Parser) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if c.debug {
 		log.Printf("Running command on %s: %s\n", c.conn.Host(), cmd)
 	}
 
-	b, err := c.conn.RunCommand(fmt.Sprintf("%s | display xml", cmd))
+	start := time.Now()
+	b, err := c.conn.RunCommandContext(ctx, fmt.Sprintf("%s | display xml", cmd))
+	c.metrics.observe(c.conn.Host(), cmd, time.Since(start), len(b), err)
+
 	if err != nil {
 		return err
 	}
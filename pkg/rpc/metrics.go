@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+
+package rpc
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// argumentKeywords are keywords of commands followed by a value (e.g. the name of a routing instance)
+var argumentKeywords = map[string]bool{
+	"instance":                  true,
+	"logical-system":            true,
+	"table":                     true,
+	"regex":                     true,
+	"services-redundancy-group": true,
+	"fpc-slot":                  true,
+	"pic-slot":                  true,
+}
+
+// modifierKeywords are keywords which can follow an argument keyword without being its value
+var modifierKeywords = map[string]bool{
+	"brief":     true,
+	"detail":    true,
+	"extensive": true,
+	"summary":   true,
+	"all":       true,
+}
+
+var keywordRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Metrics are metrics of the commands run by clients
+type Metrics struct {
+	duration   *prometheus.HistogramVec
+	replyBytes *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+}
+
+// NewMetrics creates the metrics of the commands run by clients. The metrics
+// have to be registered to be exposed, clients record them when created with WithMetrics.
+func NewMetrics() *Metrics {
+	l := []string{"target", "command"}
+
+	return &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "junos_exporter_rpc_duration_seconds",
+			Help:    "Duration of commands run on the device (including transfer of the reply)",
+			Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, l),
+		replyBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_rpc_reply_bytes_total",
+			Help: "Number of bytes of the replies of commands run on the device",
+		}, l),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_exporter_rpc_errors_total",
+			Help: "Number of commands which could not be run on the device",
+		}, l),
+	}
+}
+
+// Describe implements prometheus.Collector interface
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.duration.Describe(ch)
+	m.replyBytes.Describe(ch)
+	m.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector interface
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.duration.Collect(ch)
+	m.replyBytes.Collect(ch)
+	m.errors.Collect(ch)
+}
+
+func (m *Metrics) observe(target, cmd string, d time.Duration, replyBytes int, err error) {
+	if m == nil {
+		return
+	}
+
+	cmd = NormalizeCommand(cmd)
+
+	m.duration.WithLabelValues(target, cmd).Observe(d.Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(target, cmd).Inc()
+		return
+	}
+
+	m.replyBytes.WithLabelValues(target, cmd).Add(float64(replyBytes))
+}
+
+// NormalizeCommand removes values (e.g. names of routing instances, interfaces or regular expressions)
+// and pipes from a command, e.g. to limit the cardinality of the command label
+func NormalizeCommand(cmd string) string {
+	cmd, _, _ = strings.Cut(cmd, "|")
+
+	tokens := strings.Fields(cmd)
+	for i, t := range tokens {
+		if !keywordRegex.MatchString(t) || (i > 0 && argumentKeywords[tokens[i-1]] && !modifierKeywords[t]) {
+			tokens[i] = "*"
+		}
+	}
+
+	return strings.Join(tokens, " ")
+}
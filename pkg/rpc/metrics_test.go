@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+
+package rpc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCommand(t *testing.T) {
+	tests := []struct {
+		cmd      string
+		expected string
+	}{
+		{
+			cmd:      "show bgp neighbor",
+			expected: "show bgp neighbor",
+		},
+		{
+			cmd:      "show system statistics icmp6",
+			expected: "show system statistics icmp6",
+		},
+		{
+			cmd:      "show bgp neighbor instance customer1 logical-system ls1",
+			expected: "show bgp neighbor instance * logical-system *",
+		},
+		{
+			cmd:      "show route instance detail",
+			expected: "show route instance detail",
+		},
+		{
+			cmd:      "show route summary table VRF1.",
+			expected: "show route summary table *",
+		},
+		{
+			cmd:      "show interfaces extensive ge-0/0/[0-3]",
+			expected: "show interfaces extensive *",
+		},
+		{
+			cmd:      "show firewall filter regex .*",
+			expected: "show firewall filter regex *",
+		},
+		{
+			cmd:      "show chassis pic fpc-slot 0 pic-slot 1",
+			expected: "show chassis pic fpc-slot * pic-slot *",
+		},
+		{
+			cmd:      "show ntp status | display xml",
+			expected: "show ntp status",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cmd, func(t *testing.T) {
+			assert.Equal(t, test.expected, NormalizeCommand(test.cmd))
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	m.observe("router1", "show bgp neighbor instance a", 2*time.Second, 100, nil)
+	m.observe("router1", "show bgp neighbor instance b", time.Second, 50, nil)
+	m.observe("router1", "show bgp neighbor instance c", time.Second, 0, errors.New("failed"))
+
+	expected := `
+# HELP junos_exporter_rpc_errors_total Number of commands which could not be run on the device
+# TYPE junos_exporter_rpc_errors_total counter
+junos_exporter_rpc_errors_total{command="show bgp neighbor instance *",target="router1"} 1
+# HELP junos_exporter_rpc_reply_bytes_total Number of bytes of the replies of commands run on the device
+# TYPE junos_exporter_rpc_reply_bytes_total counter
+junos_exporter_rpc_reply_bytes_total{command="show bgp neighbor instance *",target="router1"} 150
+`
+	assert.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(expected),
+		"junos_exporter_rpc_errors_total", "junos_exporter_rpc_reply_bytes_total"))
+	assert.Equal(t, 1, testutil.CollectAndCount(m, "junos_exporter_rpc_duration_seconds"))
+
+	var nilMetrics *Metrics
+	nilMetrics.observe("router1", "show version", time.Second, 0, nil)
+}
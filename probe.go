@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+)
+
+const (
+	probeFormatText = "text"
+	probeFormatJSON = "json"
+)
+
+// runProbe implements the probe subcommand: it scrapes a single target once
+// and writes the metrics or a summary to stdout. All flags of the exporter
+// can be used. It returns the exit code (1 if the scrape failed, 2 on usage errors).
+func runProbe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("probe", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	target := fs.String("target", "", "Target to scrape (required)")
+	collect := fs.String("collect", "", "Comma-separated list of features whose collectors are run (default: features enabled for the target)")
+	format := fs.String("format", probeFormatText, "Output format: text (exposition format) or json (summary of the collectors)")
+	timeout := fs.Duration("timeout", time.Minute, "Time budget of the scrape (0 = no deadline)")
+	fs.StringVar(configFile, "config", *configFile, "Path to config file (same as -config.file)")
+
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: junos_exporter probe -target <target> [-collect bgp,interfaces] [-config <file>] [-format text|json] [ ... ]\n\nParameters:")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		return 2
+	}
+
+	if *target == "" {
+		fmt.Fprintln(stderr, "-target is required")
+		return 2
+	}
+
+	if *format != probeFormatText && *format != probeFormatJSON {
+		fmt.Fprintf(stderr, "invalid format %q (valid: %s, %s)\n", *format, probeFormatText, probeFormatJSON)
+		return 2
+	}
+
+	features, err := probeFeatures(*collect)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if err := resolveSSHSecrets(); err != nil {
+		fmt.Fprintf(stderr, "could not resolve ssh credentials: %v\n", err)
+		return 2
+	}
+
+	if *configFile == "" {
+		*sshHosts = *target
+	}
+
+	c, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	opts := exporterOptions()
+	if len(features) > 0 {
+		opts = append(opts, exporter.WithFeatures(features...))
+	}
+
+	e, err := exporter.New(c, opts...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	defer e.Close()
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	res, err := e.Probe(ctx, *target)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *format == probeFormatJSON {
+		err = res.WriteJSON(stdout)
+	} else {
+		err = res.WriteText(stdout, stderr)
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if res.Failed() {
+		return 1
+	}
+
+	return 0
+}
+
+// probeFeatures parses the list of features of the collect flag
+func probeFeatures(collect string) ([]string, error) {
+	features := make([]string, 0)
+	for f := range strings.SplitSeq(collect, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		if _, found := collector.RegistrationForFeature(f); !found {
+			return nil, fmt.Errorf("unknown feature %q", f)
+		}
+
+		features = append(features, f)
+	}
+
+	return features, nil
+}
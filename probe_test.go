@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+)
+
+func TestProbe(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(cfg, []byte(`
+devices:
+  - host: 127.0.0.1:1
+    password: secret
+    features:
+      bgp: true
+`), 0o600))
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectedCode    int
+		expectedStderr  string
+		expectedSummary *exporter.ProbeSummary
+	}{
+		{
+			name:         "help",
+			args:         []string{"-h"},
+			expectedCode: 0,
+		},
+		{
+			name:           "target missing",
+			args:           []string{"--config", cfg},
+			expectedCode:   2,
+			expectedStderr: "-target is required",
+		},
+		{
+			name:           "unknown feature",
+			args:           []string{"--target", "127.0.0.1:1", "--collect", "bgp,subscribers"},
+			expectedCode:   2,
+			expectedStderr: `unknown feature "subscribers"`,
+		},
+		{
+			name:           "invalid format",
+			args:           []string{"--target", "127.0.0.1:1", "--format", "yaml"},
+			expectedCode:   2,
+			expectedStderr: `invalid format "yaml"`,
+		},
+		{
+			name:           "config file missing",
+			args:           []string{"--target", "127.0.0.1:1", "--config", cfg + ".missing"},
+			expectedCode:   2,
+			expectedStderr: "no such file or directory",
+		},
+		{
+			name:           "unknown target",
+			args:           []string{"--target", "router2", "--config", cfg},
+			expectedCode:   1,
+			expectedStderr: "unknown target: router2",
+		},
+		{
+			name:         "unreachable target",
+			args:         []string{"--target", "127.0.0.1:1", "--config", cfg, "--collect", "bfd", "--format", "json"},
+			expectedCode: 1,
+			expectedSummary: &exporter.ProbeSummary{
+				Target:       "127.0.0.1:1",
+				ConnectError: "could not open tcp connection",
+				Failed:       true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			*configFile = ""
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			code := runProbe(test.args, stdout, stderr)
+
+			assert.Equal(t, test.expectedCode, code, "exit code (stderr: %s)", stderr)
+			assert.Contains(t, stderr.String(), test.expectedStderr)
+
+			if test.expectedSummary == nil {
+				assert.Empty(t, stdout.String())
+				return
+			}
+
+			var s exporter.ProbeSummary
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &s))
+			assert.Equal(t, test.expectedSummary.Target, s.Target)
+			assert.False(t, s.Connected)
+			assert.Contains(t, s.ConnectError, test.expectedSummary.ConnectError)
+			assert.Equal(t, test.expectedSummary.Failed, s.Failed)
+			assert.Empty(t, s.Collectors, "collectors do not run without connection")
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	configReloadSuccessful = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "junos_exporter_config_last_reload_successful",
+		Help: "Whether the last configuration reload attempt was successful",
+	})
+	configReloadTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "junos_exporter_config_last_reload_success_timestamp_seconds",
+		Help: "Timestamp of the last successful configuration reload",
+	})
+)
+
+// reinitialize reloads the config. In contrast to the initial load, the
+// connection manager is kept: connections to devices which are still
+// configured with unchanged credentials are reused, connections to removed
+// devices or devices with changed credentials are closed. New devices are
+// connected on their first scrape.
+func (a *app) reinitialize() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := a.reload()
+	recordConfigReload(err)
+
+	return err
+}
+
+func (a *app) reload() error {
+	base, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	err = a.exp.Reload(a.configWithInventories(base))
+	if err != nil {
+		return err
+	}
+
+	a.baseCfg = base
+	return nil
+}
+
+func recordConfigReload(err error) {
+	if err != nil {
+		configReloadSuccessful.Set(0)
+		return
+	}
+
+	configReloadSuccessful.Set(1)
+	configReloadTimestamp.SetToCurrentTime()
+}
+
+// watchConfigFile reloads the config whenever the content of the file changes
+func (a *app) watchConfigFile(ctx context.Context, path string, interval time.Duration) {
+	log.Infof("Watching config file %s for changes (interval: %s)", path, interval)
+
+	last, err := fileHash(path)
+	if err != nil {
+		log.Errorf("Could not read config file %s: %s", path, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h, err := fileHash(path)
+			if err != nil {
+				log.Errorf("Could not read config file %s: %s", path, err)
+				continue
+			}
+
+			if h == last {
+				continue
+			}
+
+			last = h
+			log.Infoln("Config file changed, reloading")
+			if err := a.reinitialize(); err != nil {
+				log.Errorf("Error reloading config: %s", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func fileHash(path string) ([sha256.Size]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(b), nil
+}
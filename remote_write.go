@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/remotewrite"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runRemoteWrite scrapes all devices in the interval of the remote_write
+// section of the config and pushes the metrics to the remote-write endpoint.
+// Changes of the section are applied on reload.
+func (a *app) runRemoteWrite(ctx context.Context) {
+	var w *remotewrite.Writer
+	interval := remotewrite.DefaultScrapeInterval
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if cfg := a.remoteWriteConfig(); cfg != nil {
+			if w == nil {
+				log.Infof("Pushing metrics to %s", cfg.URL)
+				w = remotewrite.New(cfg)
+				go w.Run(ctx)
+			} else {
+				w.SetConfig(cfg)
+			}
+
+			if i := w.ScrapeInterval(); i != interval {
+				interval = i
+				ticker.Reset(interval)
+			}
+
+			a.pushMetrics(ctx, w, interval)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *app) remoteWriteConfig() *config.RemoteWriteConfig {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.baseCfg.RemoteWrite
+}
+
+// pushMetrics scrapes all devices and adds the result to the queue of the writer.
+// The scrape is abandoned after timeout, so it does not overlap with the next one.
+func (a *app) pushMetrics(ctx context.Context, w *remotewrite.Writer, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ts := time.Now()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(w)
+
+	mfs, err := prometheus.Gatherers{
+		prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+			return a.exp.Gather(ctx)
+		}),
+		reg,
+	}.Gather()
+	if err != nil {
+		log.Errorf("Error while scraping devices for remote-write: %v", err)
+	}
+
+	w.Enqueue(mfs, ts)
+}
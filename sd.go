@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// sdTargetGroup is a target group in the format of the Prometheus HTTP service discovery
+type sdTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// handleServiceDiscoveryRequest returns the configured devices in the Prometheus http_sd_config format.
+// The devices can be filtered by group (parameter group, multiple groups are or-ed).
+// Host patterns are left out, since their targets are not known in advance.
+func (a *app) handleServiceDiscoveryRequest(w http.ResponseWriter, r *http.Request) {
+	cfg, devices := a.exp.Snapshot()
+
+	groups := r.URL.Query()["group"]
+	module := r.URL.Query().Get("module")
+
+	tgs := make([]*sdTargetGroup, 0, len(devices))
+	for _, d := range devices {
+		dc := cfg.FindDeviceConfig(d.Host)
+		if len(groups) > 0 && (dc == nil || !slices.ContainsFunc(dc.Groups, func(g string) bool {
+			return slices.Contains(groups, g)
+		})) {
+			continue
+		}
+
+		labels := make(map[string]string)
+		if dc != nil {
+			maps.Copy(labels, dc.Labels)
+		}
+
+		labels["instance"] = d.Host
+		labels["__param_target"] = d.Host
+		labels["__metrics_path__"] = *metricsPath
+		if module != "" {
+			labels["__param_module"] = module
+		}
+
+		tgs = append(tgs, &sdTargetGroup{
+			Targets: []string{r.Host},
+			Labels:  labels,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tgs)
+}
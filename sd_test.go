@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/czerwonk/junos_exporter/internal/config"
+	"github.com/czerwonk/junos_exporter/pkg/collector"
+	"github.com/czerwonk/junos_exporter/pkg/exporter"
+)
+
+func TestServiceDiscovery(t *testing.T) {
+	c, err := config.Load(bytes.NewReader([]byte(`
+password: secret
+groups:
+  pe: {}
+  p: {}
+devices:
+  - name: fra1-pe1
+    address: 10.12.0.5
+    groups: [pe]
+    labels:
+      site: fra1
+  - host: fra1-p1
+    groups: [p]
+  - host: lab-.*
+    host_pattern: true
+`)), false, collector.DefaultRegistry)
+	assert.NoError(t, err)
+
+	e, err := exporter.New(c)
+	assert.NoError(t, err)
+	a := newApp(e, c)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []*sdTargetGroup
+	}{
+		{
+			name:  "all devices",
+			query: "",
+			expected: []*sdTargetGroup{
+				{
+					Targets: []string{"exporter:9326"},
+					Labels: map[string]string{
+						"__metrics_path__": "/metrics",
+						"__param_target":   "fra1-pe1",
+						"instance":         "fra1-pe1",
+						"site":             "fra1",
+					},
+				},
+				{
+					Targets: []string{"exporter:9326"},
+					Labels: map[string]string{
+						"__metrics_path__": "/metrics",
+						"__param_target":   "fra1-p1",
+						"instance":         "fra1-p1",
+					},
+				},
+			},
+		},
+		{
+			name:  "filtered by group with module",
+			query: "?group=p&module=junos",
+			expected: []*sdTargetGroup{
+				{
+					Targets: []string{"exporter:9326"},
+					Labels: map[string]string{
+						"__metrics_path__": "/metrics",
+						"__param_target":   "fra1-p1",
+						"__param_module":   "junos",
+						"instance":         "fra1-p1",
+					},
+				},
+			},
+		},
+		{
+			name:     "unknown group",
+			query:    "?group=rr",
+			expected: []*sdTargetGroup{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sd"+test.query, nil)
+			req.Host = "exporter:9326"
+			w := httptest.NewRecorder()
+			a.handleServiceDiscoveryRequest(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var tgs []*sdTargetGroup
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&tgs))
+			assert.Equal(t, test.expected, tgs)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/czerwonk/junos_exporter/pkg/connector"
+	"github.com/czerwonk/junos_exporter/pkg/rpc"
+)
+
+var (
+	rpcMetrics = rpc.NewMetrics()
+	sshMetrics = connector.NewMetrics()
+)
+
+// selfMetricsHandler returns the handler of the metrics of the exporter itself (RPCs, SSH connections, config reloads,
+// device files, HTTP inventories, Go runtime and process). The metrics are kept apart from the metrics of the devices,
+// so they are not part of every scrape of a target.
+func selfMetricsHandler() http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		prometheus.NewBuildInfoCollector(),
+		rpcMetrics,
+		sshMetrics,
+		configReloadSuccessful,
+		configReloadTimestamp,
+		deviceFileLoadSuccessful,
+		deviceFileDevices,
+		httpInventoryFetchSuccessful,
+		httpInventoryDevices,
+	)
+
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
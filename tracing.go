@@ -8,8 +8,6 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
@@ -17,16 +15,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
-	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
-
-	"github.com/czerwonk/junos_exporter/pkg/connector"
-	"github.com/czerwonk/junos_exporter/pkg/rpc"
-)
-
-var tracer = otel.GetTracerProvider().Tracer(
-	"github.com/czerwonk/junos_exporter",
-	trace.WithSchemaURL(semconv.SchemaURL),
 )
 
 func initTracing(ctx context.Context) (func(), error) {
@@ -108,48 +97,3 @@ func resourceDefinition() *resource.Resource {
 		semconv.ServiceVersionKey.String(version),
 	)
 }
-
-type clientTracingAdapter struct {
-	cl  *rpc.Client
-	ctx context.Context
-}
-
-// RunCommandAndParse implements RunCommandAndParse of the collector.Client interface
-func (cta *clientTracingAdapter) RunCommandAndParse(cmd string, obj any) error {
-	return cta.cl.RunCommandAndParse(cmd, obj)
-}
-
-// RunCommandAndParseWithParser implements RunCommandAndParseWithParser of the collector.Client interface
-func (cta *clientTracingAdapter) RunCommandAndParseWithParser(cmd string, parser rpc.Parser) error {
-	_, span := tracer.Start(cta.ctx, "RunCommandAndParseWithParser", trace.WithAttributes(
-		attribute.String("command", cmd),
-	))
-	defer span.End()
-
-	err := cta.cl.RunCommandAndParseWithParser(cmd, parser)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-	}
-
-	return err
-}
-
-// IsSatelliteEnabled implements IsSatelliteEnabled of the collector.Client interface
-func (cta *clientTracingAdapter) IsSatelliteEnabled() bool {
-	return cta.cl.IsSatelliteEnabled()
-}
-
-func (cta *clientTracingAdapter) IsScrapingLicenseEnabled() bool {
-	return cta.cl.IsScrapingLicenseEnabled()
-}
-
-// Device implements Device of the collector.Client interface
-func (cta *clientTracingAdapter) Device() *connector.Device {
-	return cta.cl.Device()
-}
-
-// Context implements Context of the collector.Client interface
-func (cta *clientTracingAdapter) Context() context.Context {
-	return cta.ctx
-}